@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -32,3 +34,38 @@ func IsConditionTrue(va *VariantAutoscaling, conditionType string) bool {
 func IsConditionFalse(va *VariantAutoscaling, conditionType string) bool {
 	return meta.IsStatusConditionFalse(va.Status.Conditions, conditionType)
 }
+
+// readinessConditions are the conditions that must all be True for a VariantAutoscaling to be Ready,
+// in the order they are evaluated by the controller
+var readinessConditions = []string{
+	TypeConfigurationValid,
+	TypeSLOResolved,
+	TypeTargetResolved,
+	TypeMetricsAvailable,
+	TypeOptimizationReady,
+}
+
+// SetReadyCondition sets the Ready condition from the readiness conditions: False with the reason and message of
+// the first one that is False, Unknown if any is not yet set, and True otherwise
+func SetReadyCondition(va *VariantAutoscaling) {
+	unknown := ""
+	for _, conditionType := range readinessConditions {
+		condition := GetCondition(va, conditionType)
+		if condition == nil || condition.Status == metav1.ConditionUnknown {
+			if unknown == "" {
+				unknown = conditionType
+			}
+			continue
+		}
+		if condition.Status == metav1.ConditionFalse {
+			SetCondition(va, TypeReady, metav1.ConditionFalse, condition.Reason, condition.Message)
+			return
+		}
+	}
+	if unknown != "" {
+		SetCondition(va, TypeReady, metav1.ConditionUnknown, ReasonNotReady,
+			fmt.Sprintf("Waiting for condition %s", unknown))
+		return
+	}
+	SetCondition(va, TypeReady, metav1.ConditionTrue, ReasonReady, "Variant is being optimized")
+}
@@ -18,6 +18,11 @@ type VariantAutoscalingSpec struct {
 	// ModelProfile provides resource and performance characteristics for the model variant.
 	// +kubebuilder:validation:Required
 	ModelProfile ModelProfile `json:"modelProfile"`
+
+	// StartupLatency is the time a new replica takes to load the model and become ready.
+	// If not set, it is learned from the readiness timestamps of the variant's pods.
+	// +optional
+	StartupLatency *metav1.Duration `json:"startupLatency,omitempty"`
 }
 
 // ConfigMapKeyRef references a specific key within a ConfigMap.
@@ -80,6 +85,10 @@ type VariantAutoscalingStatus struct {
 	// Actuation provides details about the actuation process and its current status.
 	Actuation ActuationStatus `json:"actuation,omitempty"`
 
+	// Recommendation describes how the optimizer sized the variant in its last run.
+	// +optional
+	Recommendation *Recommendation `json:"recommendation,omitempty"`
+
 	// Conditions represent the latest available observations of the VariantAutoscaling's state
 	// +optional
 	// +patchMergeKey=type
@@ -90,10 +99,12 @@ type VariantAutoscalingStatus struct {
 }
 
 // Allocation describes the current resource allocation for a model variant.
+// Its string fields are unset until metrics have first been collected for the variant.
 type Allocation struct {
 	// Accelerator is the type of accelerator currently allocated.
 	// +kubebuilder:validation:MinLength=1
-	Accelerator string `json:"accelerator"`
+	// +optional
+	Accelerator string `json:"accelerator,omitempty"`
 
 	// NumReplicas is the number of replicas currently allocated.
 	// +kubebuilder:validation:Minimum=0
@@ -105,18 +116,22 @@ type Allocation struct {
 
 	// VariantCost is the cost associated with the current variant allocation.
 	// +kubebuilder:validation:Pattern=`^\d+(\.\d+)?$`
-	VariantCost string `json:"variantCost"`
+	// +optional
+	VariantCost string `json:"variantCost,omitempty"`
 
 	// ITLAverage is the average inter token latency for the current allocation.
 	// +kubebuilder:validation:Pattern=`^\d+(\.\d+)?$`
-	ITLAverage string `json:"itlAverage"`
+	// +optional
+	ITLAverage string `json:"itlAverage,omitempty"`
 
 	// TTFTAverage is the average time to first token for the current allocation
 	// +kubebuilder:validation:Pattern=`^\d+(\.\d+)?$`
-	TTFTAverage string `json:"ttftAverage"`
+	// +optional
+	TTFTAverage string `json:"ttftAverage,omitempty"`
 
 	// Load describes the workload characteristics for the current allocation.
-	Load LoadProfile `json:"load"`
+	// +optional
+	Load LoadProfile `json:"load,omitempty"`
 }
 
 // LoadProfile represents the configuration for workload characteristics,
@@ -125,29 +140,90 @@ type Allocation struct {
 // to allow flexible input formats.
 type LoadProfile struct {
 	// ArrivalRate is the rate of incoming requests in inference server.
-	ArrivalRate string `json:"arrivalRate"`
+	// +optional
+	ArrivalRate string `json:"arrivalRate,omitempty"`
 
 	// AvgInputTokens is the average number of input(prefill) tokens per request in inference server.
-	AvgInputTokens string `json:"avgInputTokens"`
+	// +optional
+	AvgInputTokens string `json:"avgInputTokens,omitempty"`
 
 	// AvgOutputTokens is the average number of output(decode) tokens per request in inference server.
-	AvgOutputTokens string `json:"avgOutputTokens"`
+	// +optional
+	AvgOutputTokens string `json:"avgOutputTokens,omitempty"`
 }
 
 // OptimizedAlloc describes the target optimized allocation for a model variant.
+// Its accelerator is unset until the variant has first been optimized.
 type OptimizedAlloc struct {
 	// LastRunTime is the timestamp of the last optimization run.
 	LastRunTime metav1.Time `json:"lastRunTime,omitempty"`
 
 	// Accelerator is the type of accelerator for the optimized allocation.
 	// +kubebuilder:validation:MinLength=2
-	Accelerator string `json:"accelerator"`
+	// +optional
+	Accelerator string `json:"accelerator,omitempty"`
 
 	// NumReplicas is the number of replicas for the optimized allocation.
 	// +kubebuilder:validation:Minimum=0
 	NumReplicas int `json:"numReplicas"`
 }
 
+// Recommendation describes the allocations evaluated by the optimizer for a model variant and the reason for its choice.
+type Recommendation struct {
+	// Reason explains why the recommended allocation was chosen, including any adjustment made by the controller.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Saturated indicates that the recommended allocation cannot serve the observed load.
+	Saturated bool `json:"saturated"`
+
+	// Recommended is the allocation chosen by the optimizer, with its predicted performance.
+	// +optional
+	Recommended *CandidateAllocation `json:"recommended,omitempty"`
+
+	// Candidates are the allocations evaluated by the optimizer, one per feasible accelerator.
+	// +optional
+	Candidates []CandidateAllocation `json:"candidates,omitempty"`
+}
+
+// CandidateAllocation describes an allocation evaluated by the optimizer and its predicted performance.
+type CandidateAllocation struct {
+	// Accelerator is the type of accelerator of the allocation.
+	Accelerator string `json:"accelerator"`
+
+	// NumReplicas is the number of replicas needed to meet the SLO on this accelerator.
+	// +kubebuilder:validation:Minimum=0
+	NumReplicas int `json:"numReplicas"`
+
+	// MaxBatch is the maximum batch size of the allocation.
+	// +kubebuilder:validation:Minimum=0
+	MaxBatch int `json:"maxBatch"`
+
+	// Cost is the cost of the allocation.
+	// +kubebuilder:validation:Pattern=`^\d+(\.\d+)?$`
+	Cost string `json:"cost"`
+
+	// Value is the value the optimizer minimizes for the allocation (cost, including any accelerator transition penalty).
+	// +kubebuilder:validation:Pattern=`^-?\d+(\.\d+)?$`
+	Value string `json:"value"`
+
+	// ITLPredicted is the predicted average inter token latency (msec) at this number of replicas.
+	// +kubebuilder:validation:Pattern=`^\d+(\.\d+)?$`
+	ITLPredicted string `json:"itlPredicted"`
+
+	// TTFTPredicted is the predicted average time to first token (msec) at this number of replicas.
+	// +kubebuilder:validation:Pattern=`^\d+(\.\d+)?$`
+	TTFTPredicted string `json:"ttftPredicted"`
+
+	// Utilization is the predicted average fraction of the maximum batch in use.
+	// +kubebuilder:validation:Pattern=`^\d+(\.\d+)?$`
+	Utilization string `json:"utilization"`
+
+	// MaxRPMPerReplica is the maximum request rate (requests per minute) a replica can serve within the SLO.
+	// +kubebuilder:validation:Pattern=`^\d+(\.\d+)?$`
+	MaxRPMPerReplica string `json:"maxRPMPerReplica"`
+}
+
 // ActuationStatus provides details about the actuation process and its current status.
 type ActuationStatus struct {
 	// Applied indicates whether the actuation was successfully applied.
@@ -162,6 +238,7 @@ type ActuationStatus struct {
 // +kubebuilder:printcolumn:name="CurrentReplicas",type=integer,JSONPath=".status.currentAlloc.numReplicas"
 // +kubebuilder:printcolumn:name="Optimized",type=string,JSONPath=".status.desiredOptimizedAlloc.numReplicas"
 // +kubebuilder:printcolumn:name="MetricsReady",type=string,JSONPath=".status.conditions[?(@.type=='MetricsAvailable')].status"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 
 // VariantAutoscaling is the Schema for the variantautoscalings API.
@@ -197,6 +274,22 @@ const (
 	TypeMetricsAvailable = "MetricsAvailable"
 	// TypeOptimizationReady indicates whether the optimization engine can run successfully
 	TypeOptimizationReady = "OptimizationReady"
+	// TypeKVCacheSaturated indicates whether the KV cache rather than the max number of sequences limits the batch size
+	TypeKVCacheSaturated = "KVCacheSaturated"
+	// TypeConfigurationValid indicates whether the model ID, accelerator profiles and accelerator cost are usable
+	TypeConfigurationValid = "ConfigurationValid"
+	// TypeTargetResolved indicates whether the Deployment of the variant was found and owns the VariantAutoscaling
+	TypeTargetResolved = "TargetResolved"
+	// TypeSLOResolved indicates whether a service class with SLOs for the model was found
+	TypeSLOResolved = "SLOResolved"
+	// TypeReady indicates whether the variant is being optimized, i.e., all the other conditions are satisfied
+	TypeReady = "Ready"
+	// TypePerformanceModelDrift indicates whether the latencies predicted for the current allocation persistently
+	// differ from the observed latencies, i.e., whether the performance parameters need recalibration
+	TypePerformanceModelDrift = "PerformanceModelDrift"
+	// TypeBudgetConstrained indicates whether the variant was allocated fewer replicas than needed to meet its SLO,
+	// to fit the cost budget
+	TypeBudgetConstrained = "BudgetConstrained"
 )
 
 // Condition Reasons for MetricsAvailable
@@ -220,3 +313,69 @@ const (
 	// ReasonMetricsUnavailable indicates optimization cannot run due to missing metrics
 	ReasonMetricsUnavailable = "MetricsUnavailable"
 )
+
+// Condition Reasons for ConfigurationValid
+const (
+	// ReasonConfigurationValid indicates the configuration of the variant is usable
+	ReasonConfigurationValid = "ConfigurationValid"
+	// ReasonMissingModelID indicates the variant has no model ID
+	ReasonMissingModelID = "MissingModelID"
+	// ReasonInvalidModelProfile indicates an accelerator profile of the variant has invalid performance parameters
+	ReasonInvalidModelProfile = "InvalidModelProfile"
+	// ReasonMissingAcceleratorCost indicates the accelerator has no, or an invalid, cost in the accelerator ConfigMap
+	ReasonMissingAcceleratorCost = "MissingAcceleratorCost"
+	// ReasonInvalidServerData indicates the server data of the variant cannot be added to the optimization problem
+	ReasonInvalidServerData = "InvalidServerData"
+)
+
+// Condition Reasons for TargetResolved
+const (
+	// ReasonDeploymentFound indicates the Deployment of the variant was found
+	ReasonDeploymentFound = "DeploymentFound"
+	// ReasonDeploymentNotFound indicates the Deployment of the variant cannot be read
+	ReasonDeploymentNotFound = "DeploymentNotFound"
+	// ReasonOwnerReferenceFailed indicates the Deployment cannot be set as the owner of the variant
+	ReasonOwnerReferenceFailed = "OwnerReferenceFailed"
+)
+
+// Condition Reasons for SLOResolved
+const (
+	// ReasonSLOFound indicates a service class with SLOs for the model was found
+	ReasonSLOFound = "SLOFound"
+	// ReasonSLONotFound indicates no service class lists the model
+	ReasonSLONotFound = "SLONotFound"
+)
+
+// Condition Reasons for Ready
+const (
+	// ReasonReady indicates all the conditions for optimizing the variant are satisfied
+	ReasonReady = "Ready"
+	// ReasonNotReady indicates some condition is not yet known
+	ReasonNotReady = "NotReady"
+)
+
+// Condition Reasons for PerformanceModelDrift
+const (
+	// ReasonPredictionErrorHigh indicates the prediction error stayed above the threshold for the configured number of cycles
+	ReasonPredictionErrorHigh = "PredictionErrorHigh"
+	// ReasonPredictionAccurate indicates the prediction error is within the threshold, or has not stayed above it long enough
+	ReasonPredictionAccurate = "PredictionAccurate"
+)
+
+// Condition Reasons for BudgetConstrained
+const (
+	// ReasonReplicasReducedByBudget indicates replicas needed to meet the SLO were removed to fit the cost budget
+	ReasonReplicasReducedByBudget = "ReplicasReducedByBudget"
+	// ReasonWithinBudget indicates the allocation needed to meet the SLO fits the cost budget
+	ReasonWithinBudget = "WithinBudget"
+)
+
+// Condition Reasons for KVCacheSaturated
+const (
+	// ReasonKVCacheFull indicates the KV cache usage is at or above the saturation threshold
+	ReasonKVCacheFull = "KVCacheFull"
+	// ReasonRequestsPreempted indicates requests are being preempted for lack of KV cache space
+	ReasonRequestsPreempted = "RequestsPreempted"
+	// ReasonKVCacheAvailable indicates the KV cache has room for more requests
+	ReasonKVCacheAvailable = "KVCacheAvailable"
+)
@@ -249,6 +249,90 @@ func TestOptimizedAllocLastRunTimeJSON(t *testing.T) {
 	}
 }
 
+func TestEmptyAllocationOmitsUnsetFields(t *testing.T) {
+	raw, err := json.Marshal(VariantAutoscalingStatus{})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var probe struct {
+		CurrentAlloc          map[string]any `json:"currentAlloc"`
+		DesiredOptimizedAlloc map[string]any `json:"desiredOptimizedAlloc"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		t.Fatalf("unmarshal probe failed: %v", err)
+	}
+	for _, key := range []string{"accelerator", "variantCost", "itlAverage", "ttftAverage"} {
+		if _, ok := probe.CurrentAlloc[key]; ok {
+			t.Errorf("expected unset currentAlloc.%s to be omitted, got: %s", key, string(raw))
+		}
+	}
+	if _, ok := probe.DesiredOptimizedAlloc["accelerator"]; ok {
+		t.Errorf("expected unset desiredOptimizedAlloc.accelerator to be omitted, got: %s", string(raw))
+	}
+}
+
+func TestSetReadyCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions map[string]metav1.ConditionStatus
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "no conditions",
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: ReasonNotReady,
+		},
+		{
+			name: "first false condition wins",
+			conditions: map[string]metav1.ConditionStatus{
+				TypeConfigurationValid: metav1.ConditionTrue,
+				TypeSLOResolved:        metav1.ConditionFalse,
+				TypeMetricsAvailable:   metav1.ConditionFalse,
+			},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: TypeSLOResolved + "Reason",
+		},
+		{
+			name: "false before unknown",
+			conditions: map[string]metav1.ConditionStatus{
+				TypeMetricsAvailable: metav1.ConditionFalse,
+			},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: TypeMetricsAvailable + "Reason",
+		},
+		{
+			name: "all true",
+			conditions: map[string]metav1.ConditionStatus{
+				TypeConfigurationValid: metav1.ConditionTrue,
+				TypeSLOResolved:        metav1.ConditionTrue,
+				TypeTargetResolved:     metav1.ConditionTrue,
+				TypeMetricsAvailable:   metav1.ConditionTrue,
+				TypeOptimizationReady:  metav1.ConditionTrue,
+			},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonReady,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			va := makeValidVA()
+			for conditionType, status := range tt.conditions {
+				SetCondition(va, conditionType, status, conditionType+"Reason", "message")
+			}
+			SetReadyCondition(va)
+			ready := GetCondition(va, TypeReady)
+			if ready == nil {
+				t.Fatalf("expected Ready condition to be set")
+			}
+			if ready.Status != tt.wantStatus || ready.Reason != tt.wantReason {
+				t.Errorf("Ready = (%s, %s), want (%s, %s)", ready.Status, ready.Reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
 func jsonContainsKey(b []byte, key string) bool {
 	var m map[string]any
 	if err := json.Unmarshal(b, &m); err != nil {
@@ -72,6 +72,21 @@ func (in *Allocation) DeepCopy() *Allocation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CandidateAllocation) DeepCopyInto(out *CandidateAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CandidateAllocation.
+func (in *CandidateAllocation) DeepCopy() *CandidateAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(CandidateAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapKeyRef) DeepCopyInto(out *ConfigMapKeyRef) {
 	*out = *in
@@ -169,6 +184,31 @@ func (in *PerfParms) DeepCopy() *PerfParms {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Recommendation) DeepCopyInto(out *Recommendation) {
+	*out = *in
+	if in.Recommended != nil {
+		in, out := &in.Recommended, &out.Recommended
+		*out = new(CandidateAllocation)
+		**out = **in
+	}
+	if in.Candidates != nil {
+		in, out := &in.Candidates, &out.Candidates
+		*out = make([]CandidateAllocation, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Recommendation.
+func (in *Recommendation) DeepCopy() *Recommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(Recommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VariantAutoscaling) DeepCopyInto(out *VariantAutoscaling) {
 	*out = *in
@@ -233,6 +273,11 @@ func (in *VariantAutoscalingSpec) DeepCopyInto(out *VariantAutoscalingSpec) {
 	*out = *in
 	out.SLOClassRef = in.SLOClassRef
 	in.ModelProfile.DeepCopyInto(&out.ModelProfile)
+	if in.StartupLatency != nil {
+		in, out := &in.StartupLatency, &out.StartupLatency
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VariantAutoscalingSpec.
@@ -251,6 +296,11 @@ func (in *VariantAutoscalingStatus) DeepCopyInto(out *VariantAutoscalingStatus)
 	out.CurrentAlloc = in.CurrentAlloc
 	in.DesiredOptimizedAlloc.DeepCopyInto(&out.DesiredOptimizedAlloc)
 	out.Actuation = in.Actuation
+	if in.Recommendation != nil {
+		in, out := &in.Recommendation, &out.Recommendation
+		*out = new(Recommendation)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
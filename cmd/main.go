@@ -27,6 +27,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -43,6 +44,8 @@ import (
 	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/controller"
 	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
 	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/metrics"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/snapshot"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/trace"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	//+kubebuilder:scaffold:imports
 )
@@ -64,9 +67,13 @@ func main() {
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
+	var leaderElectionID string
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var watchNamespaces, variantSelector, configNamespace string
+	var tracePath string
+	var traceMaxSizeMB int
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -74,6 +81,8 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "72dd1cf1.llm-d.ai",
+		"The name of the leader election lease. Instances deployed in the same namespace need distinct names.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
@@ -85,6 +94,16 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma-separated list of namespaces whose VariantAutoscalings are managed. All namespaces if empty.")
+	flag.StringVar(&variantSelector, "variant-selector", "",
+		"Label selector of the VariantAutoscalings managed by this instance, e.g., tenant=a. All if empty.")
+	flag.StringVar(&configNamespace, "config-namespace", controller.DefaultConfigNamespace,
+		"The namespace of the optimization, accelerator cost and service class ConfigMaps.")
+	flag.StringVar(&tracePath, "record-trace", "",
+		"Path of a file to append the inputs of each optimization cycle to, for replay with wva-replay. None if empty.")
+	flag.IntVar(&traceMaxSizeMB, "record-trace-max-size-mb", 100,
+		"Size in MiB beyond which the trace file is moved to a backup with the .1 suffix. Unlimited if 0.")
 
 	flag.Parse()
 
@@ -160,12 +179,19 @@ func main() {
 		TLSOpts:       tlsOpts,
 	}
 
+	// the input and output of the last optimization cycle, kept for debugging
+	snapshots := snapshot.NewStore()
+
 	if secureMetrics {
 		// FilterProvider is used to protect the metrics endpoint with authn/authz.
 		// These configurations ensure that only authorized users and service accounts
 		// can access the metrics endpoint. The RBAC are configured in 'config/rbac/kustomization.yaml'. More info:
 		// https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.4/pkg/metrics/filters#WithAuthenticationAndAuthorization
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+
+		// the filter also protects the snapshot endpoints, which are only served securely as they expose the
+		// configuration and load of all variants
+		metricsServerOptions.ExtraHandlers = snapshots.Handlers()
 	}
 
 	// If the certificate is not specified, controller-runtime will automatically
@@ -198,13 +224,29 @@ func main() {
 		})
 	}
 
+	selector, err := labels.Parse(variantSelector)
+	if err != nil {
+		setupLog.Error("invalid variant selector", zap.String("selector", variantSelector), zap.Error(err))
+		os.Exit(1)
+	}
+	scope := controller.Scope{
+		Namespaces:      controller.ParseNamespaces(watchNamespaces),
+		VariantSelector: selector,
+		ConfigNamespace: configNamespace,
+	}
+	setupLog.Info("Managing VariantAutoscalings",
+		zap.Strings("namespaces", scope.Namespaces),
+		zap.String("selector", selector.String()),
+		zap.String("configNamespace", configNamespace))
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  scope.CacheOptions(),
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "72dd1cf1.llm-d.ai",
+		LeaderElectionID:       leaderElectionID,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -215,17 +257,42 @@ func main() {
 		// the manager stops, so would be fine to enable this option. However,
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		//
+		// The lease is released so that the next leader publishes recommendations without
+		// waiting for it to expire.
+		LeaderElectionReleaseOnCancel: true,
 	})
 	if err != nil {
 		setupLog.Error("unable to start manager", zap.Error(err))
 		os.Exit(1)
 	}
 
-	if err = (&controller.VariantAutoscalingReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	// with leader election, only the elected replica exports the recommendation series external autoscalers act on
+	if enableLeaderElection {
+		metrics.DefaultLeaderGate().EnableLeaderElection()
+	}
+	if err := mgr.Add(metrics.DefaultLeaderGate()); err != nil {
+		setupLog.Error("unable to add leader gate to manager", zap.Error(err))
+		os.Exit(1)
+	}
+
+	// the inputs of each optimization cycle, recorded for replay
+	var traceWriter *trace.Writer
+	if tracePath != "" {
+		if traceWriter, err = trace.NewWriter(tracePath, int64(traceMaxSizeMB)<<20); err != nil {
+			setupLog.Error("unable to open trace file", zap.String("path", tracePath), zap.Error(err))
+			os.Exit(1)
+		}
+		defer traceWriter.Close() //nolint:errcheck
+		setupLog.Info("Recording optimization cycles", zap.String("path", tracePath), zap.Int("maxSizeMB", traceMaxSizeMB))
+	}
+
+	reconciler := controller.NewVariantAutoscalingReconciler(mgr.GetClient(), mgr.GetScheme())
+	reconciler.Recorder = mgr.GetEventRecorderFor("workload-variant-autoscaler")
+	reconciler.Scope = scope
+	reconciler.Snapshots = snapshots
+	reconciler.Trace = traceWriter
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error("unable to create controller", zap.String("controller", "variantautoscaling"), zap.Error(err))
 		os.Exit(1)
 	}
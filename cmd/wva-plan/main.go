@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// wva-plan runs the optimizer offline on a system spec (accelerators, models, service classes, servers with their
+// loads) and prints the optimized allocations, for capacity planning and what-if analysis without a cluster.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/planner"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// Result of a point of an arrival rate sweep
+type sweepResult struct {
+	RateFactor float32         `json:"rateFactor"`
+	Result     *planner.Result `json:"result"`
+}
+
+func main() {
+	var systemPath, output, sweepRate, sweepServer string
+	var swaps []string
+	flag.StringVar(&systemPath, "system", "", "Path of the system spec in JSON (SystemData), or - for standard input.")
+	flag.StringVar(&output, "output", outputTable, "Output format: table or json.")
+	flag.StringVar(&sweepRate, "sweep-rate", "",
+		"Comma-separated factors to scale arrival rates by, optimizing once per factor, e.g., 0.5,1,2,4.")
+	flag.StringVar(&sweepServer, "sweep-server", "", "Only scale the arrival rate of this server in a sweep.")
+	flag.Func("swap-accelerator",
+		"Replace an accelerator by another as FROM=TO, moving allocations and capacity to it; may be repeated.",
+		func(val string) error {
+			from, to, found := strings.Cut(val, "=")
+			if !found || from == "" || to == "" {
+				return fmt.Errorf("expected FROM=TO, got %q", val)
+			}
+			swaps = append(swaps, val)
+			return nil
+		})
+	flag.Parse()
+
+	if err := run(os.Stdout, systemPath, output, sweepRate, sweepServer, swaps); err != nil {
+		fmt.Fprintf(os.Stderr, "wva-plan: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, systemPath, output, sweepRate, sweepServer string, swaps []string) error {
+	if systemPath == "" {
+		return fmt.Errorf("missing -system")
+	}
+	if output != outputTable && output != outputJSON {
+		return fmt.Errorf("invalid -output %q, expected %s or %s", output, outputTable, outputJSON)
+	}
+	factors, err := parseFactors(sweepRate)
+	if err != nil {
+		return err
+	}
+
+	data, err := readSystemData(systemPath)
+	if err != nil {
+		return err
+	}
+	for _, swap := range swaps {
+		from, to, _ := strings.Cut(swap, "=")
+		if data, err = planner.SwapAccelerator(data, from, to); err != nil {
+			return err
+		}
+	}
+
+	if factors == nil {
+		result, err := planner.Optimize(data)
+		if err != nil {
+			return err
+		}
+		if output == outputJSON {
+			return writeJSON(w, result)
+		}
+		return writeTable(w, data, result)
+	}
+
+	results := make([]sweepResult, 0, len(factors))
+	for _, factor := range factors {
+		scaled, err := planner.ScaleArrivalRate(data, factor, sweepServer)
+		if err != nil {
+			return err
+		}
+		result, err := planner.Optimize(scaled)
+		if err != nil {
+			return fmt.Errorf("rate factor %v: %w", factor, err)
+		}
+		results = append(results, sweepResult{RateFactor: factor, Result: result})
+		if output == outputTable {
+			fmt.Fprintf(w, "Arrival rate x%v\n", factor)
+			if err := writeTable(w, scaled, result); err != nil {
+				return err
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	if output == outputJSON {
+		return writeJSON(w, results)
+	}
+	return nil
+}
+
+// Parse comma-separated arrival rate factors; nil if none
+func parseFactors(val string) ([]float32, error) {
+	if val == "" {
+		return nil, nil
+	}
+	factors := make([]float32, 0)
+	for _, field := range strings.Split(val, ",") {
+		f, err := strconv.ParseFloat(strings.TrimSpace(field), 32)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("invalid -sweep-rate factor %q", field)
+		}
+		factors = append(factors, float32(f))
+	}
+	return factors, nil
+}
+
+func readSystemData(path string) (*config.SystemData, error) {
+	if path == "-" {
+		return planner.ReadSystemData(os.Stdin)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+	return planner.ReadSystemData(file)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// Write the allocations of all servers of the system, those without a feasible allocation included, and the plan
+func writeTable(w io.Writer, data *config.SystemData, result *planner.Result) error {
+	names := make([]string, 0, len(data.Spec.Servers.Spec))
+	for _, server := range data.Spec.Servers.Spec {
+		names = append(names, server.Name)
+	}
+	slices.Sort(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tACCELERATOR\tREPLICAS\tMAX BATCH\tCOST\tITL (ms)\tTTFT (ms)\tRATE (req/min)")
+	totalCost := float32(0)
+	for _, name := range names {
+		alloc, ok := result.Solution.Spec[name]
+		if !ok {
+			fmt.Fprintf(tw, "%s\tnone\t-\t-\t-\t-\t-\t-\n", name)
+			continue
+		}
+		totalCost += alloc.Cost
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.2f\t%.2f\t%.2f\t%.2f\n", name, alloc.Accelerator, alloc.NumReplicas,
+			alloc.MaxBatch, alloc.Cost, alloc.ITLAverage, alloc.TTFTAverage, alloc.Load.ArrivalRate)
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t\t\t%.2f\n", totalCost)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if result.Plan == nil || len(result.Plan.Steps) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STEP\tACTION\tSERVER\tACCELERATOR\tREPLICAS\tAFTER")
+	for _, step := range result.Plan.Steps {
+		after := "-"
+		if len(step.DependsOn) > 0 {
+			indices := make([]string, 0, len(step.DependsOn))
+			for _, index := range step.DependsOn {
+				indices = append(indices, strconv.Itoa(index))
+			}
+			after = strings.Join(indices, ",")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d -> %d\t%s\n", step.Index, step.Action, step.ServerName,
+			step.Accelerator, step.FromReplicas, step.ToReplicas, after)
+	}
+	return tw.Flush()
+}
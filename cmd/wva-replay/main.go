@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// wva-replay replays the optimization cycles recorded by the controller with --record-trace, without a cluster or
+// Prometheus, and prints the recommendations of each cycle, to check solver and analyzer changes against real traffic.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	llmdVariantAutoscalingV1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/controller"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/metrics"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/trace"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+func main() {
+	var tracePath, output string
+	var verbose bool
+	flag.StringVar(&tracePath, "trace", "", "Path of the trace recorded by the controller, or - for standard input.")
+	flag.StringVar(&output, "output", outputTable, "Output format: table, or json with one line per cycle.")
+	flag.BoolVar(&verbose, "v", false, "Log the controller messages, at the level set by LOG_LEVEL.")
+	flag.Parse()
+
+	if err := run(os.Stdout, tracePath, output, verbose); err != nil {
+		fmt.Fprintf(os.Stderr, "wva-replay: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, tracePath, output string, verbose bool) error {
+	if tracePath == "" {
+		return fmt.Errorf("missing -trace")
+	}
+	if output != outputTable && output != outputJSON {
+		return fmt.Errorf("invalid -output %q, expected %s or %s", output, outputTable, outputJSON)
+	}
+
+	if verbose {
+		if _, err := logger.InitLogger(); err != nil {
+			return err
+		}
+	} else {
+		logger.Log = zap.NewNop().Sugar()
+	}
+	// the controller emits its metrics as usual, to a registry that is not exported
+	if err := metrics.InitMetrics(prometheus.NewRegistry()); err != nil {
+		return err
+	}
+
+	cycles, err := readTrace(tracePath)
+	if err != nil {
+		return err
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return err
+	}
+	if err := llmdVariantAutoscalingV1alpha1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	replayed, err := controller.Replay(context.Background(), scheme, cycles)
+	if err != nil {
+		return err
+	}
+
+	if output == outputJSON {
+		encoder := json.NewEncoder(w)
+		for _, cycle := range replayed {
+			if err := encoder.Encode(cycle); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tNAMESPACE\tVARIANT\tACCELERATOR\tREPLICAS\tOPTIMIZED")
+	for _, cycle := range replayed {
+		for _, variant := range cycle.Variants {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%t\n", cycle.Time.UTC().Format(time.RFC3339), variant.Namespace,
+				variant.Name, variant.Accelerator, variant.NumReplicas, variant.Optimized)
+		}
+	}
+	return tw.Flush()
+}
+
+func readTrace(path string) ([]*trace.Cycle, error) {
+	if path == "-" {
+		return trace.ReadCycles(os.Stdin)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+	return trace.ReadCycles(file)
+}
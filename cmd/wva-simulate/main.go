@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// wva-simulate runs the autoscaling control loop offline on a system spec under a synthetic load, with a modeled
+// actuation delay and replica startup, and prints the SLO attainment, cost and replica churn of the servers, to
+// compare autoscaling policies before changing the controller.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/simulation"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/planner"
+)
+
+const (
+	outputTable   = "table"
+	outputJSON    = "json"
+	outputSamples = "samples"
+
+	// stabilization window of the controller
+	stabilizationStartup = "startup"
+)
+
+type options struct {
+	systemPath          string
+	output              string
+	load                string
+	loadFactor          float64
+	loadPeriod          time.Duration
+	seed                uint64
+	duration            time.Duration
+	step                time.Duration
+	actuationDelay      time.Duration
+	startupLatency      time.Duration
+	interval            time.Duration
+	rateWindow          time.Duration
+	stabilizationWindow string
+	rampHeadroom        bool
+	solver              string
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.systemPath, "system", "", "Path of the system spec in JSON (SystemData), or - for standard input.")
+	flag.StringVar(&opts.output, "output", outputTable,
+		"Output format: table, json, or samples with one JSON line per server and step.")
+	flag.StringVar(&opts.load, "load", "diurnal",
+		"Shape of the arrival rate of every server, relative to its arrival rate in the system spec: "+
+			"constant, diurnal (down to the rate divided by -load-factor), step or bursty (up to the rate multiplied by -load-factor).")
+	flag.Float64Var(&opts.loadFactor, "load-factor", 3, "Ratio of the highest to the lowest arrival rate of the load.")
+	flag.DurationVar(&opts.loadPeriod, "load-period", 24*time.Hour,
+		"Period of diurnal load, time of the step, and ten times the length of bursts.")
+	flag.Uint64Var(&opts.seed, "seed", 1, "Seed of the bursts of bursty load.")
+	flag.DurationVar(&opts.duration, "duration", 24*time.Hour, "Simulated time.")
+	flag.DurationVar(&opts.step, "step", simulation.DefaultStep, "Resolution of the simulation.")
+	flag.DurationVar(&opts.actuationDelay, "actuation-delay", 30*time.Second,
+		"Time from a recommendation to the scaling of the Deployment.")
+	flag.DurationVar(&opts.startupLatency, "startup-latency", 3*time.Minute,
+		"Time from the scaling of the Deployment to new replicas being ready.")
+	flag.DurationVar(&opts.interval, "interval", simulation.DefaultInterval, "Interval between optimization cycles.")
+	flag.DurationVar(&opts.rateWindow, "rate-window", simulation.DefaultRateWindow,
+		"Window over which the collected arrival rate is averaged.")
+	flag.StringVar(&opts.stabilizationWindow, "stabilization-window", stabilizationStartup,
+		"Window in which a higher recommendation holds a scale-down; startup for the startup latency, as the controller, 0 to disable.")
+	flag.BoolVar(&opts.rampHeadroom, "ramp-headroom", true,
+		"Size servers for the arrival rate extrapolated to when new replicas are ready, as the controller.")
+	flag.StringVar(&opts.solver, "solver", "",
+		"Solver mode: unlimited, greedy or mip for limited capacity; that of the system spec if empty.")
+	flag.Parse()
+
+	if err := run(os.Stdout, &opts); err != nil {
+		fmt.Fprintf(os.Stderr, "wva-simulate: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, opts *options) error {
+	if opts.systemPath == "" {
+		return fmt.Errorf("missing -system")
+	}
+	if opts.output != outputTable && opts.output != outputJSON && opts.output != outputSamples {
+		return fmt.Errorf("invalid -output %q, expected %s, %s or %s", opts.output, outputTable, outputJSON, outputSamples)
+	}
+	data, err := readSystemData(opts.systemPath)
+	if err != nil {
+		return err
+	}
+
+	scenario := &simulation.Scenario{
+		System:         data,
+		Loads:          make(map[string]simulation.LoadTrace),
+		Duration:       opts.duration,
+		Step:           opts.step,
+		ActuationDelay: opts.actuationDelay,
+		StartupLatency: opts.startupLatency,
+	}
+	for _, server := range data.Spec.Servers.Spec {
+		load, err := simulation.ParseShape(opts.load, float64(server.CurrentAlloc.Load.ArrivalRate), opts.loadFactor,
+			opts.loadPeriod, opts.seed)
+		if err != nil {
+			return err
+		}
+		scenario.Loads[server.Name] = load
+	}
+
+	policy := &simulation.Policy{
+		Interval:     opts.interval,
+		RateWindow:   opts.rateWindow,
+		RampHeadroom: opts.rampHeadroom,
+	}
+	if opts.stabilizationWindow == stabilizationStartup {
+		policy.StabilizationWindow = opts.startupLatency
+	} else if policy.StabilizationWindow, err = time.ParseDuration(opts.stabilizationWindow); err != nil {
+		return fmt.Errorf("invalid -stabilization-window: %w", err)
+	}
+	if policy.Optimizer, err = solverMode(&data.Spec.Optimizer.Spec, opts.solver); err != nil {
+		return err
+	}
+
+	result, err := simulation.Run(scenario, policy)
+	if err != nil {
+		return err
+	}
+	switch opts.output {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case outputSamples:
+		encoder := json.NewEncoder(w)
+		for _, sample := range result.Samples {
+			if err := encoder.Encode(sample); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return writeTable(w, result)
+}
+
+// solverMode returns the optimizer spec of a solver mode, keeping the other settings of the system spec; nil to keep
+// the optimizer spec of the system spec
+func solverMode(spec *config.OptimizerSpec, mode string) (*config.OptimizerSpec, error) {
+	if mode == "" {
+		return nil, nil
+	}
+	optimizer := *spec
+	switch mode {
+	case "unlimited":
+		optimizer.Unlimited = true
+	case "greedy":
+		optimizer.Unlimited = false
+		optimizer.MIP = false
+	case "mip":
+		optimizer.Unlimited = false
+		optimizer.MIP = true
+	default:
+		return nil, fmt.Errorf("invalid -solver %q, expected unlimited, greedy or mip", mode)
+	}
+	return &optimizer, nil
+}
+
+func readSystemData(path string) (*config.SystemData, error) {
+	if path == "-" {
+		return planner.ReadSystemData(os.Stdin)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+	return planner.ReadSystemData(file)
+}
+
+// Write the summary of each server, and the total
+func writeTable(w io.Writer, result *simulation.Result) error {
+	names := make([]string, 0, len(result.Servers))
+	for name := range result.Servers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tSLO ATTAINMENT\tCOST\tAVG REPLICAS\tMAX REPLICAS\tSCALE UPS\tSCALE DOWNS\tACC CHANGES\tSTARTED\tSTOPPED")
+	row := func(name string, s *simulation.Summary) {
+		fmt.Fprintf(tw, "%s\t%.2f%%\t%.2f\t%.2f\t%d\t%d\t%d\t%d\t%d\t%d\n", name, 100*s.SLOAttainment, s.Cost,
+			s.AvgReplicas, s.MaxReplicas, s.ScaleUps, s.ScaleDowns, s.AcceleratorChanges, s.ReplicasStarted, s.ReplicasStopped)
+	}
+	for _, name := range names {
+		row(name, result.Servers[name])
+	}
+	row("TOTAL", result.Total)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if result.FailedCycles > 0 {
+		fmt.Fprintf(w, "\n%d of %d optimization cycles failed, keeping the previous recommendations\n",
+			result.FailedCycles, result.Cycles)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package actuator
+
+import (
+	"strings"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+)
+
+// ConfigMap keys for actuation options
+const (
+	KeyActuationMode        = "WVA_ACTUATION_MODE"
+	KeyActuationStepTimeout = "WVA_ACTUATION_STEP_TIMEOUT"
+)
+
+// Actuation modes
+const (
+	// ModeMetrics emits the optimized allocations as metrics for external autoscalers (HPA, KEDA) to act on
+	ModeMetrics = "metrics"
+	// ModeDirect also scales the Deployments of the variants, executing the allocation plan step by step
+	ModeDirect = "direct"
+)
+
+// DefaultStepTimeout is how long the direct actuator waits for a step of the plan to converge
+const DefaultStepTimeout = 5 * time.Minute
+
+// Config holds the actuation options
+type Config struct {
+	Mode        string        // ModeMetrics or ModeDirect
+	StepTimeout time.Duration // how long to wait for a step to converge before abandoning the rest of the plan
+}
+
+// ConfigFromData parses actuation options from the optimization ConfigMap data, using defaults for missing or bad values
+func ConfigFromData(data map[string]string) Config {
+	cfg := Config{
+		Mode:        ModeMetrics,
+		StepTimeout: DefaultStepTimeout,
+	}
+	if val, ok := data[KeyActuationMode]; ok && val != "" {
+		switch mode := strings.ToLower(strings.TrimSpace(val)); mode {
+		case ModeMetrics, ModeDirect:
+			cfg.Mode = mode
+		default:
+			logger.Log.Warn("invalid actuation mode, using default", "key", KeyActuationMode, "value", val, "default", ModeMetrics)
+		}
+	}
+	if val, ok := data[KeyActuationStepTimeout]; ok && val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			cfg.StepTimeout = d
+		} else {
+			logger.Log.Warn("invalid actuation step timeout, using default", "key", KeyActuationStepTimeout, "value", val, "default", DefaultStepTimeout)
+		}
+	}
+	return cfg
+}
@@ -0,0 +1,123 @@
+package actuator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/utils"
+	infernoSolver "github.com/llm-d-incubation/workload-variant-autoscaler/pkg/solver"
+)
+
+// DefaultPollInterval is how often the direct actuator checks whether a step of the plan has converged
+const DefaultPollInterval = 5 * time.Second
+
+// DirectActuator scales the Deployments of variants, executing the steps of an allocation plan in order and waiting
+// for each step to converge before the next, so that the accelerators released by scale-downs are free when the
+// scale-ups depending on them start
+type DirectActuator struct {
+	Client       client.Client
+	PollInterval time.Duration
+	StepTimeout  time.Duration
+}
+
+func NewDirectActuator(k8sClient client.Client, stepTimeout time.Duration) *DirectActuator {
+	return &DirectActuator{
+		Client:       k8sClient,
+		PollInterval: DefaultPollInterval,
+		StepTimeout:  stepTimeout,
+	}
+}
+
+// AcceleratorChanges returns the servers the plan moves to another accelerator. Scaling their Deployment cannot
+// change its accelerator, and releasing the replicas on the current accelerator before scaling up the same
+// Deployment would leave the variant without replicas, so such changes are left to the operator.
+func AcceleratorChanges(plan *infernoSolver.Plan) map[string]bool {
+	changes := make(map[string]bool)
+	if plan == nil {
+		return changes
+	}
+	for _, step := range plan.Steps {
+		if step.Action == infernoSolver.ActionRelease {
+			changes[step.ServerName] = true
+		}
+	}
+	return changes
+}
+
+// Execute applies the steps of the plan to the Deployments of their servers, keyed by server name in targets, and
+// returns the number of steps done, skipping steps of servers without a target, steps of servers changing
+// accelerator, and steps depending on skipped ones. It stops at the first step that fails or does not converge
+// within the step timeout, since later steps may depend on it, returning its position.
+func (a *DirectActuator) Execute(ctx context.Context, plan *infernoSolver.Plan, targets map[string]types.NamespacedName) (int, error) {
+	if plan == nil {
+		return 0, nil
+	}
+	changes := AcceleratorChanges(plan)
+	skipped := make(map[int]bool)
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+		if changes[step.ServerName] || slices.ContainsFunc(step.DependsOn, func(j int) bool { return skipped[j] }) {
+			logger.Log.Info("Skipping plan step changing accelerator, or depending on a skipped step - ", "step: ", step)
+			skipped[step.Index] = true
+			continue
+		}
+		target, ok := targets[step.ServerName]
+		if !ok {
+			logger.Log.Debug("Skipping plan step without a target Deployment - ", "step: ", step)
+			continue
+		}
+		if err := a.executeStep(ctx, step, target); err != nil {
+			return i, fmt.Errorf("plan step %d (%s %s) failed: %w", step.Index, step.Action, target, err)
+		}
+		logger.Log.Info("Plan step converged - ", "step: ", step.Index, ", action: ", step.Action,
+			", deployment: ", target, ", replicas: ", step.ToReplicas)
+	}
+	return len(plan.Steps), nil
+}
+
+// executeStep scales the Deployment to the replicas of the step and waits until it converges
+func (a *DirectActuator) executeStep(ctx context.Context, step *infernoSolver.PlanStep, target types.NamespacedName) error {
+	replicas := int32(step.ToReplicas)
+
+	var deploy appsv1.Deployment
+	if err := utils.GetDeploymentWithBackoff(ctx, a.Client, target.Name, target.Namespace, &deploy); err != nil {
+		return err
+	}
+	if deploy.Spec.Replicas == nil || *deploy.Spec.Replicas != replicas {
+		original := deploy.DeepCopy()
+		deploy.Spec.Replicas = ptr.To(replicas)
+		if err := a.Client.Patch(ctx, &deploy, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("failed to scale Deployment to %d replicas: %w", replicas, err)
+		}
+	}
+
+	err := wait.PollUntilContextTimeout(ctx, a.PollInterval, a.StepTimeout, true, func(ctx context.Context) (bool, error) {
+		if err := a.Client.Get(ctx, target, &deploy); err != nil {
+			logger.Log.Debug("Failed to get Deployment while waiting for plan step, retrying - ", "deployment: ", target, ", error: ", err)
+			return false, nil
+		}
+		return deploymentConverged(&deploy, replicas), nil
+	})
+	if err != nil {
+		return fmt.Errorf("Deployment did not converge to %d replicas: %w", replicas, err)
+	}
+	return nil
+}
+
+// deploymentConverged returns whether the Deployment controller has observed the latest spec and all replicas are
+// updated and ready, with no replica left over
+func deploymentConverged(deploy *appsv1.Deployment, replicas int32) bool {
+	return deploy.Status.ObservedGeneration >= deploy.Generation &&
+		deploy.Status.Replicas == replicas &&
+		deploy.Status.UpdatedReplicas == replicas &&
+		deploy.Status.ReadyReplicas == replicas
+}
@@ -0,0 +1,155 @@
+package actuator
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	infernoSolver "github.com/llm-d-incubation/workload-variant-autoscaler/pkg/solver"
+)
+
+var _ = Describe("DirectActuator", func() {
+	var (
+		ctx     context.Context
+		scheme  *runtime.Scheme
+		patched []string
+	)
+
+	newDeployment := func(name string, replicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(replicas)},
+			Status: appsv1.DeploymentStatus{
+				Replicas:        replicas,
+				UpdatedReplicas: replicas,
+				ReadyReplicas:   replicas,
+			},
+		}
+	}
+
+	// fake client whose Deployments converge on being scaled, unless listed as stuck
+	newClient := func(stuck map[string]bool, objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&appsv1.Deployment{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					if err := c.Patch(ctx, obj, patch, opts...); err != nil {
+						return err
+					}
+					deploy, ok := obj.(*appsv1.Deployment)
+					if !ok {
+						return nil
+					}
+					patched = append(patched, deploy.Name)
+					if stuck[deploy.Name] {
+						return nil
+					}
+					deploy.Status.Replicas = *deploy.Spec.Replicas
+					deploy.Status.UpdatedReplicas = *deploy.Spec.Replicas
+					deploy.Status.ReadyReplicas = *deploy.Spec.Replicas
+					return c.Status().Update(ctx, deploy)
+				},
+			}).
+			Build()
+	}
+
+	targets := map[string]types.NamespacedName{
+		"low:default":  {Name: "low", Namespace: "default"},
+		"high:default": {Name: "high", Namespace: "default"},
+	}
+	plan := &infernoSolver.Plan{Steps: []infernoSolver.PlanStep{
+		{Index: 0, Action: infernoSolver.ActionScaleDown, ServerName: "low:default", FromReplicas: 6, ToReplicas: 2},
+		{Index: 1, Action: infernoSolver.ActionScaleUp, ServerName: "other:default", FromReplicas: 0, ToReplicas: 1},
+		{Index: 2, Action: infernoSolver.ActionScaleUp, ServerName: "high:default", FromReplicas: 2, ToReplicas: 6,
+			DependsOn: []int{0}},
+	}}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		patched = nil
+		scheme = runtime.NewScheme()
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("should execute the steps in order, skipping servers without a target", func() {
+		c := newClient(nil, newDeployment("low", 6), newDeployment("high", 2))
+		act := NewDirectActuator(c, time.Second)
+		act.PollInterval = 10 * time.Millisecond
+
+		completed, err := act.Execute(ctx, plan, targets)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(completed).To(Equal(3))
+		Expect(patched).To(Equal([]string{"low", "high"}))
+
+		var deploy appsv1.Deployment
+		Expect(c.Get(ctx, targets["high:default"], &deploy)).To(Succeed())
+		Expect(*deploy.Spec.Replicas).To(Equal(int32(6)))
+	})
+
+	It("should abandon the remaining steps when a step does not converge", func() {
+		c := newClient(map[string]bool{"low": true}, newDeployment("low", 6), newDeployment("high", 2))
+		act := NewDirectActuator(c, 50*time.Millisecond)
+		act.PollInterval = 10 * time.Millisecond
+
+		completed, err := act.Execute(ctx, plan, targets)
+		Expect(err).To(HaveOccurred())
+		Expect(completed).To(Equal(0))
+		Expect(patched).To(Equal([]string{"low"}))
+	})
+
+	It("should not patch a Deployment already at the replicas of the step", func() {
+		c := newClient(nil, newDeployment("low", 2), newDeployment("high", 6))
+		act := NewDirectActuator(c, time.Second)
+		act.PollInterval = 10 * time.Millisecond
+
+		completed, err := act.Execute(ctx, plan, targets)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(completed).To(Equal(3))
+		Expect(patched).To(BeEmpty())
+	})
+
+	It("should skip accelerator changes and the steps depending on them", func() {
+		c := newClient(nil, newDeployment("low", 6), newDeployment("high", 2))
+		act := NewDirectActuator(c, time.Second)
+		act.PollInterval = 10 * time.Millisecond
+
+		changePlan := &infernoSolver.Plan{Steps: []infernoSolver.PlanStep{
+			{Index: 0, Action: infernoSolver.ActionRelease, ServerName: "low:default", Accelerator: "A100", FromReplicas: 6, ToReplicas: 0},
+			{Index: 1, Action: infernoSolver.ActionScaleUp, ServerName: "low:default", Accelerator: "H100", FromReplicas: 0, ToReplicas: 2},
+			{Index: 2, Action: infernoSolver.ActionScaleUp, ServerName: "high:default", Accelerator: "A100", FromReplicas: 2, ToReplicas: 6,
+				DependsOn: []int{0}},
+		}}
+		Expect(AcceleratorChanges(changePlan)).To(Equal(map[string]bool{"low:default": true}))
+
+		completed, err := act.Execute(ctx, changePlan, targets)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(completed).To(Equal(3))
+		Expect(patched).To(BeEmpty())
+	})
+
+	It("should parse the actuation options", func() {
+		cfg := ConfigFromData(map[string]string{})
+		Expect(cfg.Mode).To(Equal(ModeMetrics))
+		Expect(cfg.StepTimeout).To(Equal(DefaultStepTimeout))
+
+		cfg = ConfigFromData(map[string]string{KeyActuationMode: "Direct", KeyActuationStepTimeout: "90s"})
+		Expect(cfg.Mode).To(Equal(ModeDirect))
+		Expect(cfg.StepTimeout).To(Equal(90 * time.Second))
+
+		cfg = ConfigFromData(map[string]string{KeyActuationMode: "hpa", KeyActuationStepTimeout: "-1s"})
+		Expect(cfg.Mode).To(Equal(ModeMetrics))
+		Expect(cfg.StepTimeout).To(Equal(DefaultStepTimeout))
+	})
+})
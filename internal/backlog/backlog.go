@@ -0,0 +1,107 @@
+// Package backlog compares the observed request queues of a variant with those predicted by the queueing model,
+// and sizes emergency scale-ups when an observed backlog cannot be drained within the TTFT SLO.
+// Its predictions also include the latencies of the current allocation, to check the model against observations.
+package backlog
+
+import (
+	"fmt"
+	"math"
+
+	llmdVariantAutoscalingV1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/utils"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/analyzer"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+)
+
+// Prediction of the number of requests waiting and running across the replicas of a variant, and of their
+// average latencies (msec); latencies are not predicted for saturated replicas
+type Prediction struct {
+	Waiting   float64
+	Running   float64
+	ITL       float64
+	TTFT      float64
+	Saturated bool
+}
+
+// Evaluator of the queue of one replica of a variant on an accelerator
+type Evaluator struct {
+	queueAnalyzer *analyzer.QueueAnalyzer
+}
+
+// Create an evaluator from the accelerator profile of a variant and the observed request sizes
+func NewEvaluator(profile *llmdVariantAutoscalingV1alpha1.AcceleratorProfile, avgInTokens, avgOutTokens int) (*Evaluator, error) {
+	return NewEvaluatorWithBatchSize(profile, 0, avgInTokens, avgOutTokens)
+}
+
+// Create an evaluator as NewEvaluator, with a max batch size overriding the one of the profile, if positive
+func NewEvaluatorWithBatchSize(profile *llmdVariantAutoscalingV1alpha1.AcceleratorProfile, maxBatchSize, avgInTokens,
+	avgOutTokens int) (*Evaluator, error) {
+	if maxBatchSize <= 0 {
+		maxBatchSize = profile.MaxBatchSize
+	}
+	decode, prefill, err := utils.ParsePerfParms(profile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid performance parameters for accelerator %s: %w", profile.Acc, err)
+	}
+	qConfig := &analyzer.Configuration{
+		MaxBatchSize: maxBatchSize,
+		MaxQueueSize: maxBatchSize * config.MaxQueueToBatchRatio,
+		ServiceParms: &analyzer.ServiceParms{
+			Prefill: &analyzer.PrefillParms{Gamma: prefill.Gamma, Delta: prefill.Delta},
+			Decode:  &analyzer.DecodeParms{Alpha: decode.Alpha, Beta: decode.Beta},
+		},
+	}
+	requestSize := &analyzer.RequestSize{
+		AvgInputTokens:  avgInTokens,
+		AvgOutputTokens: avgOutTokens,
+	}
+	queueAnalyzer, err := analyzer.NewQueueAnalyzer(qConfig, requestSize)
+	if err != nil {
+		return nil, err
+	}
+	return &Evaluator{queueAnalyzer: queueAnalyzer}, nil
+}
+
+// Predict the number of requests waiting and running across replicas, given the total arrival rate (requests/min)
+// evenly spread over the replicas. Replicas loaded beyond their maximum rate are predicted to have full queues.
+func (e *Evaluator) Predict(replicas int, arrivalRate float64) (Prediction, error) {
+	if replicas <= 0 || arrivalRate <= 0 {
+		return Prediction{}, nil
+	}
+	qa := e.queueAnalyzer
+	ratePerReplica := float32(arrivalRate / 60 / float64(replicas))
+	if ratePerReplica > qa.RateRange.Max {
+		return Prediction{
+			Waiting:   float64(replicas * qa.MaxQueueSize),
+			Running:   float64(replicas * qa.MaxBatchSize),
+			Saturated: true,
+		}, nil
+	}
+	metrics, err := qa.Analyze(ratePerReplica)
+	if err != nil {
+		return Prediction{}, err
+	}
+	return Prediction{
+		Waiting: float64(replicas) * float64(metrics.AvgQueueLength),
+		Running: float64(replicas) * float64(metrics.AvgNumInServ),
+		ITL:     float64(metrics.AvgTokenTime),
+		TTFT:    float64(metrics.AvgWaitTime + metrics.AvgPrefillTime),
+	}, nil
+}
+
+// Number of waiting requests one replica can drain within the TTFT SLO (msec) running at its maximum rate
+func (e *Evaluator) DrainablePerReplica(sloTTFT float64) float64 {
+	if sloTTFT <= 0 {
+		return 0
+	}
+	return float64(e.queueAnalyzer.RateRange.Max) * sloTTFT / 1000
+}
+
+// Number of replicas needed to drain the observed waiting requests within the TTFT SLO; the current number
+// of replicas if the backlog is within what the SLO allows (Little's law: waiting <= throughput * TTFT)
+func EmergencyReplicas(waiting float64, current int, drainablePerReplica float64) int {
+	if drainablePerReplica <= 0 || waiting <= float64(current)*drainablePerReplica {
+		return current
+	}
+	return max(current, int(math.Ceil(waiting/drainablePerReplica)))
+}
@@ -0,0 +1,141 @@
+package backlog
+
+import (
+	"testing"
+
+	llmdVariantAutoscalingV1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+)
+
+func testProfile() *llmdVariantAutoscalingV1alpha1.AcceleratorProfile {
+	return &llmdVariantAutoscalingV1alpha1.AcceleratorProfile{
+		Acc:      "A100",
+		AccCount: 1,
+		PerfParms: llmdVariantAutoscalingV1alpha1.PerfParms{
+			DecodeParms:  map[string]string{"alpha": "20.28", "beta": "0.72"},
+			PrefillParms: map[string]string{"gamma": "5.0", "delta": "0.01"},
+		},
+		MaxBatchSize: 8,
+	}
+}
+
+func TestNewEvaluatorErrors(t *testing.T) {
+	badParms := testProfile()
+	badParms.PerfParms.DecodeParms = map[string]string{"alpha": "x", "beta": "0.72"}
+
+	tests := []struct {
+		name         string
+		profile      *llmdVariantAutoscalingV1alpha1.AcceleratorProfile
+		avgOutTokens int
+	}{
+		{name: "bad perf parms", profile: badParms, avgOutTokens: 128},
+		{name: "no output tokens", profile: testProfile(), avgOutTokens: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEvaluator(tt.profile, 128, tt.avgOutTokens); err == nil {
+				t.Errorf("NewEvaluator() expected error")
+			}
+		})
+	}
+}
+
+func TestPredict(t *testing.T) {
+	e, err := NewEvaluator(testProfile(), 128, 128)
+	if err != nil {
+		t.Fatalf("NewEvaluator() unexpected error: %v", err)
+	}
+	maxRatePerMin := float64(e.queueAnalyzer.RateRange.Max) * 60
+
+	if got, err := e.Predict(0, 60); err != nil || got != (Prediction{}) {
+		t.Errorf("Predict(no replicas) = %+v, %v, want zero prediction", got, err)
+	}
+
+	light, err := e.Predict(2, 2*0.3*maxRatePerMin)
+	if err != nil {
+		t.Fatalf("Predict() unexpected error: %v", err)
+	}
+	heavy, err := e.Predict(2, 2*0.9*maxRatePerMin)
+	if err != nil {
+		t.Fatalf("Predict() unexpected error: %v", err)
+	}
+	if light.Running <= 0 || light.Running > 16 || light.Waiting < 0 {
+		t.Errorf("Predict(light) = %+v, want running in (0,16] and non-negative waiting", light)
+	}
+	if heavy.Waiting <= light.Waiting || heavy.Running <= light.Running {
+		t.Errorf("Predict(heavy) = %+v, want more waiting and running than light load %+v", heavy, light)
+	}
+	if light.ITL <= 0 || light.TTFT <= 0 || heavy.ITL <= light.ITL || heavy.TTFT <= light.TTFT {
+		t.Errorf("Predict() latencies light = %+v, heavy = %+v, want positive and increasing with load", light, heavy)
+	}
+
+	saturated, err := e.Predict(2, 2*2*maxRatePerMin)
+	if err != nil {
+		t.Fatalf("Predict() unexpected error: %v", err)
+	}
+	if want := (Prediction{Waiting: 160, Running: 16, Saturated: true}); saturated != want {
+		t.Errorf("Predict(saturated) = %+v, want %+v", saturated, want)
+	}
+}
+
+func TestNewEvaluatorWithBatchSize(t *testing.T) {
+	e, err := NewEvaluatorWithBatchSize(testProfile(), 4, 128, 128)
+	if err != nil {
+		t.Fatalf("NewEvaluatorWithBatchSize() unexpected error: %v", err)
+	}
+	maxRatePerMin := float64(e.queueAnalyzer.RateRange.Max) * 60
+	saturated, err := e.Predict(2, 2*2*maxRatePerMin)
+	if err != nil {
+		t.Fatalf("Predict() unexpected error: %v", err)
+	}
+	if want := (Prediction{Waiting: 80, Running: 8, Saturated: true}); saturated != want {
+		t.Errorf("Predict(saturated) = %+v, want %+v", saturated, want)
+	}
+
+	profileBatch, err := NewEvaluatorWithBatchSize(testProfile(), 0, 128, 128)
+	if err != nil {
+		t.Fatalf("NewEvaluatorWithBatchSize() unexpected error: %v", err)
+	}
+	if got := profileBatch.queueAnalyzer.MaxBatchSize; got != testProfile().MaxBatchSize {
+		t.Errorf("NewEvaluatorWithBatchSize(0) max batch size = %d, want %d", got, testProfile().MaxBatchSize)
+	}
+}
+
+func TestDrainablePerReplica(t *testing.T) {
+	e, err := NewEvaluator(testProfile(), 128, 128)
+	if err != nil {
+		t.Fatalf("NewEvaluator() unexpected error: %v", err)
+	}
+	if got := e.DrainablePerReplica(0); got != 0 {
+		t.Errorf("DrainablePerReplica(0) = %v, want 0", got)
+	}
+	oneSec := e.DrainablePerReplica(1000)
+	if oneSec <= 0 {
+		t.Fatalf("DrainablePerReplica(1000) = %v, want positive", oneSec)
+	}
+	if got := e.DrainablePerReplica(2000); got != 2*oneSec {
+		t.Errorf("DrainablePerReplica(2000) = %v, want %v", got, 2*oneSec)
+	}
+}
+
+func TestEmergencyReplicas(t *testing.T) {
+	tests := []struct {
+		name      string
+		waiting   float64
+		current   int
+		drainable float64
+		want      int
+	}{
+		{name: "no backlog", waiting: 0, current: 3, drainable: 10, want: 3},
+		{name: "backlog within SLO", waiting: 30, current: 3, drainable: 10, want: 3},
+		{name: "backlog above SLO", waiting: 45, current: 3, drainable: 10, want: 5},
+		{name: "backlog with no replicas", waiting: 5, current: 0, drainable: 10, want: 1},
+		{name: "unknown drain rate", waiting: 100, current: 2, drainable: 0, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EmergencyReplicas(tt.waiting, tt.current, tt.drainable); got != tt.want {
+				t.Errorf("EmergencyReplicas(%v, %d, %v) = %d, want %d", tt.waiting, tt.current, tt.drainable, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package budget
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	infernoConfig "github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+)
+
+// ConfigMap keys for cost budget options
+const (
+	KeyCostBudget          = "WVA_COST_BUDGET"
+	KeyNamespaceCostBudget = "WVA_NAMESPACE_COST_BUDGETS"
+)
+
+// Config holds the cost budget options, in the unit of the accelerator costs per hour; zero means unlimited
+type Config struct {
+	Total      float64            // cluster-wide ceiling on the total cost of the allocations
+	Namespaces map[string]float64 // ceilings on the total cost of the allocations of the variants in a namespace
+}
+
+// ConfigFromData parses cost budget options from the optimization ConfigMap data, ignoring missing or bad values.
+// Namespace ceilings are given as a comma-separated list of namespace=ceiling pairs.
+func ConfigFromData(data map[string]string) Config {
+	cfg := Config{
+		Namespaces: make(map[string]float64),
+	}
+	if val, ok := data[KeyCostBudget]; ok && val != "" {
+		if f, err := strconv.ParseFloat(val, 64); err == nil && f >= 0 {
+			cfg.Total = f
+		} else {
+			logger.Log.Warn("invalid cost budget, using no budget", "key", KeyCostBudget, "value", val)
+		}
+	}
+	for _, entry := range strings.Split(data[KeyNamespaceCostBudget], ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		namespace, val, found := strings.Cut(entry, "=")
+		namespace = strings.TrimSpace(namespace)
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if !found || namespace == "" || err != nil || f < 0 {
+			logger.Log.Warn("invalid namespace cost budget, ignoring", "key", KeyNamespaceCostBudget, "entry", entry)
+			continue
+		}
+		cfg.Namespaces[namespace] = f
+	}
+	return cfg
+}
+
+// Limited returns whether any ceiling is set
+func (c Config) Limited() bool {
+	if c.Total > 0 {
+		return true
+	}
+	for _, limit := range c.Namespaces {
+		if limit > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// BudgetData returns the ceilings as optimizer system data
+func (c Config) BudgetData() infernoConfig.BudgetData {
+	data := infernoConfig.BudgetData{
+		Total:      float32(c.Total),
+		Namespaces: make([]infernoConfig.NamespaceBudget, 0, len(c.Namespaces)),
+	}
+	namespaces := make([]string, 0, len(c.Namespaces))
+	for namespace := range c.Namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	slices.Sort(namespaces)
+	for _, namespace := range namespaces {
+		data.Namespaces = append(data.Namespaces, infernoConfig.NamespaceBudget{
+			Namespace: namespace,
+			Limit:     float32(c.Namespaces[namespace]),
+		})
+	}
+	return data
+}
@@ -0,0 +1,72 @@
+package budget
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	infernoConfig "github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.Log = zap.NewNop().Sugar()
+}
+
+func TestConfigFromData(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           map[string]string
+		wantTotal      float64
+		wantNamespaces map[string]float64
+		wantLimited    bool
+	}{
+		{name: "no budget", data: map[string]string{}, wantNamespaces: map[string]float64{}},
+		{
+			name:           "cluster budget",
+			data:           map[string]string{KeyCostBudget: "120.5"},
+			wantTotal:      120.5,
+			wantNamespaces: map[string]float64{},
+			wantLimited:    true,
+		},
+		{
+			name:           "namespace budgets",
+			data:           map[string]string{KeyNamespaceCostBudget: " team-a=40, team-b = 25.5 ,"},
+			wantNamespaces: map[string]float64{"team-a": 40, "team-b": 25.5},
+			wantLimited:    true,
+		},
+		{
+			name: "bad values ignored",
+			data: map[string]string{
+				KeyCostBudget:          "-1",
+				KeyNamespaceCostBudget: "team-a,=3,team-b=x,team-c=-2,team-d=10",
+			},
+			wantNamespaces: map[string]float64{"team-d": 10},
+			wantLimited:    true,
+		},
+		{
+			name:           "zero ceilings are unlimited",
+			data:           map[string]string{KeyCostBudget: "0", KeyNamespaceCostBudget: "team-a=0"},
+			wantNamespaces: map[string]float64{"team-a": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := ConfigFromData(tt.data)
+			if cfg.Total != tt.wantTotal || !maps.Equal(cfg.Namespaces, tt.wantNamespaces) {
+				t.Errorf("ConfigFromData() = %+v, want total %v and namespaces %v", cfg, tt.wantTotal, tt.wantNamespaces)
+			}
+			if cfg.Limited() != tt.wantLimited {
+				t.Errorf("Limited() = %v, want %v", cfg.Limited(), tt.wantLimited)
+			}
+		})
+	}
+}
+
+func TestBudgetData(t *testing.T) {
+	data := Config{Total: 100, Namespaces: map[string]float64{"b": 20, "a": 10}}.BudgetData()
+	want := []infernoConfig.NamespaceBudget{{Namespace: "a", Limit: 10}, {Namespace: "b", Limit: 20}}
+	if data.Total != 100 || len(data.Namespaces) != 2 || data.Namespaces[0] != want[0] || data.Namespaces[1] != want[1] {
+		t.Errorf("BudgetData() = %+v, want total 100 and namespaces %v", data, want)
+	}
+}
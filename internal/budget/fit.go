@@ -0,0 +1,80 @@
+package budget
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// relative tolerance of cost comparisons against the ceilings, as in the optimizer
+const tolerance = 1e-6
+
+// Allocation holds the replicas of a variant allocated by the optimizer, within the budget, and those applied after
+// the adjustments made afterwards, e.g., queue backlog scale-ups and scale-downs held within the startup latency
+type Allocation struct {
+	Namespace      string
+	Priority       int     // priority of the service class of the variant; lower values are more important
+	CostPerReplica float64 // cost of a replica on the allocated accelerator
+	Optimized      int     // replicas allocated by the optimizer
+	Applied        int     // replicas applied after adjustments
+}
+
+// Cost returns the cost of the applied allocations of the variants in a namespace, or of all variants if empty
+func Cost(allocs map[string]*Allocation, namespace string) float64 {
+	cost := 0.0
+	for _, alloc := range allocs {
+		if namespace == "" || alloc.Namespace == namespace {
+			cost += float64(alloc.Applied) * alloc.CostPerReplica
+		}
+	}
+	return cost
+}
+
+// Fit removes replicas applied beyond those allocated by the optimizer until the cost of the applied allocations fits
+// the ceilings, namespace ceilings first, then the cluster-wide ceiling, so that adjustments only use the headroom
+// left by the optimizer. Replicas are removed one at a time from the variant of the lowest priority, then the largest
+// cost per replica, then by name. It returns the number of replicas removed, by variant.
+func (c Config) Fit(allocs map[string]*Allocation) map[string]int {
+	removed := make(map[string]int)
+	for _, namespace := range slices.Sorted(maps.Keys(c.Namespaces)) {
+		if limit := c.Namespaces[namespace]; limit > 0 {
+			fit(allocs, namespace, limit, removed)
+		}
+	}
+	if c.Total > 0 {
+		fit(allocs, "", c.Total, removed)
+	}
+	return removed
+}
+
+// fit removes adjusted replicas of the variants in a namespace, or all variants if empty, until their cost fits the limit
+func fit(allocs map[string]*Allocation, namespace string, limit float64, removed map[string]int) {
+	names := slices.Sorted(maps.Keys(allocs))
+	cost := Cost(allocs, namespace)
+	for cost > limit*(1+tolerance) {
+		var next string
+		for _, name := range names {
+			alloc := allocs[name]
+			if (namespace != "" && alloc.Namespace != namespace) || alloc.Applied <= alloc.Optimized {
+				continue
+			}
+			if next == "" || before(alloc, allocs[next]) {
+				next = name
+			}
+		}
+		if next == "" {
+			return
+		}
+		allocs[next].Applied--
+		removed[next]++
+		cost -= allocs[next].CostPerReplica
+	}
+}
+
+// before returns whether a replica is removed from variant a before variant b
+func before(a, b *Allocation) bool {
+	if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+		return c < 0
+	}
+	return a.CostPerReplica > b.CostPerReplica
+}
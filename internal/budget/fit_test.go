@@ -0,0 +1,79 @@
+package budget
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		allocs      map[string]*Allocation
+		wantApplied map[string]int
+	}{
+		{
+			name: "scale-down held within the startup latency meets a budget cut",
+			cfg:  Config{Total: 40},
+			allocs: map[string]*Allocation{
+				// the optimizer cut the variant from 4 to 2 replicas, but the stabilizer holds it at 4
+				"a": {Namespace: "ns", Priority: 1, CostPerReplica: 20, Optimized: 2, Applied: 4},
+			},
+			wantApplied: map[string]int{"a": 2},
+		},
+		{
+			name: "adjustments within the headroom are kept",
+			cfg:  Config{Total: 100},
+			allocs: map[string]*Allocation{
+				"a": {Namespace: "ns", Priority: 1, CostPerReplica: 20, Optimized: 2, Applied: 3},
+				"b": {Namespace: "ns", Priority: 2, CostPerReplica: 10, Optimized: 1, Applied: 3},
+			},
+			wantApplied: map[string]int{"a": 3, "b": 3},
+		},
+		{
+			name: "lowest priority first",
+			cfg:  Config{Total: 80},
+			allocs: map[string]*Allocation{
+				"a": {Namespace: "ns", Priority: 1, CostPerReplica: 20, Optimized: 2, Applied: 3},
+				"b": {Namespace: "ns", Priority: 2, CostPerReplica: 10, Optimized: 1, Applied: 3},
+			},
+			wantApplied: map[string]int{"a": 3, "b": 2},
+		},
+		{
+			name: "optimized replicas are kept",
+			cfg:  Config{Total: 30},
+			allocs: map[string]*Allocation{
+				"a": {Namespace: "ns", Priority: 1, CostPerReplica: 20, Optimized: 2, Applied: 3},
+			},
+			wantApplied: map[string]int{"a": 2},
+		},
+		{
+			name: "namespace ceiling",
+			cfg:  Config{Namespaces: map[string]float64{"team-a": 40, "team-b": 0}},
+			allocs: map[string]*Allocation{
+				"a": {Namespace: "team-a", Priority: 1, CostPerReplica: 20, Optimized: 1, Applied: 3},
+				"b": {Namespace: "team-b", Priority: 1, CostPerReplica: 20, Optimized: 1, Applied: 3},
+			},
+			wantApplied: map[string]int{"a": 2, "b": 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := make(map[string]int)
+			for name, alloc := range tt.allocs {
+				before[name] = alloc.Applied
+			}
+			removed := tt.cfg.Fit(tt.allocs)
+			applied := make(map[string]int)
+			for name, alloc := range tt.allocs {
+				applied[name] = alloc.Applied
+				if removed[name] != before[name]-alloc.Applied {
+					t.Errorf("Fit() removed %d replicas of %s, want %d", removed[name], name, before[name]-alloc.Applied)
+				}
+			}
+			if !maps.Equal(applied, tt.wantApplied) {
+				t.Errorf("Fit() applied = %v, want %v", applied, tt.wantApplied)
+			}
+		})
+	}
+}
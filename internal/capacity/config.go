@@ -0,0 +1,150 @@
+package capacity
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	infernoConfig "github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+)
+
+// ConfigMap keys for accelerator capacity options
+const (
+	KeySolverMode                = "WVA_SOLVER_MODE"
+	KeyMIPTimeLimit              = "WVA_MIP_TIME_LIMIT"
+	KeyNamespaceAcceleratorQuota = "WVA_NAMESPACE_ACCELERATOR_QUOTAS"
+	KeySaturationPolicy          = "WVA_SATURATION_POLICY"
+)
+
+// Solver modes
+const (
+	// ModeUnlimited sizes each variant on its own, ignoring accelerator capacity
+	ModeUnlimited = "unlimited"
+	// ModeGreedy allocates the accelerator capacity by service class priority, with a greedy heuristic
+	ModeGreedy = "greedy"
+	// ModeMIP allocates the accelerator capacity by solving an integer program, within a time budget
+	ModeMIP = "mip"
+)
+
+// DefaultMIPTimeLimit is the time budget of the integer program solution, after which the best solution found is used
+const DefaultMIPTimeLimit = time.Duration(infernoConfig.DefaultMIPTimeLimitMsec) * time.Millisecond
+
+// saturationPolicies are the policies allocating the capacity left once the variants that fit meet their SLO
+var saturationPolicies = []infernoConfig.SaturatedAllocationPolicy{
+	infernoConfig.None,
+	infernoConfig.PriorityExhaustive,
+	infernoConfig.PriorityRoundRobin,
+	infernoConfig.RoundRobin,
+	infernoConfig.SLODeficit,
+}
+
+// Config holds the accelerator capacity options
+type Config struct {
+	Mode             string                    // ModeUnlimited, ModeGreedy or ModeMIP
+	MIPTimeLimit     time.Duration             // time budget of the integer program solution
+	SaturationPolicy string                    // allocation of the capacity left to the variants that do not fit
+	Quotas           map[string]map[string]int // units of accelerators the variants in a namespace may use, by namespace and accelerator name
+}
+
+// ConfigFromData parses accelerator capacity options from the optimization ConfigMap data, using defaults for
+// missing or bad values. Namespace quotas are given as a comma-separated list of namespace:accelerator=count entries.
+func ConfigFromData(data map[string]string) Config {
+	cfg := Config{
+		Mode:             ModeUnlimited,
+		MIPTimeLimit:     DefaultMIPTimeLimit,
+		SaturationPolicy: infernoConfig.DefaultSaturatedAllocationPolicy.String(),
+		Quotas:           make(map[string]map[string]int),
+	}
+	if val, ok := data[KeySolverMode]; ok && val != "" {
+		switch mode := strings.ToLower(strings.TrimSpace(val)); mode {
+		case ModeUnlimited, ModeGreedy, ModeMIP:
+			cfg.Mode = mode
+		default:
+			logger.Log.Warn("invalid solver mode, using default", "key", KeySolverMode, "value", val, "default", ModeUnlimited)
+		}
+	}
+	if val, ok := data[KeyMIPTimeLimit]; ok && val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= time.Millisecond {
+			cfg.MIPTimeLimit = d
+		} else {
+			logger.Log.Warn("invalid MIP time limit, using default", "key", KeyMIPTimeLimit, "value", val, "default", DefaultMIPTimeLimit)
+		}
+	}
+	if val, ok := data[KeySaturationPolicy]; ok && val != "" {
+		i := slices.IndexFunc(saturationPolicies, func(policy infernoConfig.SaturatedAllocationPolicy) bool {
+			return strings.EqualFold(policy.String(), strings.TrimSpace(val))
+		})
+		if i >= 0 {
+			cfg.SaturationPolicy = saturationPolicies[i].String()
+		} else {
+			logger.Log.Warn("invalid saturation policy, using default", "key", KeySaturationPolicy, "value", val,
+				"default", cfg.SaturationPolicy)
+		}
+	}
+	for _, entry := range strings.Split(data[KeyNamespaceAcceleratorQuota], ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, val, found := strings.Cut(entry, "=")
+		namespace, accName, _ := strings.Cut(key, ":")
+		namespace, accName = strings.TrimSpace(namespace), strings.TrimSpace(accName)
+		count, err := strconv.Atoi(strings.TrimSpace(val))
+		if !found || namespace == "" || accName == "" || err != nil || count < 0 {
+			logger.Log.Warn("invalid namespace accelerator quota, ignoring", "key", KeyNamespaceAcceleratorQuota, "entry", entry)
+			continue
+		}
+		if cfg.Quotas[namespace] == nil {
+			cfg.Quotas[namespace] = make(map[string]int)
+		}
+		cfg.Quotas[namespace][accName] = count
+	}
+	return cfg
+}
+
+// Limited returns whether the allocations are limited by the accelerator capacity
+func (c Config) Limited() bool {
+	return c.Mode != ModeUnlimited
+}
+
+// OptimizerSpec returns the optimizer spec of the solver mode
+func (c Config) OptimizerSpec() infernoConfig.OptimizerSpec {
+	return infernoConfig.OptimizerSpec{
+		Unlimited:        !c.Limited(),
+		SaturationPolicy: c.SaturationPolicy,
+		MIP:              c.Mode == ModeMIP,
+		MIPTimeLimitMsec: int(c.MIPTimeLimit.Milliseconds()),
+	}
+}
+
+// QuotaData returns the namespace quotas as optimizer system data, given the accelerator type of each accelerator
+// name; the quotas of accelerators of the same type add up, and those of unknown accelerators are ignored
+func (c Config) QuotaData(accTypes map[string]string) infernoConfig.QuotaData {
+	data := infernoConfig.QuotaData{
+		Spec: make([]infernoConfig.NamespaceQuota, 0, len(c.Quotas)),
+	}
+	for _, namespace := range slices.Sorted(maps.Keys(c.Quotas)) {
+		counts := make(map[string]int)
+		for accName, count := range c.Quotas[namespace] {
+			accType, ok := accTypes[accName]
+			if !ok {
+				logger.Log.Warn("accelerator quota of unknown accelerator, ignoring", "key", KeyNamespaceAcceleratorQuota,
+					"namespace", namespace, "accelerator", accName)
+				continue
+			}
+			counts[accType] += count
+		}
+		if len(counts) == 0 {
+			continue
+		}
+		quota := infernoConfig.NamespaceQuota{Namespace: namespace}
+		for _, accType := range slices.Sorted(maps.Keys(counts)) {
+			quota.Count = append(quota.Count, infernoConfig.AcceleratorCount{Type: accType, Count: counts[accType]})
+		}
+		data.Spec = append(data.Spec, quota)
+	}
+	return data
+}
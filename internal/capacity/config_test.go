@@ -0,0 +1,95 @@
+package capacity
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	infernoConfig "github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.Log = zap.NewNop().Sugar()
+}
+
+func TestConfigFromData(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]string
+		want Config
+	}{
+		{
+			name: "defaults",
+			data: map[string]string{},
+			want: Config{Mode: ModeUnlimited, MIPTimeLimit: DefaultMIPTimeLimit, SaturationPolicy: "None"},
+		},
+		{
+			name: "mip",
+			data: map[string]string{KeySolverMode: " MIP ", KeyMIPTimeLimit: "250ms", KeySaturationPolicy: " slodeficit"},
+			want: Config{Mode: ModeMIP, MIPTimeLimit: 250 * time.Millisecond, SaturationPolicy: "SLODeficit"},
+		},
+		{
+			name: "bad values",
+			data: map[string]string{KeySolverMode: "exact", KeyMIPTimeLimit: "0s", KeySaturationPolicy: "Unknown"},
+			want: Config{Mode: ModeUnlimited, MIPTimeLimit: DefaultMIPTimeLimit, SaturationPolicy: "None"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConfigFromData(tt.data)
+			if got.Mode != tt.want.Mode || got.MIPTimeLimit != tt.want.MIPTimeLimit ||
+				got.SaturationPolicy != tt.want.SaturationPolicy || len(got.Quotas) != 0 {
+				t.Errorf("ConfigFromData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigFromData_Quotas(t *testing.T) {
+	cfg := ConfigFromData(map[string]string{
+		KeyNamespaceAcceleratorQuota: " team-a:A100=8, team-a:H100 = 2 ,team-b:A100=0,team-c=3,:A100=1,team-d:A100=-1,team-e:A100=x,",
+	})
+	want := map[string]map[string]int{
+		"team-a": {"A100": 8, "H100": 2},
+		"team-b": {"A100": 0},
+	}
+	if !maps.EqualFunc(cfg.Quotas, want, maps.Equal[map[string]int]) {
+		t.Errorf("ConfigFromData() quotas = %v, want %v", cfg.Quotas, want)
+	}
+}
+
+func TestQuotaData(t *testing.T) {
+	cfg := Config{Quotas: map[string]map[string]int{
+		"team-b": {"A100": 4, "A100-PCIE": 2, "H100": 1},
+		"team-a": {"L4": 3},
+	}}
+	accTypes := map[string]string{"A100": "NVIDIA-A100", "A100-PCIE": "NVIDIA-A100", "H100": "NVIDIA-H100"}
+	want := infernoConfig.QuotaData{Spec: []infernoConfig.NamespaceQuota{{
+		Namespace: "team-b",
+		Count:     []infernoConfig.AcceleratorCount{{Type: "NVIDIA-A100", Count: 6}, {Type: "NVIDIA-H100", Count: 1}},
+	}}}
+	if got := cfg.QuotaData(accTypes); !reflect.DeepEqual(got, want) {
+		t.Errorf("QuotaData() = %+v, want %+v", got, want)
+	}
+}
+
+func TestOptimizerSpec(t *testing.T) {
+	tests := []struct {
+		mode string
+		want infernoConfig.OptimizerSpec
+	}{
+		{mode: ModeUnlimited, want: infernoConfig.OptimizerSpec{Unlimited: true, SaturationPolicy: "SLODeficit", MIPTimeLimitMsec: 1000}},
+		{mode: ModeGreedy, want: infernoConfig.OptimizerSpec{SaturationPolicy: "SLODeficit", MIPTimeLimitMsec: 1000}},
+		{mode: ModeMIP, want: infernoConfig.OptimizerSpec{MIP: true, SaturationPolicy: "SLODeficit", MIPTimeLimitMsec: 1000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			if got := (Config{Mode: tt.mode, MIPTimeLimit: time.Second, SaturationPolicy: "SLODeficit"}).OptimizerSpec(); got != tt.want {
+				t.Errorf("OptimizerSpec() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
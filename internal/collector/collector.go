@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"slices"
 	"strconv"
 	"time"
 
@@ -13,6 +14,9 @@ import (
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type AcceleratorModelInfo struct {
@@ -167,10 +171,7 @@ func AddMetricsToOptStatus(ctx context.Context,
 	// --- 1. Define Queries ---
 
 	// Metric 1: Arrival rate (requests per minute)
-	arrivalQuery := fmt.Sprintf(`sum(rate(%s{%s="%s",%s="%s"}[1m]))`,
-		constants.VLLMRequestSuccessTotal,
-		constants.LabelModelName, modelName,
-		constants.LabelNamespace, deployNamespace)
+	arrivalQuery := arrivalRateQuery(modelName, deployNamespace)
 
 	// Metric 2: Average prompt length (Input Tokens)
 	avgPromptToksQuery := fmt.Sprintf(`sum(rate(%s{%s="%s",%s="%s"}[1m]))/sum(rate(%s{%s="%s",%s="%s"}[1m]))`,
@@ -277,6 +278,190 @@ func AddMetricsToOptStatus(ctx context.Context,
 	return currentAlloc, nil
 }
 
+// CollectArrivalRateHistory returns the arrival rate (requests per minute) of a model over a lookback window,
+// sampled at the given step, oldest sample first
+func CollectArrivalRateHistory(ctx context.Context,
+	promAPI promv1.API,
+	modelName string,
+	namespace string,
+	lookback time.Duration,
+	step time.Duration) ([]float64, error) {
+
+	query := arrivalRateQuery(modelName, namespace)
+	end := time.Now()
+	r := promv1.Range{
+		Start: end.Add(-lookback),
+		End:   end,
+		Step:  step,
+	}
+	val, warn, err := promAPI.QueryRange(ctx, query, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Prometheus for ArrivalRateHistory: %w", err)
+	}
+	if warn != nil {
+		logger.Log.Warn("Prometheus warnings", "metric", "ArrivalRateHistory", "warnings", warn)
+	}
+	if val == nil || val.Type() != model.ValMatrix {
+		return nil, nil
+	}
+
+	matrix := val.(model.Matrix)
+	if len(matrix) == 0 {
+		return nil, nil
+	}
+	history := make([]float64, 0, len(matrix[0].Values))
+	for _, pair := range matrix[0].Values {
+		v := float64(pair.Value)
+		FixValue(&v)
+		history = append(history, v*60) // convert from req/sec to req/min
+	}
+	return history, nil
+}
+
+// QueueState is the number of requests waiting and running across the replicas of a model
+type QueueState struct {
+	Waiting float64
+	Running float64
+}
+
+// CollectQueueState returns the current number of waiting and running requests of a model in a namespace
+func CollectQueueState(ctx context.Context, promAPI promv1.API, modelName, namespace string) (QueueState, error) {
+	waitingQuery := fmt.Sprintf(`sum(%s{%s="%s",%s="%s"})`,
+		constants.VLLMNumRequestsWaiting,
+		constants.LabelModelName, modelName,
+		constants.LabelNamespace, namespace)
+	runningQuery := fmt.Sprintf(`sum(%s{%s="%s",%s="%s"})`,
+		constants.VLLMNumRequestsRunning,
+		constants.LabelModelName, modelName,
+		constants.LabelNamespace, namespace)
+
+	waiting, err := queryAndExtractMetric(ctx, promAPI, waitingQuery, "NumRequestsWaiting")
+	if err != nil {
+		return QueueState{}, err
+	}
+	running, err := queryAndExtractMetric(ctx, promAPI, runningQuery, "NumRequestsRunning")
+	if err != nil {
+		return QueueState{}, err
+	}
+	return QueueState{Waiting: waiting, Running: running}, nil
+}
+
+// KVCacheState is the KV cache usage of the replicas of a model
+type KVCacheState struct {
+	Usage          float64 // highest fraction of KV cache in use across replicas
+	AvgUsage       float64 // average fraction of KV cache in use per replica
+	PreemptionRate float64 // requests preempted per minute across replicas
+}
+
+// CollectKVCacheState returns the current KV cache usage and preemption rate of a model in a namespace
+func CollectKVCacheState(ctx context.Context, promAPI promv1.API, modelName, namespace string) (KVCacheState, error) {
+	usageQuery := fmt.Sprintf(`max(%s{%s="%s",%s="%s"})`,
+		constants.VLLMGPUCacheUsagePerc,
+		constants.LabelModelName, modelName,
+		constants.LabelNamespace, namespace)
+	avgUsageQuery := fmt.Sprintf(`avg(%s{%s="%s",%s="%s"})`,
+		constants.VLLMGPUCacheUsagePerc,
+		constants.LabelModelName, modelName,
+		constants.LabelNamespace, namespace)
+	preemptionQuery := fmt.Sprintf(`sum(rate(%s{%s="%s",%s="%s"}[1m]))`,
+		constants.VLLMNumPreemptionsTotal,
+		constants.LabelModelName, modelName,
+		constants.LabelNamespace, namespace)
+
+	usage, err := queryAndExtractMetric(ctx, promAPI, usageQuery, "GPUCacheUsage")
+	if err != nil {
+		return KVCacheState{}, err
+	}
+	avgUsage, err := queryAndExtractMetric(ctx, promAPI, avgUsageQuery, "AvgGPUCacheUsage")
+	if err != nil {
+		return KVCacheState{}, err
+	}
+	preemptionRate, err := queryAndExtractMetric(ctx, promAPI, preemptionQuery, "PreemptionRate")
+	if err != nil {
+		return KVCacheState{}, err
+	}
+	return KVCacheState{Usage: usage, AvgUsage: avgUsage, PreemptionRate: preemptionRate * 60}, nil // convert from per sec to per min
+}
+
+// arrivalRateQuery builds the query for the arrival rate (requests per second) of a model in a namespace
+func arrivalRateQuery(modelName, namespace string) string {
+	return fmt.Sprintf(`sum(rate(%s{%s="%s",%s="%s"}[1m]))`,
+		constants.VLLMRequestSuccessTotal,
+		constants.LabelModelName, modelName,
+		constants.LabelNamespace, namespace)
+}
+
+// EstimateStartupLatency returns the median time the ready pods of a deployment created since a given time took from
+// creation to readiness, which covers scheduling, image pull, and model load. The Ready condition only keeps its last
+// transition, so pods whose containers restarted, and pods created before the given time, are ignored, as their last
+// transition may be a recovery from a failed readiness probe rather than their first readiness.
+// Returns zero if no such pod is ready.
+func EstimateStartupLatency(ctx context.Context, k8sClient client.Client, deploy appsv1.Deployment, since time.Time) (time.Duration, error) {
+	if deploy.Spec.Selector == nil {
+		return 0, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return 0, fmt.Errorf("invalid selector for deployment %s/%s: %w", deploy.Namespace, deploy.Name, err)
+	}
+	var pods corev1.PodList
+	if err := k8sClient.List(ctx, &pods,
+		client.InNamespace(deploy.Namespace),
+		client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return 0, fmt.Errorf("failed to list pods for deployment %s/%s: %w", deploy.Namespace, deploy.Name, err)
+	}
+
+	latencies := make([]time.Duration, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		if !pod.DeletionTimestamp.IsZero() || pod.CreationTimestamp.Time.Before(since) || restarted(&pod) {
+			continue
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type != corev1.PodReady || cond.Status != corev1.ConditionTrue {
+				continue
+			}
+			if latency := cond.LastTransitionTime.Sub(pod.CreationTimestamp.Time); latency > 0 {
+				latencies = append(latencies, latency)
+			}
+		}
+	}
+	if len(latencies) == 0 {
+		return 0, nil
+	}
+	slices.Sort(latencies)
+	return latencies[len(latencies)/2], nil
+}
+
+// StartupLatencyFields returns a copy of a pod with only its identity and the fields EstimateStartupLatency reads,
+// so that pods are cached and recorded without their spec
+func StartupLatencyFields(pod *corev1.Pod) *corev1.Pod {
+	stripped := &corev1.Pod{
+		TypeMeta: pod.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              pod.Name,
+			Namespace:         pod.Namespace,
+			UID:               pod.UID,
+			ResourceVersion:   pod.ResourceVersion,
+			Labels:            pod.Labels,
+			CreationTimestamp: pod.CreationTimestamp,
+			DeletionTimestamp: pod.DeletionTimestamp,
+		},
+		Status: corev1.PodStatus{Conditions: pod.Status.Conditions},
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		stripped.Status.ContainerStatuses = append(stripped.Status.ContainerStatuses,
+			corev1.ContainerStatus{Name: status.Name, RestartCount: status.RestartCount})
+	}
+	return stripped
+}
+
+// restarted returns whether a container of a pod restarted
+func restarted(pod *corev1.Pod) bool {
+	return slices.ContainsFunc(pod.Status.ContainerStatuses, func(status corev1.ContainerStatus) bool {
+		return status.RestartCount > 0
+	})
+}
+
 // Helper to handle if a value is NaN or infinite
 func FixValue(x *float64) {
 	if math.IsNaN(*x) || math.IsInf(*x, 0) {
@@ -369,6 +369,209 @@ var _ = Describe("Collector", func() {
 		})
 	})
 
+	Context("When estimating startup latency", func() {
+		var deployment appsv1.Deployment
+
+		readyPod := func(name string, created time.Time, startup time.Duration) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         "test-ns",
+					Labels:            map[string]string{"app": "test"},
+					CreationTimestamp: metav1.NewTime(created),
+				},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{
+							Type:               corev1.PodReady,
+							Status:             corev1.ConditionTrue,
+							LastTransitionTime: metav1.NewTime(created.Add(startup)),
+						},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			deployment = appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment",
+					Namespace: "test-ns",
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+				},
+			}
+		})
+
+		It("should return the median time to readiness of ready pods", func() {
+			created := time.Now().Add(-time.Hour).Truncate(time.Second)
+			notReady := readyPod("pod-starting", created, 0)
+			notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+			otherApp := readyPod("pod-other", created, 30*time.Minute)
+			otherApp.Labels = map[string]string{"app": "other"}
+			// ready again after a restart, long after its first readiness
+			restartedPod := readyPod("pod-restarted", created, 50*time.Minute)
+			restartedPod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "vllm", RestartCount: 1}}
+			// created before the window, e.g., ready again after a failed readiness probe
+			oldPod := readyPod("pod-old", created.Add(-24*time.Hour), 24*time.Hour)
+
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				readyPod("pod-1", created, 2*time.Minute),
+				readyPod("pod-2", created, 3*time.Minute),
+				readyPod("pod-3", created, 10*time.Minute),
+				notReady,
+				otherApp,
+				restartedPod,
+				oldPod,
+			).Build()
+
+			latency, err := EstimateStartupLatency(ctx, fakeClient, deployment, created.Add(-time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(latency).To(Equal(3 * time.Minute))
+		})
+
+		It("should return zero when no pod is ready", func() {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			latency, err := EstimateStartupLatency(ctx, fakeClient, deployment, time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(latency).To(BeZero())
+		})
+	})
+
+	Context("When collecting arrival rate history", func() {
+		var (
+			mockProm      *utils.MockPromAPI
+			modelID       string
+			testNamespace string
+		)
+
+		BeforeEach(func() {
+			mockProm = &utils.MockPromAPI{
+				QueryResults:      make(map[string]model.Value),
+				QueryErrors:       make(map[string]error),
+				QueryRangeResults: make(map[string]model.Value),
+			}
+			modelID = "default/default"
+			testNamespace = "default"
+		})
+
+		It("should return the history in requests per minute", func() {
+			arrivalQuery := utils.CreateArrivalQuery(modelID, testNamespace)
+			mockProm.QueryRangeResults[arrivalQuery] = model.Matrix{
+				&model.SampleStream{
+					Values: []model.SamplePair{
+						{Timestamp: 0, Value: 0.5},
+						{Timestamp: 30000, Value: model.SampleValue(math.NaN())},
+						{Timestamp: 60000, Value: 1.0},
+					},
+				},
+			}
+
+			history, err := CollectArrivalRateHistory(ctx, mockProm, modelID, testNamespace, 5*time.Minute, 30*time.Second)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(Equal([]float64{30, 0, 60}))
+		})
+
+		It("should return no history when Prometheus has no samples", func() {
+			history, err := CollectArrivalRateHistory(ctx, mockProm, modelID, testNamespace, 5*time.Minute, 30*time.Second)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(BeEmpty())
+		})
+
+		It("should handle Prometheus QueryRange errors", func() {
+			arrivalQuery := utils.CreateArrivalQuery(modelID, testNamespace)
+			mockProm.QueryErrors[arrivalQuery] = fmt.Errorf("prometheus connection failed")
+
+			_, err := CollectArrivalRateHistory(ctx, mockProm, modelID, testNamespace, 5*time.Minute, 30*time.Second)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("prometheus connection failed"))
+		})
+	})
+
+	Context("When collecting queue state", func() {
+		var (
+			mockProm      *utils.MockPromAPI
+			modelID       string
+			testNamespace string
+			waitingQuery  string
+			runningQuery  string
+		)
+
+		BeforeEach(func() {
+			mockProm = &utils.MockPromAPI{
+				QueryResults: make(map[string]model.Value),
+				QueryErrors:  make(map[string]error),
+			}
+			modelID = "default/default"
+			testNamespace = "default"
+			waitingQuery = `sum(vllm:num_requests_waiting{model_name="default/default",namespace="default"})`
+			runningQuery = `sum(vllm:num_requests_running{model_name="default/default",namespace="default"})`
+		})
+
+		It("should return the waiting and running requests", func() {
+			mockProm.QueryResults[waitingQuery] = model.Vector{&model.Sample{Value: 12}}
+			mockProm.QueryResults[runningQuery] = model.Vector{&model.Sample{Value: 64}}
+
+			state, err := CollectQueueState(ctx, mockProm, modelID, testNamespace)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal(QueueState{Waiting: 12, Running: 64}))
+		})
+
+		It("should handle Prometheus query errors", func() {
+			mockProm.QueryErrors[runningQuery] = fmt.Errorf("prometheus connection failed")
+
+			_, err := CollectQueueState(ctx, mockProm, modelID, testNamespace)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("prometheus connection failed"))
+		})
+	})
+
+	Context("When collecting KV cache state", func() {
+		var (
+			mockProm        *utils.MockPromAPI
+			usageQuery      string
+			avgUsageQuery   string
+			preemptionQuery string
+		)
+
+		BeforeEach(func() {
+			mockProm = &utils.MockPromAPI{
+				QueryResults: make(map[string]model.Value),
+				QueryErrors:  make(map[string]error),
+			}
+			usageQuery = `max(vllm:gpu_cache_usage_perc{model_name="default/default",namespace="default"})`
+			avgUsageQuery = `avg(vllm:gpu_cache_usage_perc{model_name="default/default",namespace="default"})`
+			preemptionQuery = `sum(rate(vllm:num_preemptions_total{model_name="default/default",namespace="default"}[1m]))`
+		})
+
+		It("should return the highest and average usage and the preemptions per minute", func() {
+			mockProm.QueryResults[usageQuery] = model.Vector{&model.Sample{Value: 0.95}}
+			mockProm.QueryResults[avgUsageQuery] = model.Vector{&model.Sample{Value: 0.8}}
+			mockProm.QueryResults[preemptionQuery] = model.Vector{&model.Sample{Value: 0.5}}
+
+			state, err := CollectKVCacheState(ctx, mockProm, "default/default", "default")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal(KVCacheState{Usage: 0.95, AvgUsage: 0.8, PreemptionRate: 30}))
+		})
+
+		It("should handle Prometheus query errors", func() {
+			mockProm.QueryErrors[usageQuery] = fmt.Errorf("prometheus connection failed")
+
+			_, err := CollectKVCacheState(ctx, mockProm, "default/default", "default")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("prometheus connection failed"))
+		})
+	})
+
 	Context("When testing FixValue func", func() {
 		It("should fix NaN values", func() {
 			val := math.NaN()
@@ -0,0 +1,69 @@
+package constants
+
+// Kubernetes Event Reasons
+// These reasons are attached to the Events the controller records on VariantAutoscaling resources.
+const (
+	// EventReasonRecommendationChanged is recorded (Normal) when the optimized allocation of a variant changes.
+	EventReasonRecommendationChanged = "RecommendationChanged"
+
+	// EventReasonMissingModelID is recorded (Warning) when a variant is skipped because it has no model ID.
+	EventReasonMissingModelID = "MissingModelID"
+
+	// EventReasonSLONotFound is recorded (Warning) when a variant is skipped because no service class covers its model.
+	EventReasonSLONotFound = "SLONotFound"
+
+	// EventReasonInvalidModelProfile is recorded (Warning) when an accelerator profile of a variant cannot be parsed.
+	EventReasonInvalidModelProfile = "InvalidModelProfile"
+
+	// EventReasonMissingAcceleratorCost is recorded (Warning) when a variant is skipped because its accelerator
+	// has no cost, or an unparsable cost, in the accelerator ConfigMap.
+	EventReasonMissingAcceleratorCost = "MissingAcceleratorCost"
+
+	// EventReasonDeploymentNotFound is recorded (Warning) when a variant is skipped because its Deployment cannot be read.
+	EventReasonDeploymentNotFound = "DeploymentNotFound"
+
+	// EventReasonOwnerReferenceFailed is recorded (Warning) when a variant is skipped because its Deployment
+	// cannot be set as its owner.
+	EventReasonOwnerReferenceFailed = "OwnerReferenceFailed"
+
+	// EventReasonMetricsUnavailable is recorded (Warning) when a variant is skipped because its vLLM metrics
+	// are missing or cannot be collected.
+	EventReasonMetricsUnavailable = "MetricsUnavailable"
+
+	// EventReasonInvalidServerData is recorded (Warning) when a variant is skipped because its server data
+	// cannot be added to the optimization problem.
+	EventReasonInvalidServerData = "InvalidServerData"
+
+	// EventReasonOptimizationFailed is recorded (Warning) on every variant when the optimization fails.
+	EventReasonOptimizationFailed = "OptimizationFailed"
+
+	// EventReasonPerformanceModelDrift is recorded (Warning) when the latencies predicted for a variant start to
+	// persistently differ from the observed latencies.
+	EventReasonPerformanceModelDrift = "PerformanceModelDrift"
+
+	// EventReasonBudgetConstrained is recorded (Warning) when a variant starts to be allocated fewer replicas than
+	// needed to meet its SLO, to fit the cost budget.
+	EventReasonBudgetConstrained = "BudgetConstrained"
+
+	// EventReasonActuationFailed is recorded (Warning) when a step of the allocation plan executed by the direct
+	// actuator fails or does not converge in time.
+	EventReasonActuationFailed = "ActuationFailed"
+
+	// EventReasonAcceleratorChangeSkipped is recorded (Warning) when the direct actuator does not apply a new
+	// recommendation moving a variant to another accelerator.
+	EventReasonAcceleratorChangeSkipped = "AcceleratorChangeSkipped"
+)
+
+// Scaling Reasons
+// These values label inferno_replica_scaling_total with the cause of a change in the optimized number of replicas.
+const (
+	// ScalingReasonOptimization is a change recommended by the optimizer.
+	ScalingReasonOptimization = "optimization"
+
+	// ScalingReasonQueueBacklog is a scale-up to drain an observed backlog within the TTFT SLO.
+	ScalingReasonQueueBacklog = "queue_backlog"
+
+	// ScalingReasonCostBudget is a change of an allocation reduced below the replicas needed to meet the SLO to fit
+	// the cost budget.
+	ScalingReasonCostBudget = "cost_budget"
+)
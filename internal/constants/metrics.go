@@ -40,6 +40,22 @@ const (
 	// VLLMTimePerOutputTokenSecondsCount tracks the count of requests for time per output token.
 	// Used with VLLMTimePerOutputTokenSecondsSum to calculate ITL (Inter-Token Latency).
 	VLLMTimePerOutputTokenSecondsCount = "vllm:time_per_output_token_seconds_count"
+
+	// VLLMNumRequestsWaiting is a gauge of the number of requests waiting to be processed.
+	// Used to detect queue backlog.
+	VLLMNumRequestsWaiting = "vllm:num_requests_waiting"
+
+	// VLLMNumRequestsRunning is a gauge of the number of requests currently being processed.
+	// Used with VLLMNumRequestsWaiting to compare the observed queue state with the model's prediction.
+	VLLMNumRequestsRunning = "vllm:num_requests_running"
+
+	// VLLMGPUCacheUsagePerc is a gauge of the fraction of KV cache blocks in use (1 means 100 percent).
+	// Used to detect when the KV cache rather than the max number of sequences limits the batch size.
+	VLLMGPUCacheUsagePerc = "vllm:gpu_cache_usage_perc"
+
+	// VLLMNumPreemptionsTotal tracks the total number of requests preempted for lack of KV cache space.
+	// Used with VLLMGPUCacheUsagePerc to detect KV cache saturation.
+	VLLMNumPreemptionsTotal = "vllm:num_preemptions_total"
 )
 
 // Inferno Output Metrics
@@ -61,6 +77,139 @@ const (
 	// InfernoDesiredRatio is a gauge that tracks the ratio of desired to current replicas.
 	// Labels: variant_name, namespace, accelerator_type
 	InfernoDesiredRatio = "inferno_desired_ratio"
+
+	// InfernoObservedArrivalRate is a gauge that tracks the observed arrival rate (requests per minute).
+	// Labels: variant_name, namespace
+	InfernoObservedArrivalRate = "inferno_observed_arrival_rate"
+
+	// InfernoForecastArrivalRate is a gauge that tracks the forecast arrival rate (requests per minute)
+	// at the configured lead time, as used for sizing.
+	// Labels: variant_name, namespace
+	InfernoForecastArrivalRate = "inferno_forecast_arrival_rate"
+
+	// InfernoStartupLatencySeconds is a gauge that tracks the startup latency (seconds) used for each variant,
+	// either configured in the VariantAutoscaling spec or learned from pod readiness.
+	// Labels: variant_name, namespace
+	InfernoStartupLatencySeconds = "inferno_startup_latency_seconds"
+
+	// InfernoObservedRequestsWaiting is a gauge that tracks the observed number of waiting requests across replicas.
+	// Labels: variant_name, namespace
+	InfernoObservedRequestsWaiting = "inferno_observed_requests_waiting"
+
+	// InfernoPredictedRequestsWaiting is a gauge that tracks the number of waiting requests across replicas
+	// predicted by the queueing model for the current allocation and load.
+	// Labels: variant_name, namespace
+	InfernoPredictedRequestsWaiting = "inferno_predicted_requests_waiting"
+
+	// InfernoObservedRequestsRunning is a gauge that tracks the observed number of running requests across replicas.
+	// Labels: variant_name, namespace
+	InfernoObservedRequestsRunning = "inferno_observed_requests_running"
+
+	// InfernoPredictedRequestsRunning is a gauge that tracks the number of running requests across replicas
+	// predicted by the queueing model for the current allocation and load.
+	// Labels: variant_name, namespace
+	InfernoPredictedRequestsRunning = "inferno_predicted_requests_running"
+
+	// InfernoPredictedITLMsec is a gauge that tracks the average inter token latency (msec) predicted for the
+	// allocation recommended by the optimizer.
+	// Labels: variant_name, namespace, accelerator_type
+	InfernoPredictedITLMsec = "inferno_predicted_itl_ms"
+
+	// InfernoPredictedTTFTMsec is a gauge that tracks the average time to first token (msec) predicted for the
+	// allocation recommended by the optimizer.
+	// Labels: variant_name, namespace, accelerator_type
+	InfernoPredictedTTFTMsec = "inferno_predicted_ttft_ms"
+
+	// InfernoPredictedUtilization is a gauge that tracks the average fraction of the max batch in use predicted
+	// for the allocation recommended by the optimizer.
+	// Labels: variant_name, namespace, accelerator_type
+	InfernoPredictedUtilization = "inferno_predicted_utilization"
+
+	// InfernoMaxRPMPerReplica is a gauge that tracks the maximum request rate (requests per minute) a replica of
+	// the allocation recommended by the optimizer can serve within the SLO.
+	// Labels: variant_name, namespace, accelerator_type
+	InfernoMaxRPMPerReplica = "inferno_max_rpm_per_replica"
+
+	// InfernoSLOITLMsec is a gauge that tracks the inter token latency (time per output token) SLO (msec)
+	// of the service class of each variant.
+	// Labels: variant_name, namespace
+	InfernoSLOITLMsec = "inferno_slo_itl_ms"
+
+	// InfernoSLOTTFTMsec is a gauge that tracks the time to first token SLO (msec) of the service class of each variant.
+	// Labels: variant_name, namespace
+	InfernoSLOTTFTMsec = "inferno_slo_ttft_ms"
+
+	// InfernoITLPredictionError is a gauge that tracks the relative error of the inter token latency predicted for
+	// the current allocation and load against the observed one.
+	// Labels: variant_name, namespace
+	InfernoITLPredictionError = "inferno_itl_prediction_error"
+
+	// InfernoTTFTPredictionError is a gauge that tracks the relative error of the time to first token predicted for
+	// the current allocation and load against the observed one.
+	// Labels: variant_name, namespace
+	InfernoTTFTPredictionError = "inferno_ttft_prediction_error"
+
+	// InfernoPerformanceModelDrift is a gauge that is 1 while the PerformanceModelDrift condition of a variant is True, 0 otherwise.
+	// Labels: variant_name, namespace
+	InfernoPerformanceModelDrift = "inferno_performance_model_drift"
+
+	// InfernoBudgetReducedReplicas is a gauge that tracks the number of replicas removed from the allocation of each
+	// variant to fit the cost budget, i.e., how far it is below the replicas needed to meet its SLO.
+	// Labels: variant_name, namespace
+	InfernoBudgetReducedReplicas = "inferno_budget_reduced_replicas"
+
+	// InfernoCostBudget is a gauge that tracks the cost ceiling of the allocations, cluster-wide or of a namespace.
+	// Labels: scope (cluster or namespace), namespace (empty for the cluster scope)
+	InfernoCostBudget = "inferno_cost_budget"
+
+	// InfernoCostBudgetHeadroom is a gauge that tracks the cost remaining under a ceiling after the last
+	// optimization, negative if the ceiling cannot be met at the minimum number of replicas.
+	// Labels: scope (cluster or namespace), namespace (empty for the cluster scope)
+	InfernoCostBudgetHeadroom = "inferno_cost_budget_headroom"
+
+	// InfernoPlanStep is a gauge that tracks the position, starting at 1, of each step of the allocation plan of the
+	// last optimization: steps releasing accelerators come first, then steps acquiring them.
+	// Labels: variant_name, namespace, action (ScaleDown, Release or ScaleUp)
+	InfernoPlanStep = "inferno_plan_step"
+)
+
+// Inferno Self-Observability Metrics
+// These metric names are used to emit metrics about the controller itself: how long reconciliation,
+// Prometheus queries and the optimizer take, and how many variants are optimized or skipped.
+const (
+	// InfernoControllerLeader is a gauge that is 1 on the elected leader replica of the controller, 0 on the others.
+	// Labels: none
+	InfernoControllerLeader = "inferno_controller_leader"
+
+	// InfernoReconcileDurationSeconds is a histogram of the duration of a reconciliation (global optimization cycle).
+	InfernoReconcileDurationSeconds = "inferno_reconcile_duration_seconds"
+
+	// InfernoPrometheusQueryDurationSeconds is a histogram of the duration of Prometheus queries.
+	// Labels: query (the metric the query reads)
+	InfernoPrometheusQueryDurationSeconds = "inferno_prometheus_query_duration_seconds"
+
+	// InfernoPrometheusQueryErrorsTotal is a counter of failed Prometheus queries.
+	// Labels: query (the metric the query reads)
+	InfernoPrometheusQueryErrorsTotal = "inferno_prometheus_query_errors_total"
+
+	// InfernoOptimizationDurationSeconds is a histogram of the time the solver takes to find the optimized allocations.
+	InfernoOptimizationDurationSeconds = "inferno_optimization_duration_seconds"
+
+	// InfernoVariantsProcessed is a histogram of the number of variants included in an optimization cycle.
+	InfernoVariantsProcessed = "inferno_variants_processed"
+
+	// InfernoVariantsSkipped is a histogram of the number of variants skipped from an optimization cycle.
+	// Labels: reason (the reason of the condition reporting why the variant was skipped)
+	InfernoVariantsSkipped = "inferno_variants_skipped"
+
+	// InfernoLastSuccessfulOptimizationTimestampSeconds is a gauge of the Unix time of the last successful optimization.
+	InfernoLastSuccessfulOptimizationTimestampSeconds = "inferno_last_successful_optimization_timestamp_seconds"
+)
+
+// Cost budget scopes, the values of the scope label
+const (
+	BudgetScopeCluster   = "cluster"
+	BudgetScopeNamespace = "namespace"
 )
 
 // Metric Label Names
@@ -72,4 +221,7 @@ const (
 	LabelDirection       = "direction"
 	LabelReason          = "reason"
 	LabelAcceleratorType = "accelerator_type"
+	LabelQuery           = "query"
+	LabelScope           = "scope"
+	LabelAction          = "action"
 )
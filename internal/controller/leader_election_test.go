@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/metrics"
+)
+
+var _ = Describe("Leader election", func() {
+	// start a manager competing for the same lease, with its own gate of the recommendation series
+	startReplica := func() (*metrics.LeaderGate, context.CancelFunc, chan error) {
+		leaseDuration := 2 * time.Second
+		renewDeadline := time.Second
+		retryPeriod := 200 * time.Millisecond
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			Scheme:                        scheme.Scheme,
+			Metrics:                       metricsserver.Options{BindAddress: "0"},
+			HealthProbeBindAddress:        "0",
+			LeaderElection:                true,
+			LeaderElectionID:              "leader-failover-test.llm-d.ai",
+			LeaderElectionNamespace:       "default",
+			LeaderElectionReleaseOnCancel: true,
+			LeaseDuration:                 &leaseDuration,
+			RenewDeadline:                 &renewDeadline,
+			RetryPeriod:                   &retryPeriod,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		gate := metrics.NewLeaderGate()
+		gate.EnableLeaderElection()
+		Expect(mgr.Add(gate)).To(Succeed())
+
+		mgrCtx, mgrCancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			done <- mgr.Start(mgrCtx)
+		}()
+		return gate, mgrCancel, done
+	}
+
+	It("should export recommendations only from the leader, and hand over on failover", func() {
+		By("electing the first replica")
+		first, stopFirst, firstDone := startReplica()
+		Eventually(first.IsLeader, 10*time.Second, 100*time.Millisecond).Should(BeTrue())
+		Expect(first.Exporting()).To(BeTrue())
+
+		By("keeping the second replica a follower while the first one leads")
+		second, stopSecond, secondDone := startReplica()
+		defer func() {
+			stopSecond()
+			Eventually(secondDone, 10*time.Second).Should(Receive())
+		}()
+		Consistently(second.Exporting, 3*time.Second, 100*time.Millisecond).Should(BeFalse())
+
+		By("stopping the first replica")
+		stopFirst()
+		Eventually(firstDone, 10*time.Second).Should(Receive())
+		Expect(first.IsLeader()).To(BeFalse())
+
+		By("electing the second replica")
+		Eventually(second.IsLeader, 10*time.Second, 100*time.Millisecond).Should(BeTrue())
+		Expect(second.Exporting()).To(BeTrue())
+	})
+})
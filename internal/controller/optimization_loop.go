@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+)
+
+const (
+	// minOptimizationInterval is the minimum time between the starts of two global optimizations, however often
+	// they are triggered
+	minOptimizationInterval = 5 * time.Second
+
+	// defaultOptimizationInterval is the time until the next global optimization after one fails
+	defaultOptimizationInterval = 60 * time.Second
+)
+
+// optimizationLoop runs the global optimization of all variants on the elected leader, periodically and whenever
+// triggered. Triggers received while an optimization is waiting or running are coalesced into the next one.
+type optimizationLoop struct {
+	optimize    func(ctx context.Context) (time.Duration, error)
+	trigger     chan struct{}
+	minInterval time.Duration
+}
+
+// Create an optimization loop calling the given function, which returns the interval until the next periodic run
+func newOptimizationLoop(optimize func(ctx context.Context) (time.Duration, error), minInterval time.Duration) *optimizationLoop {
+	return &optimizationLoop{
+		optimize:    optimize,
+		trigger:     make(chan struct{}, 1),
+		minInterval: minInterval,
+	}
+}
+
+// Trigger an optimization without waiting for the next periodic one; never blocks
+func (l *optimizationLoop) Trigger() {
+	select {
+	case l.trigger <- struct{}{}:
+	default:
+		// an optimization is already pending
+	}
+}
+
+// NeedLeaderElection makes the manager run the loop only on the elected replica
+func (l *optimizationLoop) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs an optimization right away, and then on every trigger or at the end of the interval returned by the
+// last one, at most once every minimum interval, until the context is done
+func (l *optimizationLoop) Start(ctx context.Context) error {
+	var lastRun time.Time
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		case <-l.trigger:
+		}
+
+		if wait := time.Until(lastRun.Add(l.minInterval)); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(wait):
+			}
+		}
+		// triggers received until now are served by this run
+		select {
+		case <-l.trigger:
+		default:
+		}
+
+		lastRun = time.Now()
+		interval, err := l.optimize(ctx)
+		if err != nil {
+			logger.Log.Error(err, "global optimization failed")
+			interval = defaultOptimizationInterval
+		}
+		timer.Reset(interval)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Optimization loop", func() {
+	// start a loop whose optimization takes the given time, returning the number of runs so far
+	startLoop := func(duration, minInterval time.Duration) (*optimizationLoop, *atomic.Int32, context.CancelFunc) {
+		runs := &atomic.Int32{}
+		loop := newOptimizationLoop(func(ctx context.Context) (time.Duration, error) {
+			runs.Add(1)
+			time.Sleep(duration)
+			return time.Hour, nil
+		}, minInterval)
+		loopCtx, loopCancel := context.WithCancel(ctx)
+		go func() {
+			defer GinkgoRecover()
+			Expect(loop.Start(loopCtx)).To(Succeed())
+		}()
+		return loop, runs, loopCancel
+	}
+
+	It("should optimize once on start", func() {
+		_, runs, stop := startLoop(0, 0)
+		defer stop()
+		Eventually(runs.Load).Should(Equal(int32(1)))
+		Consistently(runs.Load, 200*time.Millisecond).Should(Equal(int32(1)))
+	})
+
+	It("should coalesce triggers received while optimizing", func() {
+		loop, runs, stop := startLoop(200*time.Millisecond, 0)
+		defer stop()
+		Eventually(runs.Load).Should(Equal(int32(1)))
+		for range 10 {
+			loop.Trigger()
+		}
+		Eventually(runs.Load).Should(Equal(int32(2)))
+		Consistently(runs.Load, 500*time.Millisecond).Should(Equal(int32(2)))
+	})
+
+	It("should optimize at most once every minimum interval", func() {
+		loop, runs, stop := startLoop(0, 500*time.Millisecond)
+		defer stop()
+		Eventually(runs.Load).Should(Equal(int32(1)))
+		loop.Trigger()
+		Consistently(runs.Load, 300*time.Millisecond).Should(Equal(int32(1)))
+		Eventually(runs.Load).Should(Equal(int32(2)))
+	})
+
+	It("should not block triggers when not running", func() {
+		loop := newOptimizationLoop(func(ctx context.Context) (time.Duration, error) {
+			return time.Hour, nil
+		}, 0)
+		loop.Trigger()
+		loop.Trigger()
+		Expect(loop.NeedLeaderElection()).To(BeTrue())
+	})
+})
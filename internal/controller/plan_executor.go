@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"sync"
+)
+
+// planExecutor executes the allocation plans of the direct actuator on the elected leader, outside the optimization
+// loop, one at a time. A plan submitted while another is executing supersedes it: the executing plan is cancelled,
+// and the new one starts as soon as it returns.
+type planExecutor struct {
+	pending chan func(ctx context.Context)
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // cancels the executing plan; nil if none
+}
+
+func newPlanExecutor() *planExecutor {
+	return &planExecutor{
+		pending: make(chan func(ctx context.Context), 1),
+	}
+}
+
+// Submit the execution of a plan, cancelling the executing one and replacing any pending one; never blocks
+func (e *planExecutor) Submit(execute func(ctx context.Context)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cancel != nil {
+		e.cancel()
+	}
+	select {
+	case <-e.pending:
+	default:
+	}
+	e.pending <- execute
+}
+
+// NeedLeaderElection makes the manager run the executor only on the elected replica
+func (e *planExecutor) NeedLeaderElection() bool {
+	return true
+}
+
+// Start executes the submitted plans in turn until the context is done, which cancels the executing one
+func (e *planExecutor) Start(ctx context.Context) error {
+	for {
+		var execute func(ctx context.Context)
+		select {
+		case <-ctx.Done():
+			return nil
+		case execute = <-e.pending:
+		}
+
+		planCtx, cancel := context.WithCancel(ctx)
+		e.mu.Lock()
+		e.cancel = cancel
+		if len(e.pending) > 0 {
+			// superseded before it started
+			cancel()
+		}
+		e.mu.Unlock()
+
+		execute(planCtx)
+
+		e.mu.Lock()
+		e.cancel = nil
+		e.mu.Unlock()
+		cancel()
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plan executor", func() {
+	startExecutor := func() (*planExecutor, context.CancelFunc) {
+		executor := newPlanExecutor()
+		executorCtx, executorCancel := context.WithCancel(ctx)
+		go func() {
+			defer GinkgoRecover()
+			Expect(executor.Start(executorCtx)).To(Succeed())
+		}()
+		return executor, executorCancel
+	}
+
+	It("should cancel the executing plan when a new one is submitted", func() {
+		executor, stop := startExecutor()
+		defer stop()
+
+		started, cancelled, executed := &atomic.Bool{}, &atomic.Bool{}, &atomic.Bool{}
+		executor.Submit(func(ctx context.Context) {
+			started.Store(true)
+			<-ctx.Done()
+			cancelled.Store(true)
+		})
+		Eventually(started.Load).Should(BeTrue())
+
+		executor.Submit(func(ctx context.Context) {
+			executed.Store(ctx.Err() == nil)
+		})
+		Eventually(cancelled.Load).Should(BeTrue())
+		Eventually(executed.Load).Should(BeTrue())
+	})
+
+	It("should only execute the last of the plans submitted while executing", func() {
+		executor, stop := startExecutor()
+		defer stop()
+
+		started, release := &atomic.Bool{}, make(chan struct{})
+		executor.Submit(func(ctx context.Context) {
+			started.Store(true)
+			<-release
+		})
+		Eventually(started.Load).Should(BeTrue())
+		runs := &atomic.Int32{}
+		for range 5 {
+			executor.Submit(func(ctx context.Context) {
+				runs.Add(1)
+			})
+		}
+		close(release)
+		Eventually(runs.Load).Should(Equal(int32(1)))
+		Consistently(runs.Load, 200*time.Millisecond).Should(Equal(int32(1)))
+		Expect(executor.NeedLeaderElection()).To(BeTrue())
+	})
+})
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	llmdVariantAutoscalingV1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+)
+
+// triggerOptimizationHandler triggers the global optimization on any event, rather than enqueueing a reconcile
+func triggerOptimizationHandler[T client.Object](r *VariantAutoscalingReconciler) handler.TypedEventHandler[T, reconcile.Request] {
+	return handler.TypedFuncs[T, reconcile.Request]{
+		CreateFunc: func(_ context.Context, _ event.TypedCreateEvent[T], _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.triggerOptimization()
+		},
+		UpdateFunc: func(_ context.Context, _ event.TypedUpdateEvent[T], _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.triggerOptimization()
+		},
+		DeleteFunc: func(_ context.Context, _ event.TypedDeleteEvent[T], _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.triggerOptimization()
+		},
+	}
+}
+
+// variantAutoscalingPredicate passes the creation and deletion of a variant, and changes to its spec, which
+// bump its generation; status updates made by the controller itself are filtered out
+func variantAutoscalingPredicate() predicate.TypedPredicate[*llmdVariantAutoscalingV1alpha1.VariantAutoscaling] {
+	return predicate.TypedFuncs[*llmdVariantAutoscalingV1alpha1.VariantAutoscaling]{
+		CreateFunc: func(e event.TypedCreateEvent[*llmdVariantAutoscalingV1alpha1.VariantAutoscaling]) bool {
+			return true
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*llmdVariantAutoscalingV1alpha1.VariantAutoscaling]) bool {
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*llmdVariantAutoscalingV1alpha1.VariantAutoscaling]) bool {
+			return true
+		},
+		GenericFunc: func(e event.TypedGenericEvent[*llmdVariantAutoscalingV1alpha1.VariantAutoscaling]) bool {
+			return false
+		},
+	}
+}
+
+// deploymentPredicate passes the creation and deletion of a Deployment, and changes to its pod template; changes to
+// its desired replicas, made by the HPA or the actuator to apply a recommendation, are filtered out, as they would
+// trigger an optimization for every scaling it recommends, as are status updates, e.g., replicas becoming ready
+func deploymentPredicate() predicate.TypedPredicate[*appsv1.Deployment] {
+	return predicate.TypedFuncs[*appsv1.Deployment]{
+		CreateFunc: func(e event.TypedCreateEvent[*appsv1.Deployment]) bool {
+			return true
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*appsv1.Deployment]) bool {
+			return !equality.Semantic.DeepEqual(e.ObjectOld.Spec.Template, e.ObjectNew.Spec.Template)
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*appsv1.Deployment]) bool {
+			return true
+		},
+		GenericFunc: func(e event.TypedGenericEvent[*appsv1.Deployment]) bool {
+			return false
+		},
+	}
+}
+
+// configMapPredicate passes the creation and deletion of the configuration ConfigMaps in the given namespace, and
+// changes to their data
+func configMapPredicate(namespace string) predicate.TypedPredicate[*corev1.ConfigMap] {
+	isConfigMap := func(cm *corev1.ConfigMap) bool {
+		if cm.GetNamespace() != namespace {
+			return false
+		}
+		switch cm.GetName() {
+		case configMapName, acceleratorConfigMapName, serviceClassConfigMapName:
+			return true
+		}
+		return false
+	}
+	return predicate.TypedFuncs[*corev1.ConfigMap]{
+		CreateFunc: func(e event.TypedCreateEvent[*corev1.ConfigMap]) bool {
+			return isConfigMap(e.Object)
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*corev1.ConfigMap]) bool {
+			return isConfigMap(e.ObjectNew) &&
+				(!equality.Semantic.DeepEqual(e.ObjectOld.Data, e.ObjectNew.Data) ||
+					!equality.Semantic.DeepEqual(e.ObjectOld.BinaryData, e.ObjectNew.BinaryData))
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*corev1.ConfigMap]) bool {
+			return isConfigMap(e.Object)
+		},
+		GenericFunc: func(e event.TypedGenericEvent[*corev1.ConfigMap]) bool {
+			return false
+		},
+	}
+}
+
+// deploymentVariant maps a Deployment to the reconcile of its variant, i.e., the variant of the same name in its
+// namespace, if any
+func (r *VariantAutoscalingReconciler) deploymentVariant(ctx context.Context, deploy *appsv1.Deployment) []reconcile.Request {
+	key := types.NamespacedName{Name: deploy.Name, Namespace: deploy.Namespace}
+	var va llmdVariantAutoscalingV1alpha1.VariantAutoscaling
+	if err := r.Get(ctx, key, &va); err != nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: key}}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	llmdv1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+)
+
+var _ = Describe("Reconcile predicates", func() {
+	Context("VariantAutoscaling", func() {
+		p := variantAutoscalingPredicate()
+		va := func(generation int64) *llmdv1alpha1.VariantAutoscaling {
+			return &llmdv1alpha1.VariantAutoscaling{ObjectMeta: metav1.ObjectMeta{Name: "va", Namespace: "ns", Generation: generation}}
+		}
+
+		It("should pass creation and deletion", func() {
+			Expect(p.Create(event.TypedCreateEvent[*llmdv1alpha1.VariantAutoscaling]{Object: va(1)})).To(BeTrue())
+			Expect(p.Delete(event.TypedDeleteEvent[*llmdv1alpha1.VariantAutoscaling]{Object: va(1)})).To(BeTrue())
+		})
+
+		It("should pass spec changes and filter status updates", func() {
+			Expect(p.Update(event.TypedUpdateEvent[*llmdv1alpha1.VariantAutoscaling]{ObjectOld: va(1), ObjectNew: va(2)})).To(BeTrue())
+
+			updated := va(1)
+			updated.Status.DesiredOptimizedAlloc.NumReplicas = 3
+			Expect(p.Update(event.TypedUpdateEvent[*llmdv1alpha1.VariantAutoscaling]{ObjectOld: va(1), ObjectNew: updated})).To(BeFalse())
+		})
+	})
+
+	Context("Deployment", func() {
+		p := deploymentPredicate()
+		deploy := func(replicas int32, image string) *appsv1.Deployment {
+			return &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "va", Namespace: "ns"},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: &replicas,
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "vllm", Image: image}}},
+					},
+				},
+			}
+		}
+
+		It("should pass pod template changes", func() {
+			Expect(p.Update(event.TypedUpdateEvent[*appsv1.Deployment]{ObjectOld: deploy(1, "v1"), ObjectNew: deploy(1, "v2")})).To(BeTrue())
+			Expect(p.Delete(event.TypedDeleteEvent[*appsv1.Deployment]{Object: deploy(1, "v1")})).To(BeTrue())
+		})
+
+		It("should filter replica changes", func() {
+			Expect(p.Update(event.TypedUpdateEvent[*appsv1.Deployment]{ObjectOld: deploy(1, "v1"), ObjectNew: deploy(2, "v1")})).To(BeFalse())
+		})
+
+		It("should filter status updates", func() {
+			updated := deploy(1, "v1")
+			updated.Status.ReadyReplicas = 1
+			Expect(p.Update(event.TypedUpdateEvent[*appsv1.Deployment]{ObjectOld: deploy(1, "v1"), ObjectNew: updated})).To(BeFalse())
+		})
+	})
+
+	Context("ConfigMap", func() {
+		p := configMapPredicate(DefaultConfigNamespace)
+		cm := func(name, namespace, value string) *corev1.ConfigMap {
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Data:       map[string]string{"key": value},
+			}
+		}
+
+		It("should pass data changes of the configuration ConfigMaps", func() {
+			for _, name := range []string{configMapName, acceleratorConfigMapName, serviceClassConfigMapName} {
+				Expect(p.Update(event.TypedUpdateEvent[*corev1.ConfigMap]{
+					ObjectOld: cm(name, DefaultConfigNamespace, "a"), ObjectNew: cm(name, DefaultConfigNamespace, "b")})).To(BeTrue(), name)
+				Expect(p.Create(event.TypedCreateEvent[*corev1.ConfigMap]{Object: cm(name, DefaultConfigNamespace, "a")})).To(BeTrue(), name)
+			}
+		})
+
+		It("should filter other ConfigMaps and unchanged data", func() {
+			Expect(p.Update(event.TypedUpdateEvent[*corev1.ConfigMap]{
+				ObjectOld: cm(configMapName, DefaultConfigNamespace, "a"), ObjectNew: cm(configMapName, DefaultConfigNamespace, "a")})).To(BeFalse())
+			Expect(p.Create(event.TypedCreateEvent[*corev1.ConfigMap]{Object: cm("other", DefaultConfigNamespace, "a")})).To(BeFalse())
+			Expect(p.Create(event.TypedCreateEvent[*corev1.ConfigMap]{Object: cm(configMapName, "other", "a")})).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	llmdVariantAutoscalingV1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+	actuator "github.com/llm-d-incubation/workload-variant-autoscaler/internal/actuator"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/snapshot"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/trace"
+)
+
+// ReplayedVariant is the recommendation for a variant in a replayed optimization cycle
+type ReplayedVariant struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Accelerator string `json:"accelerator"`
+	NumReplicas int    `json:"numReplicas"`
+	Optimized   bool   `json:"optimized"`        // optimization succeeded for the variant in the cycle
+	Reason      string `json:"reason,omitempty"` // reason for the recommendation
+}
+
+// ReplayedCycle holds the recommendations of a replayed optimization cycle
+type ReplayedCycle struct {
+	Time     time.Time         `json:"time"`
+	Variants []ReplayedVariant `json:"variants"`
+}
+
+// Replay runs recorded optimization cycles in order, without a cluster or Prometheus: each cycle reads its recorded
+// objects from a fake client and gets its recorded query results, at its recorded time. State kept across cycles
+// (scale-down stabilization, forecasts, drift) carries over as it does in the controller. The recorded actuation
+// mode is ignored, as there are no Deployments to scale, and no snapshot ConfigMap is written.
+func Replay(ctx context.Context, scheme *runtime.Scheme, cycles []*trace.Cycle) ([]ReplayedCycle, error) {
+	r := NewVariantAutoscalingReconciler(nil, scheme)
+	replayed := make([]ReplayedCycle, 0, len(cycles))
+	for i, cycle := range cycles {
+		objs, err := cycle.DecodeObjects(scheme)
+		if err != nil {
+			return nil, fmt.Errorf("cycle %d: %w", i+1, err)
+		}
+		for _, obj := range objs {
+			switch o := obj.(type) {
+			case *corev1.ConfigMap:
+				if o.Name == configMapName && o.Namespace == cycle.ConfigNamespace {
+					delete(o.Data, actuator.KeyActuationMode)
+					delete(o.Data, snapshot.KeySnapshotConfigMap)
+				}
+			case *llmdVariantAutoscalingV1alpha1.VariantAutoscaling:
+				// drop the recorded recommendation, so that only those of the replay are reported
+				o.Status.DesiredOptimizedAlloc = llmdVariantAutoscalingV1alpha1.OptimizedAlloc{}
+				o.Status.Recommendation = nil
+				meta.RemoveStatusCondition(&o.Status.Conditions, llmdVariantAutoscalingV1alpha1.TypeOptimizationReady)
+			}
+		}
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&llmdVariantAutoscalingV1alpha1.VariantAutoscaling{}).
+			Build()
+
+		r.Client = k8sClient
+		r.PromAPI = trace.ReplayPromAPI(cycle)
+		r.Scope = Scope{ConfigNamespace: cycle.ConfigNamespace}
+		cycleTime := cycle.Time
+		r.clock = func() time.Time { return cycleTime }
+		if _, err := r.optimize(ctx); err != nil {
+			return nil, fmt.Errorf("cycle %d: %w", i+1, err)
+		}
+
+		var vaList llmdVariantAutoscalingV1alpha1.VariantAutoscalingList
+		if err := k8sClient.List(ctx, &vaList); err != nil {
+			return nil, fmt.Errorf("cycle %d: %w", i+1, err)
+		}
+		replayed = append(replayed, ReplayedCycle{Time: cycle.Time, Variants: replayedVariants(vaList.Items)})
+	}
+	return replayed, nil
+}
+
+// replayedVariants returns the recommendations in the status of variants, by namespace and name
+func replayedVariants(items []llmdVariantAutoscalingV1alpha1.VariantAutoscaling) []ReplayedVariant {
+	variants := make([]ReplayedVariant, 0, len(items))
+	for _, va := range items {
+		variant := ReplayedVariant{
+			Name:        va.Name,
+			Namespace:   va.Namespace,
+			Accelerator: va.Status.DesiredOptimizedAlloc.Accelerator,
+			NumReplicas: va.Status.DesiredOptimizedAlloc.NumReplicas,
+			Optimized: meta.IsStatusConditionTrue(va.Status.Conditions,
+				llmdVariantAutoscalingV1alpha1.TypeOptimizationReady),
+		}
+		if va.Status.Recommendation != nil {
+			variant.Reason = va.Status.Recommendation.Reason
+		}
+		variants = append(variants, variant)
+	}
+	slices.SortFunc(variants, func(a, b ReplayedVariant) int {
+		if c := strings.Compare(a.Namespace, b.Namespace); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return variants
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	llmdVariantAutoscalingV1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+	logger "github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/trace"
+	utils "github.com/llm-d-incubation/workload-variant-autoscaler/internal/utils"
+	testutils "github.com/llm-d-incubation/workload-variant-autoscaler/test/utils"
+)
+
+var _ = Describe("Optimization cycle replay", func() {
+	const (
+		name      = "replay-test-resource"
+		namespace = "default"
+		modelID   = "default/default"
+	)
+
+	var (
+		replayCtx    context.Context
+		replayScheme *runtime.Scheme
+		objects      []client.Object
+		mockPromAPI  *testutils.MockPromAPI
+	)
+
+	BeforeEach(func() {
+		logger.Log = zap.NewNop().Sugar()
+		replayCtx = context.Background()
+
+		replayScheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(replayScheme)).To(Succeed())
+		Expect(llmdVariantAutoscalingV1alpha1.AddToScheme(replayScheme)).To(Succeed())
+
+		objects = []client.Object{
+			testutils.CreateAcceleratorUnitCostConfigMap(DefaultConfigNamespace),
+			testutils.CreateServiceClassConfigMap(DefaultConfigNamespace),
+			testutils.CreateVariantAutoscalingConfigMap(configMapName, DefaultConfigNamespace),
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: utils.Ptr(int32(1)),
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+					Template: v1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+						Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "vllm", Image: "vllm"}}},
+					},
+				},
+				Status: appsv1.DeploymentStatus{Replicas: 1, ReadyReplicas: 1},
+			},
+			&llmdVariantAutoscalingV1alpha1.VariantAutoscaling{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+					Labels:    map[string]string{"inference.optimization/acceleratorName": "A100"},
+				},
+				Spec: llmdVariantAutoscalingV1alpha1.VariantAutoscalingSpec{
+					ModelID: modelID,
+					ModelProfile: llmdVariantAutoscalingV1alpha1.ModelProfile{
+						Accelerators: []llmdVariantAutoscalingV1alpha1.AcceleratorProfile{{
+							Acc:      "A100",
+							AccCount: 1,
+							PerfParms: llmdVariantAutoscalingV1alpha1.PerfParms{
+								DecodeParms:  map[string]string{"alpha": "20.58", "beta": "0.41"},
+								PrefillParms: map[string]string{"gamma": "200", "delta": "0.041"},
+							},
+							MaxBatchSize: 64,
+						}},
+					},
+					SLOClassRef: llmdVariantAutoscalingV1alpha1.ConfigMapKeyRef{Name: "premium", Key: modelID},
+				},
+			},
+		}
+
+		sample := func(value float64) model.Value {
+			return model.Vector{&model.Sample{Value: model.SampleValue(value)}}
+		}
+		mockPromAPI = &testutils.MockPromAPI{
+			QueryResults: map[string]model.Value{
+				testutils.CreateArrivalQuery(modelID, namespace):    sample(8),
+				testutils.CreatePromptToksQuery(modelID, namespace): sample(512),
+				testutils.CreateDecToksQuery(modelID, namespace):    sample(256),
+			},
+			QueryErrors: map[string]error{},
+		}
+	})
+
+	newClient := func() client.Client {
+		return fake.NewClientBuilder().
+			WithScheme(replayScheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&llmdVariantAutoscalingV1alpha1.VariantAutoscaling{}).
+			Build()
+	}
+
+	It("should reproduce the recommendations of recorded cycles", func() {
+		tracePath := filepath.Join(GinkgoT().TempDir(), "trace.jsonl")
+		writer, err := trace.NewWriter(tracePath, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Recording two optimization cycles")
+		k8sClient := newClient()
+		r := NewVariantAutoscalingReconciler(trace.RecordingClient(k8sClient), replayScheme)
+		r.PromAPI = trace.RecordingPromAPI(mockPromAPI)
+		r.Trace = writer
+		recorded := make([][]ReplayedVariant, 0, 2)
+		for range 2 {
+			_, err := r.optimize(replayCtx)
+			Expect(err).NotTo(HaveOccurred())
+			var vaList llmdVariantAutoscalingV1alpha1.VariantAutoscalingList
+			Expect(k8sClient.List(replayCtx, &vaList)).To(Succeed())
+			recorded = append(recorded, replayedVariants(vaList.Items))
+		}
+		Expect(writer.Close()).To(Succeed())
+		Expect(recorded[0]).To(HaveLen(1))
+		Expect(recorded[0][0].Optimized).To(BeTrue())
+		Expect(recorded[0][0].Accelerator).To(Equal("A100"))
+
+		By("Reading the trace")
+		file, err := os.Open(tracePath)
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close() //nolint:errcheck
+		cycles, err := trace.ReadCycles(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cycles).To(HaveLen(2))
+		Expect(cycles[0].Objects).To(HaveLen(len(objects)))
+		Expect(cycles[0].Queries).NotTo(BeEmpty())
+
+		By("Replaying the trace without the cluster and Prometheus")
+		replayed, err := Replay(replayCtx, replayScheme, cycles)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replayed).To(HaveLen(2))
+		for i := range replayed {
+			Expect(replayed[i].Time).To(BeTemporally("==", cycles[i].Time))
+			Expect(replayed[i].Variants).To(Equal(recorded[i]))
+		}
+	})
+
+	It("should report variants without recorded metrics as not optimized", func() {
+		By("Recording a cycle in which Prometheus fails")
+		for _, query := range []string{
+			testutils.CreateArrivalQuery(modelID, namespace),
+			testutils.CreatePromptToksQuery(modelID, namespace),
+			testutils.CreateDecToksQuery(modelID, namespace),
+		} {
+			mockPromAPI.QueryErrors[query] = context.DeadlineExceeded
+		}
+		cycle := trace.NewCycle(metav1.Now().Time, DefaultConfigNamespace)
+		r := NewVariantAutoscalingReconciler(trace.RecordingClient(newClient()), replayScheme)
+		r.PromAPI = trace.RecordingPromAPI(mockPromAPI)
+		_, err := r.optimize(trace.WithCycle(replayCtx, cycle))
+		Expect(err).NotTo(HaveOccurred())
+
+		replayed, err := Replay(replayCtx, replayScheme, []*trace.Cycle{cycle})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replayed).To(HaveLen(1))
+		Expect(replayed[0].Variants).To(HaveLen(1))
+		Expect(replayed[0].Variants[0].Optimized).To(BeFalse())
+	})
+})
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	llmdVariantAutoscalingV1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/constants"
+	interfaces "github.com/llm-d-incubation/workload-variant-autoscaler/internal/interfaces"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/utils"
+)
+
+// resolvedVariant holds the configuration, SLO and target Deployment of a variant
+type resolvedVariant struct {
+	className       string
+	slo             *interfaces.ServiceClassEntry
+	acceleratorName string
+	acceleratorCost float64
+	deploy          appsv1.Deployment
+
+	// accelerator profiles ignored for their invalid performance parameters
+	invalidProfiles []string
+}
+
+// variantIssue describes why a variant cannot be optimized, as reported in its conditions and events
+type variantIssue struct {
+	conditionType   string
+	conditionReason string
+	eventReason     string
+	message         string
+}
+
+// resolveVariant checks the model, SLO, accelerator profiles and cost, and target Deployment of a variant,
+// returning the issue that prevents optimizing it, if any.
+func (r *VariantAutoscalingReconciler) resolveVariant(
+	ctx context.Context,
+	va *llmdVariantAutoscalingV1alpha1.VariantAutoscaling,
+	acceleratorCm map[string]map[string]string,
+	serviceClassCm map[string]string,
+) (*resolvedVariant, *variantIssue) {
+	modelName := va.Spec.ModelID
+	if modelName == "" {
+		logger.Log.Info("variantAutoscaling missing modelName label, skipping optimization - ", "variantAutoscaling-name: ", va.Name)
+		return nil, &variantIssue{
+			conditionType:   llmdVariantAutoscalingV1alpha1.TypeConfigurationValid,
+			conditionReason: llmdVariantAutoscalingV1alpha1.ReasonMissingModelID,
+			eventReason:     constants.EventReasonMissingModelID,
+			message:         "spec.modelID is not set",
+		}
+	}
+
+	entry, className, err := utils.FindModelSLO(serviceClassCm, modelName)
+	if err != nil {
+		logger.Log.Error(err, "failed to locate SLO for model - ", "variantAutoscaling-name: ", va.Name, "modelName: ", modelName)
+		return nil, &variantIssue{
+			conditionType:   llmdVariantAutoscalingV1alpha1.TypeSLOResolved,
+			conditionReason: llmdVariantAutoscalingV1alpha1.ReasonSLONotFound,
+			eventReason:     constants.EventReasonSLONotFound,
+			message:         fmt.Sprintf("No service class found for model %s: %v", modelName, err),
+		}
+	}
+	logger.Log.Debug("Found SLO for model - ", "model: ", modelName, ", class: ", className, ", slo-tpot: ", entry.SLOTPOT, ", slo-ttft: ", entry.SLOTTFT)
+
+	// profiles with invalid performance parameters are ignored, as long as a valid one remains
+	var invalidProfiles []string
+	for i := range va.Spec.ModelProfile.Accelerators {
+		profile := &va.Spec.ModelProfile.Accelerators[i]
+		if _, _, err := utils.ParsePerfParms(profile); err != nil {
+			logger.Log.Error(err, "variantAutoscaling bad model accelerator profile data, ignoring profile - ",
+				"variantAutoscaling-name: ", va.Name, ", accelerator: ", profile.Acc)
+			invalidProfiles = append(invalidProfiles, profile.Acc)
+		}
+	}
+	if len(invalidProfiles) > 0 && len(invalidProfiles) == len(va.Spec.ModelProfile.Accelerators) {
+		logger.Log.Error("variantAutoscaling has no valid model accelerator profile, skipping optimization - ", "variantAutoscaling-name: ", va.Name)
+		return nil, &variantIssue{
+			conditionType:   llmdVariantAutoscalingV1alpha1.TypeConfigurationValid,
+			conditionReason: llmdVariantAutoscalingV1alpha1.ReasonInvalidModelProfile,
+			eventReason:     constants.EventReasonInvalidModelProfile,
+			message: fmt.Sprintf("No valid accelerator profile: %s have invalid performance parameters",
+				strings.Join(invalidProfiles, ", ")),
+		}
+	}
+
+	accName := va.Labels["inference.optimization/acceleratorName"]
+	acceleratorCostVal, ok := acceleratorCm[accName]["cost"]
+	if !ok {
+		logger.Log.Error("variantAutoscaling missing accelerator cost in configMap, skipping optimization - ", "variantAutoscaling-name: ", va.Name)
+		return nil, &variantIssue{
+			conditionType:   llmdVariantAutoscalingV1alpha1.TypeConfigurationValid,
+			conditionReason: llmdVariantAutoscalingV1alpha1.ReasonMissingAcceleratorCost,
+			eventReason:     constants.EventReasonMissingAcceleratorCost,
+			message:         fmt.Sprintf("No cost for accelerator %q in the accelerator ConfigMap", accName),
+		}
+	}
+	acceleratorCost, err := strconv.ParseFloat(acceleratorCostVal, 32)
+	if err != nil {
+		logger.Log.Error("variantAutoscaling unable to parse accelerator cost in configMap, skipping optimization - ", "variantAutoscaling-name: ", va.Name)
+		return nil, &variantIssue{
+			conditionType:   llmdVariantAutoscalingV1alpha1.TypeConfigurationValid,
+			conditionReason: llmdVariantAutoscalingV1alpha1.ReasonMissingAcceleratorCost,
+			eventReason:     constants.EventReasonMissingAcceleratorCost,
+			message:         fmt.Sprintf("Invalid cost %q for accelerator %q in the accelerator ConfigMap", acceleratorCostVal, accName),
+		}
+	}
+
+	resolved := &resolvedVariant{
+		className:       className,
+		slo:             entry,
+		acceleratorName: accName,
+		acceleratorCost: acceleratorCost,
+		invalidProfiles: invalidProfiles,
+	}
+	if err := utils.GetDeploymentWithBackoff(ctx, r.Client, va.Name, va.Namespace, &resolved.deploy); err != nil {
+		logger.Log.Error(err, "failed to get Deployment after retries - ", "variantAutoscaling-name: ", va.Name)
+		return nil, &variantIssue{
+			conditionType:   llmdVariantAutoscalingV1alpha1.TypeTargetResolved,
+			conditionReason: llmdVariantAutoscalingV1alpha1.ReasonDeploymentNotFound,
+			eventReason:     constants.EventReasonDeploymentNotFound,
+			message:         fmt.Sprintf("Unable to get Deployment %s: %v", va.Name, err),
+		}
+	}
+	return resolved, nil
+}
+
+// setConditions sets the ConfigurationValid, SLOResolved and TargetResolved conditions of a resolved variant
+func (rv *resolvedVariant) setConditions(va *llmdVariantAutoscalingV1alpha1.VariantAutoscaling) {
+	message := fmt.Sprintf("Accelerator %s costs %s", rv.acceleratorName,
+		strconv.FormatFloat(rv.acceleratorCost, 'f', -1, 32))
+	if len(rv.invalidProfiles) > 0 {
+		message += "; " + rv.invalidProfilesMessage()
+	}
+	llmdVariantAutoscalingV1alpha1.SetCondition(va,
+		llmdVariantAutoscalingV1alpha1.TypeConfigurationValid,
+		metav1.ConditionTrue,
+		llmdVariantAutoscalingV1alpha1.ReasonConfigurationValid,
+		message)
+	llmdVariantAutoscalingV1alpha1.SetCondition(va,
+		llmdVariantAutoscalingV1alpha1.TypeSLOResolved,
+		metav1.ConditionTrue,
+		llmdVariantAutoscalingV1alpha1.ReasonSLOFound,
+		fmt.Sprintf("Service class %s: SLO TPOT %d ms, SLO TTFT %d ms", rv.className, rv.slo.SLOTPOT, rv.slo.SLOTTFT))
+	llmdVariantAutoscalingV1alpha1.SetCondition(va,
+		llmdVariantAutoscalingV1alpha1.TypeTargetResolved,
+		metav1.ConditionTrue,
+		llmdVariantAutoscalingV1alpha1.ReasonDeploymentFound,
+		fmt.Sprintf("Deployment %s found", rv.deploy.Name))
+}
+
+// invalidProfilesMessage reports the accelerator profiles ignored for their invalid performance parameters
+func (rv *resolvedVariant) invalidProfilesMessage() string {
+	return fmt.Sprintf("ignoring accelerator profiles with invalid performance parameters: %s",
+		strings.Join(rv.invalidProfiles, ", "))
+}
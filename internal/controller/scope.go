@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	llmdVariantAutoscalingV1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+	collector "github.com/llm-d-incubation/workload-variant-autoscaler/internal/collector"
+)
+
+// DefaultConfigNamespace is the namespace of the optimization, accelerator cost and service class ConfigMaps,
+// unless configured otherwise
+const DefaultConfigNamespace = "workload-variant-autoscaler-system"
+
+// Scope restricts the variants an instance of the controller manages, so that several instances can run in the
+// same cluster, e.g., one per tenant, each with its own configuration
+type Scope struct {
+	// namespaces whose variants, Deployments and pods are watched; all namespaces if empty
+	Namespaces []string
+	// selects the variants managed by this instance; all variants if nil
+	VariantSelector labels.Selector
+	// namespace of the configuration ConfigMaps
+	ConfigNamespace string
+}
+
+// ParseNamespaces splits a comma-separated list of namespaces, ignoring blanks and duplicates
+func ParseNamespaces(list string) []string {
+	var namespaces []string
+	seen := map[string]bool{}
+	for _, ns := range strings.Split(list, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces
+}
+
+// CacheOptions returns the manager cache options restricting the watched objects to the scope: variants matching
+// the selector and workloads in the watched namespaces, and only the ConfigMaps of the configuration namespace.
+// Pods are cached with only the fields of their startup latency, as they are the most numerous objects watched.
+func (s Scope) CacheOptions() cache.Options {
+	opts := cache.Options{
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {
+				Namespaces: map[string]cache.Config{s.configNamespace(): {}},
+			},
+			&corev1.Pod{}: {
+				Transform: transformPod,
+			},
+		},
+	}
+	if len(s.Namespaces) > 0 {
+		opts.DefaultNamespaces = make(map[string]cache.Config, len(s.Namespaces))
+		for _, ns := range s.Namespaces {
+			opts.DefaultNamespaces[ns] = cache.Config{}
+		}
+	}
+	if s.VariantSelector != nil && !s.VariantSelector.Empty() {
+		opts.ByObject[&llmdVariantAutoscalingV1alpha1.VariantAutoscaling{}] = cache.ByObject{
+			Label: s.VariantSelector,
+		}
+	}
+	return opts
+}
+
+// transformPod strips the pods added to the cache down to the fields of their startup latency
+func transformPod(obj any) (any, error) {
+	if pod, ok := obj.(*corev1.Pod); ok {
+		return collector.StartupLatencyFields(pod), nil
+	}
+	return obj, nil
+}
+
+// configNamespace returns the namespace of the configuration ConfigMaps, defaulting to DefaultConfigNamespace
+func (s Scope) configNamespace() string {
+	if s.ConfigNamespace == "" {
+		return DefaultConfigNamespace
+	}
+	return s.ConfigNamespace
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	llmdv1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+)
+
+var _ = Describe("Scope", func() {
+	byObject := func(opts cache.Options, obj client.Object) (cache.ByObject, bool) {
+		for o, b := range opts.ByObject {
+			if _, ok := o.(*corev1.ConfigMap); ok {
+				if _, want := obj.(*corev1.ConfigMap); want {
+					return b, true
+				}
+			}
+			if _, ok := o.(*corev1.Pod); ok {
+				if _, want := obj.(*corev1.Pod); want {
+					return b, true
+				}
+			}
+			if _, ok := o.(*llmdv1alpha1.VariantAutoscaling); ok {
+				if _, want := obj.(*llmdv1alpha1.VariantAutoscaling); want {
+					return b, true
+				}
+			}
+		}
+		return cache.ByObject{}, false
+	}
+
+	It("should parse namespace lists", func() {
+		Expect(ParseNamespaces("")).To(BeEmpty())
+		Expect(ParseNamespaces(" a, b,,a ,c")).To(Equal([]string{"a", "b", "c"}))
+	})
+
+	It("should watch everything but the ConfigMaps of other namespaces by default", func() {
+		opts := Scope{}.CacheOptions()
+		Expect(opts.DefaultNamespaces).To(BeEmpty())
+		cm, ok := byObject(opts, &corev1.ConfigMap{})
+		Expect(ok).To(BeTrue())
+		Expect(cm.Namespaces).To(HaveKey(DefaultConfigNamespace))
+		_, ok = byObject(opts, &llmdv1alpha1.VariantAutoscaling{})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should restrict the cache to the watched namespaces, selected variants and configuration namespace", func() {
+		selector, err := labels.Parse("tenant=a")
+		Expect(err).NotTo(HaveOccurred())
+		opts := Scope{Namespaces: []string{"a1", "a2"}, VariantSelector: selector, ConfigNamespace: "tenant-a"}.CacheOptions()
+
+		Expect(opts.DefaultNamespaces).To(HaveLen(2))
+		Expect(opts.DefaultNamespaces).To(HaveKey("a1"))
+		Expect(opts.DefaultNamespaces).To(HaveKey("a2"))
+		cm, _ := byObject(opts, &corev1.ConfigMap{})
+		Expect(cm.Namespaces).To(HaveLen(1))
+		Expect(cm.Namespaces).To(HaveKey("tenant-a"))
+		va, ok := byObject(opts, &llmdv1alpha1.VariantAutoscaling{})
+		Expect(ok).To(BeTrue())
+		Expect(va.Label.Matches(labels.Set{"tenant": "a"})).To(BeTrue())
+		Expect(va.Label.Matches(labels.Set{"tenant": "b"})).To(BeFalse())
+	})
+
+	It("should cache pods without their spec", func() {
+		pod, ok := byObject(Scope{}.CacheOptions(), &corev1.Pod{})
+		Expect(ok).To(BeTrue())
+		Expect(pod.Transform).NotTo(BeNil())
+		transformed, err := pod.Transform(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", ResourceVersion: "7",
+				Labels: map[string]string{"app": "chat"}, Annotations: map[string]string{"note": "x"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "vllm", Image: "vllm"}}},
+			Status: corev1.PodStatus{
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "vllm", Image: "vllm", RestartCount: 1}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transformed).To(Equal(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", ResourceVersion: "7",
+				Labels: map[string]string{"app": "chat"}},
+			Status: corev1.PodStatus{
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "vllm", RestartCount: 1}},
+			},
+		}))
+	})
+})
@@ -20,28 +20,39 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
-	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
-	"sigs.k8s.io/controller-runtime/pkg/predicate"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	llmdVariantAutoscalingV1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
 	actuator "github.com/llm-d-incubation/workload-variant-autoscaler/internal/actuator"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/backlog"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/budget"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/capacity"
 	collector "github.com/llm-d-incubation/workload-variant-autoscaler/internal/collector"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/constants"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/drift"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/forecast"
 	interfaces "github.com/llm-d-incubation/workload-variant-autoscaler/internal/interfaces"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/kvcache"
 	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
 	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/metrics"
 	analyzer "github.com/llm-d-incubation/workload-variant-autoscaler/internal/modelanalyzer"
 	variantAutoscalingOptimizer "github.com/llm-d-incubation/workload-variant-autoscaler/internal/optimizer"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/snapshot"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/stabilizer"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/trace"
 	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/utils"
 	infernoConfig "github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
 	inferno "github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
@@ -61,19 +72,64 @@ type VariantAutoscalingReconciler struct {
 	Scheme *runtime.Scheme
 
 	PromAPI promv1.API
+
+	// Recorder records Kubernetes Events on VariantAutoscaling resources; events are dropped if nil
+	Recorder record.EventRecorder
+
+	// scaling state kept across optimization cycles to account for replica startup latency
+	stabilizer *stabilizer.Stabilizer
+
+	// prediction error state kept across optimization cycles to detect performance model drift
+	driftTracker *drift.Tracker
+
+	// full names of the active variants in the last optimization cycle, to forget the state of deleted ones
+	knownVariants map[string]bool
+
+	// Scope restricts the managed variants and locates the configuration ConfigMaps
+	Scope Scope
+
+	// global optimization of all variants, triggered by reconciles
+	optimizationLoop *optimizationLoop
+
+	// execution of the allocation plans of the direct actuator; plans execute inline if nil
+	planExecutor *planExecutor
+
+	// Snapshots keeps the input and output of the last optimization cycle for debugging; not kept if nil
+	Snapshots *snapshot.Store
+
+	// Trace records the inputs of each optimization cycle for replay; not recorded if nil
+	Trace *trace.Writer
+
+	// clock returns the current time; time.Now if nil
+	clock func() time.Time
+}
+
+// NewVariantAutoscalingReconciler returns a reconciler with the state kept across optimization cycles; the optional
+// fields are set by the caller before SetupWithManager.
+func NewVariantAutoscalingReconciler(c client.Client, scheme *runtime.Scheme) *VariantAutoscalingReconciler {
+	return &VariantAutoscalingReconciler{
+		Client:       c,
+		Scheme:       scheme,
+		stabilizer:   stabilizer.NewStabilizer(),
+		driftTracker: drift.NewTracker(),
+	}
 }
 
 // +kubebuilder:rbac:groups=llmd.ai,resources=variantautoscalings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=llmd.ai,resources=variantautoscalings/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=llmd.ai,resources=variantautoscalings/finalizers,verbs=update
-// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;update;patch
-// +kubebuilder:rbac:groups="",resources=nodes/status,verbs=get;list;update;patch;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update;patch
-// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;update;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;update;list;watch;create
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 const (
-	configMapName      = "workload-variant-autoscaler-variantautoscaling-config"
-	configMapNamespace = "workload-variant-autoscaler-system"
+	configMapName = "workload-variant-autoscaler-variantautoscaling-config"
+
+	acceleratorConfigMapName  = "accelerator-unit-costs"
+	serviceClassConfigMapName = "service-classes-config"
 )
 
 func initMetricsEmitter() {
@@ -83,20 +139,96 @@ func initMetricsEmitter() {
 	logger.Log.Info("Metrics emitter created successfully")
 }
 
+// Reconcile checks the configuration, SLO and target Deployment of a variant, reporting them in its conditions,
+// and triggers the global optimization, which sizes all variants together.
 func (r *VariantAutoscalingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// the variant changed, was created or deleted, or its Deployment changed
+	defer r.triggerOptimization()
 
-	interval, err := r.readOptimizationConfig(ctx)
+	var va llmdVariantAutoscalingV1alpha1.VariantAutoscaling
+	if err := r.Get(ctx, req.NamespacedName, &va); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !va.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	acceleratorCm, err := r.readAcceleratorConfig(ctx, acceleratorConfigMapName, r.Scope.configNamespace())
 	if err != nil {
-		logger.Log.Error(err, "Unable to read optimization config")
+		logger.Log.Error(err, "unable to read accelerator configMap - ", "variantAutoscaling-name: ", va.Name)
+		return ctrl.Result{}, err
+	}
+	serviceClassCm, err := r.readServiceClassConfig(ctx, serviceClassConfigMapName, r.Scope.configNamespace())
+	if err != nil {
+		logger.Log.Error(err, "unable to read serviceclass configMap - ", "variantAutoscaling-name: ", va.Name)
 		return ctrl.Result{}, err
 	}
 
+	resolved, issue := r.resolveVariant(ctx, &va, acceleratorCm, serviceClassCm)
+	if issue != nil {
+		llmdVariantAutoscalingV1alpha1.SetCondition(&va, issue.conditionType, metav1.ConditionFalse, issue.conditionReason, issue.message)
+		r.recordEvent(&va, corev1.EventTypeWarning, issue.eventReason, "Invalid configuration: %s", issue.message)
+	} else {
+		resolved.setConditions(&va)
+		if len(resolved.invalidProfiles) > 0 {
+			r.recordEvent(&va, corev1.EventTypeWarning, constants.EventReasonInvalidModelProfile,
+				"Invalid configuration: %s", resolved.invalidProfilesMessage())
+		}
+	}
+	llmdVariantAutoscalingV1alpha1.SetReadyCondition(&va)
+	if err := utils.UpdateStatusWithBackoff(ctx, r.Client, &va, utils.StandardBackoff, "VariantAutoscaling"); err != nil {
+		logger.Log.Error(err, "failed to update status of variantAutoscaling - ", "variantAutoscaling-name: ", va.Name)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// now returns the current time of the reconciler's clock.
+func (r *VariantAutoscalingReconciler) now() time.Time {
+	if r.clock != nil {
+		return r.clock()
+	}
+	return time.Now()
+}
+
+// triggerOptimization triggers the global optimization, if the loop is running.
+func (r *VariantAutoscalingReconciler) triggerOptimization() {
+	if r.optimizationLoop != nil {
+		r.optimizationLoop.Trigger()
+	}
+}
+
+// optimize runs a global optimization cycle, sizing all variants together, and returns the interval until the
+// next periodic cycle.
+func (r *VariantAutoscalingReconciler) optimize(ctx context.Context) (time.Duration, error) {
+	reconcileStart := time.Now()
+	if r.Trace != nil {
+		cycle := trace.NewCycle(r.now(), r.Scope.configNamespace())
+		ctx = trace.WithCycle(ctx, cycle)
+		defer func() {
+			if err := r.Trace.Write(cycle); err != nil {
+				logger.Log.Error(err, "failed to record optimization cycle")
+			}
+		}()
+	}
+	defer func() {
+		if err := metrics.NewMetricsEmitter().EmitReconcileDuration(ctx, time.Since(reconcileStart)); err != nil {
+			logger.Log.Error(err, "failed to emit reconcile duration metric")
+		}
+	}()
+
+	optimizationConfig, err := r.readOptimizationConfig(ctx)
+	if err != nil {
+		logger.Log.Error(err, "Unable to read optimization config")
+		return 0, err
+	}
+
 	// default requeue duration
 	requeueDuration := 60 * time.Second
 
-	if interval != "" {
+	if interval := optimizationConfig["GLOBAL_OPT_INTERVAL"]; interval != "" {
 		if requeueDuration, err = time.ParseDuration(interval); err != nil {
-			return ctrl.Result{}, err
+			return 0, err
 		}
 	}
 
@@ -105,40 +237,79 @@ func (r *VariantAutoscalingReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	// TODO: decide on whether to keep accelerator properties (device name, cost) in same configMap, provided by administrator
-	acceleratorCm, err := r.readAcceleratorConfig(ctx, "accelerator-unit-costs", configMapNamespace)
+	acceleratorCm, err := r.readAcceleratorConfig(ctx, acceleratorConfigMapName, r.Scope.configNamespace())
 	if err != nil {
 		logger.Log.Error(err, "unable to read accelerator configMap, skipping optimizing")
-		return ctrl.Result{}, err
+		return 0, err
 	}
 
-	serviceClassCm, err := r.readServiceClassConfig(ctx, "service-classes-config", configMapNamespace)
+	serviceClassCm, err := r.readServiceClassConfig(ctx, serviceClassConfigMapName, r.Scope.configNamespace())
 	if err != nil {
 		logger.Log.Error(err, "unable to read serviceclass configMap, skipping optimizing")
-		return ctrl.Result{}, err
+		return 0, err
 	}
 
 	var variantAutoscalingList llmdVariantAutoscalingV1alpha1.VariantAutoscalingList
 	if err := r.List(ctx, &variantAutoscalingList); err != nil {
 		logger.Log.Error(err, "unable to list variantAutoscaling resources")
-		return ctrl.Result{}, err
+		return 0, err
 	}
 
 	activeVAs := filterActiveVariantAutoscalings(variantAutoscalingList.Items)
 
+	// drop the state of variants that were deleted since the last cycle
+	r.forgetDeletedVariants(activeVAs)
+
 	if len(activeVAs) == 0 {
+		r.retainVariantMetrics(nil)
 		logger.Log.Info("No active VariantAutoscalings found, skipping optimization")
-		return ctrl.Result{}, nil
+		return requeueDuration, nil
 	}
 
-	// WVA operates in unlimited mode - no cluster inventory collection needed
 	systemData := utils.CreateSystemData(acceleratorCm, serviceClassCm)
 
+	// allocate the accelerator capacity of the accelerator ConfigMap in a limited solver mode
+	capacityConfig := capacity.ConfigFromData(optimizationConfig)
+	if capacityConfig.Limited() && len(systemData.Spec.Capacity.Count) == 0 {
+		logger.Log.Warn("no accelerator count in the accelerator ConfigMap, using the unlimited solver mode",
+			"key", capacity.KeySolverMode, "value", capacityConfig.Mode)
+		capacityConfig.Mode = capacity.ModeUnlimited
+	}
+	systemData.Spec.Optimizer.Spec = capacityConfig.OptimizerSpec()
+	if capacityConfig.Limited() {
+		accTypes := make(map[string]string, len(systemData.Spec.Accelerators.Spec))
+		for _, acc := range systemData.Spec.Accelerators.Spec {
+			accTypes[acc.Name] = acc.Type
+		}
+		systemData.Spec.Quotas = capacityConfig.QuotaData(accTypes)
+	} else if len(capacityConfig.Quotas) > 0 {
+		logger.Log.Warn("namespace accelerator quotas are ignored in the unlimited solver mode",
+			"key", capacity.KeyNamespaceAcceleratorQuota)
+	}
+
 	updateList, vaMap, allAnalyzerResponses, err := r.prepareVariantAutoscalings(ctx, activeVAs, acceleratorCm, serviceClassCm, systemData)
 	if err != nil {
 		logger.Log.Error(err, "failed to prepare variant autoscalings")
-		return ctrl.Result{}, err
+		return 0, err
 	}
 
+	// check the performance model against the latencies observed for the current allocation and load
+	r.trackModelDrift(ctx, updateList, systemData, drift.ConfigFromData(optimizationConfig))
+
+	// new replicas only serve load once the model is loaded, so size servers for the load expected after the
+	// startup latency (or the forecast lead time, if longer), rather than the instantaneous load
+	stabilizerConfig := stabilizer.ConfigFromData(optimizationConfig)
+	startupLatencies := r.collectStartupLatencies(ctx, updateList, stabilizerConfig)
+	if forecastConfig := forecast.ConfigFromData(optimizationConfig); forecastConfig.Enabled {
+		r.applyArrivalRateForecasts(ctx, updateList, systemData, forecastConfig, startupLatencies)
+	} else {
+		r.applyRampHeadroom(updateList, systemData, startupLatencies, stabilizerConfig)
+	}
+
+	// ceilings on the total cost of the allocations, enforced by the solver
+	budgetConfig := budget.ConfigFromData(optimizationConfig)
+	systemData.Spec.Budget = budgetConfig.BudgetData()
+
 	// analyze
 	system := inferno.NewSystem()
 	optimizerSpec := system.SetFromSpec(&systemData.Spec)
@@ -164,6 +335,10 @@ func (r *VariantAutoscalingReconciler) Reconcile(ctx context.Context, req ctrl.R
 	engine := variantAutoscalingOptimizer.NewVariantAutoscalingsEngine(manager, system)
 
 	optimizedAllocation, err := engine.Optimize(ctx, *updateList, allAnalyzerResponses)
+
+	// keep the input and output of the optimizer, so that the cycle can be reproduced offline
+	r.recordSnapshot(ctx, snapshot.ConfigFromData(optimizationConfig), systemData, system.AllocationSolution(), err)
+
 	if err != nil {
 		logger.Log.Error(err, "unable to perform model optimization, skipping this iteration")
 
@@ -175,6 +350,9 @@ func (r *VariantAutoscalingReconciler) Reconcile(ctx context.Context, req ctrl.R
 				metav1.ConditionFalse,
 				llmdVariantAutoscalingV1alpha1.ReasonOptimizationFailed,
 				fmt.Sprintf("Optimization failed: %v", err))
+			llmdVariantAutoscalingV1alpha1.SetReadyCondition(va)
+			r.recordEvent(va, corev1.EventTypeWarning, constants.EventReasonOptimizationFailed,
+				"Optimization failed, keeping the previous recommendation: %v", err)
 
 			if statusErr := r.Status().Update(ctx, va); statusErr != nil {
 				logger.Log.Error(statusErr, "failed to update status condition after optimization failure",
@@ -182,23 +360,59 @@ func (r *VariantAutoscalingReconciler) Reconcile(ctx context.Context, req ctrl.R
 			}
 		}
 
-		return ctrl.Result{RequeueAfter: requeueDuration}, nil
+		// the optimized variants keep their previous recommendation, the skipped ones have none
+		r.retainVariantMetrics(updateList.Items)
+		return requeueDuration, nil
 	}
 
 	logger.Log.Debug("Optimization completed successfully, emitting optimization metrics")
+	if err := metrics.NewMetricsEmitter().EmitOptimizationMetrics(ctx,
+		time.Duration(optimizer.SolutionTimeMsec())*time.Millisecond, time.Now()); err != nil {
+		logger.Log.Error(err, "failed to emit optimization metrics")
+	}
 	logger.Log.Debug("Optimized allocation map - ", "numKeys: ", len(optimizedAllocation), ", updateList_count: ", len(updateList.Items))
 	for key, value := range optimizedAllocation {
 		logger.Log.Debug("Optimized allocation entry - ", "key: ", key, ", value: ", value)
 	}
 
-	if err := r.applyOptimizedAllocations(ctx, updateList, optimizedAllocation); err != nil {
+	// record the candidates evaluated by the optimizer and the reason for the choice
+	recommendations := engine.Recommendations(*updateList)
+	for i := range updateList.Items {
+		updateList.Items[i].Status.Recommendation = recommendations[updateList.Items[i].Name]
+	}
+
+	// scale up regardless of the arrival rate estimate when an observed backlog cannot be drained within the SLO
+	scalingReasons := r.applyQueueBacklogScaleUps(ctx, updateList, optimizedAllocation, serviceClassCm)
+
+	r.stabilizeScaleDowns(updateList, optimizedAllocation, startupLatencies)
+
+	// keep the adjustments above within the headroom the optimizer left under the cost budget
+	budgetAllocs := r.fitBudget(updateList, system, optimizedAllocation, budgetConfig, scalingReasons)
+
+	// report the variants degraded to fit the cost budget
+	r.reportBudget(ctx, updateList, system, budgetConfig, budgetAllocs, scalingReasons)
+
+	r.emitPredictionMetrics(ctx, updateList, system, optimizedAllocation, serviceClassCm)
+
+	// order the changes to the allocations, releasing accelerators before acquiring them
+	plan := r.createPlan(ctx, updateList, system, optimizedAllocation, !optimizerSpec.Unlimited)
+
+	// stop exporting the metrics of the variants skipped in this cycle, so that the HPA does not act on their
+	// last values
+	r.retainVariantMetrics(recommendedVariants(updateList, optimizedAllocation))
+
+	if err := r.applyOptimizedAllocations(ctx, updateList, optimizedAllocation, scalingReasons); err != nil {
 		// If we fail to apply optimized allocations, we log the error
 		// In next reconcile, the controller will retry.
 		logger.Log.Error(err, "failed to apply optimized allocations")
-		return ctrl.Result{RequeueAfter: requeueDuration}, nil
+		return requeueDuration, nil
 	}
 
-	return ctrl.Result{RequeueAfter: requeueDuration}, nil
+	if actuationConfig := actuator.ConfigFromData(optimizationConfig); actuationConfig.Mode == actuator.ModeDirect {
+		r.submitPlan(ctx, plan, updateList, optimizedAllocation, actuationConfig)
+	}
+
+	return requeueDuration, nil
 }
 
 // filterActiveVariantAutoscalings returns only those VAs not marked for deletion.
@@ -214,6 +428,27 @@ func filterActiveVariantAutoscalings(items []llmdVariantAutoscalingV1alpha1.Vari
 	return active
 }
 
+// publishRecommendations emits the recommendations persisted in the status of the variants, so that a newly
+// elected leader exports them before its first optimization completes.
+func (r *VariantAutoscalingReconciler) publishRecommendations(ctx context.Context) {
+	var variantAutoscalingList llmdVariantAutoscalingV1alpha1.VariantAutoscalingList
+	if err := r.List(ctx, &variantAutoscalingList); err != nil {
+		logger.Log.Error(err, "unable to list variantAutoscaling resources to publish recommendations")
+		return
+	}
+
+	act := actuator.NewActuator(r.Client)
+	for _, va := range filterActiveVariantAutoscalings(variantAutoscalingList.Items) {
+		if va.Status.DesiredOptimizedAlloc.Accelerator == "" {
+			continue
+		}
+		if err := act.EmitMetrics(ctx, &va); err != nil {
+			logger.Log.Error(err, "failed to publish recommendation - ", "variantAutoscaling-name: ", va.Name)
+		}
+	}
+	logger.Log.Info("Published persisted recommendations on election")
+}
+
 // prepareVariantAutoscalings collects and prepares all data for optimization.
 func (r *VariantAutoscalingReconciler) prepareVariantAutoscalings(
 	ctx context.Context,
@@ -226,53 +461,42 @@ func (r *VariantAutoscalingReconciler) prepareVariantAutoscalings(
 	allAnalyzerResponses := make(map[string]*interfaces.ModelAnalyzeResponse)
 	vaMap := make(map[string]*llmdVariantAutoscalingV1alpha1.VariantAutoscaling)
 
-	for _, va := range activeVAs {
-		modelName := va.Spec.ModelID
-		if modelName == "" {
-			logger.Log.Info("variantAutoscaling missing modelName label, skipping optimization - ", "variantAutoscaling-name: ", va.Name)
-			continue
-		}
+	// number of variants skipped by reason, reported with the number of variants processed
+	skipped := make(map[string]int)
+	skipVariant := func(va *llmdVariantAutoscalingV1alpha1.VariantAutoscaling, conditionType, conditionReason, eventReason, message string) {
+		r.skipVariant(ctx, va, conditionType, conditionReason, eventReason, message)
+		skipped[conditionReason]++
+	}
 
-		entry, className, err := utils.FindModelSLO(serviceClassCm, modelName)
-		if err != nil {
-			logger.Log.Error(err, "failed to locate SLO for model - ", "variantAutoscaling-name: ", va.Name, "modelName: ", modelName)
+	for _, va := range activeVAs {
+		resolved, issue := r.resolveVariant(ctx, &va, acceleratorCm, serviceClassCm)
+		if issue != nil {
+			skipVariant(&va, issue.conditionType, issue.conditionReason, issue.eventReason, issue.message)
 			continue
 		}
-		logger.Log.Info("Found SLO for model - ", "model: ", modelName, ", class: ", className, ", slo-tpot: ", entry.SLOTPOT, ", slo-ttft: ", entry.SLOTTFT)
+		modelName, className, deploy := va.Spec.ModelID, resolved.className, resolved.deploy
 
+		// profiles with invalid performance parameters, reported in the ConfigurationValid condition, are left out
 		for _, modelAcceleratorProfile := range va.Spec.ModelProfile.Accelerators {
-			if utils.AddModelAcceleratorProfileToSystemData(systemData, modelName, &modelAcceleratorProfile) != nil {
-				logger.Log.Error("variantAutoscaling bad model accelerator profile data, skipping optimization - ", "variantAutoscaling-name: ", va.Name)
-				continue
+			if err := utils.AddModelAcceleratorProfileToSystemData(systemData, modelName, &modelAcceleratorProfile); err != nil {
+				logger.Log.Debug("Ignoring invalid accelerator profile - ", "variantAutoscaling-name: ", va.Name,
+					", accelerator: ", modelAcceleratorProfile.Acc, ", reason: ", err)
 			}
 		}
 
-		accName := va.Labels["inference.optimization/acceleratorName"]
-		acceleratorCostVal, ok := acceleratorCm[accName]["cost"]
-		if !ok {
-			logger.Log.Error("variantAutoscaling missing accelerator cost in configMap, skipping optimization - ", "variantAutoscaling-name: ", va.Name)
-			continue
-		}
-		acceleratorCostValFloat, err := strconv.ParseFloat(acceleratorCostVal, 32)
-		if err != nil {
-			logger.Log.Error("variantAutoscaling unable to parse accelerator cost in configMap, skipping optimization - ", "variantAutoscaling-name: ", va.Name)
-			continue
-		}
-
-		var deploy appsv1.Deployment
-		err = utils.GetDeploymentWithBackoff(ctx, r.Client, va.Name, va.Namespace, &deploy)
-		if err != nil {
-			logger.Log.Error(err, "failed to get Deployment after retries - ", "variantAutoscaling-name: ", va.Name)
-			continue
-		}
-
 		var updateVA llmdVariantAutoscalingV1alpha1.VariantAutoscaling
-		err = utils.GetVariantAutoscalingWithBackoff(ctx, r.Client, deploy.Name, deploy.Namespace, &updateVA)
+		err := utils.GetVariantAutoscalingWithBackoff(ctx, r.Client, deploy.Name, deploy.Namespace, &updateVA)
 		if err != nil {
 			logger.Log.Error(err, "unable to get variantAutoscaling for deployment - ", "deployment-name: ", deploy.Name, ", namespace: ", deploy.Namespace)
+			skipVariant(&va, llmdVariantAutoscalingV1alpha1.TypeTargetResolved,
+				llmdVariantAutoscalingV1alpha1.ReasonDeploymentNotFound, constants.EventReasonDeploymentNotFound,
+				fmt.Sprintf("Unable to get the variant of Deployment %s: %v", deploy.Name, err))
 			continue
 		}
 
+		// the configuration, SLO and Deployment of the variant have been resolved
+		resolved.setConditions(&updateVA)
+
 		// Set ownerReference early, before metrics validation, to ensure it's always set
 		// This ensures the VA will be garbage collected when the Deployment is deleted
 		if !metav1.IsControlledBy(&updateVA, &deploy) {
@@ -280,6 +504,9 @@ func (r *VariantAutoscalingReconciler) prepareVariantAutoscalings(
 			err := controllerutil.SetControllerReference(&deploy, &updateVA, r.Scheme, controllerutil.WithBlockOwnerDeletion(false))
 			if err != nil {
 				logger.Log.Error(err, "failed to set ownerReference - ", "variantAutoscaling-name: ", updateVA.Name)
+				skipVariant(&updateVA, llmdVariantAutoscalingV1alpha1.TypeTargetResolved,
+					llmdVariantAutoscalingV1alpha1.ReasonOwnerReferenceFailed, constants.EventReasonOwnerReferenceFailed,
+					fmt.Sprintf("Unable to set Deployment %s as owner: %v", deploy.Name, err))
 				continue
 			}
 
@@ -287,6 +514,9 @@ func (r *VariantAutoscalingReconciler) prepareVariantAutoscalings(
 			patch := client.MergeFrom(original)
 			if err := r.Patch(ctx, &updateVA, patch); err != nil {
 				logger.Log.Error(err, "failed to patch ownerReference - ", "variantAutoscaling-name: ", updateVA.Name)
+				skipVariant(&updateVA, llmdVariantAutoscalingV1alpha1.TypeTargetResolved,
+					llmdVariantAutoscalingV1alpha1.ReasonOwnerReferenceFailed, constants.EventReasonOwnerReferenceFailed,
+					fmt.Sprintf("Unable to set Deployment %s as owner: %v", deploy.Name, err))
 				continue
 			}
 			logger.Log.Info("Set ownerReference on VariantAutoscaling - ", "variantAutoscaling-name: ", updateVA.Name, ", owner: ", deploy.Name)
@@ -303,42 +533,908 @@ func (r *VariantAutoscalingReconciler) prepareVariantAutoscalings(
 				metricsValidation.Reason,
 				metricsValidation.Message)
 		} else {
-			// Metrics unavailable - just log and skip (don't update status yet to avoid CRD validation errors)
-			// Conditions will be set properly once metrics become available or after first successful collection
+			// Metrics unavailable - skip, reporting why in the status, which can be written before any
+			// allocation has been collected
 			logger.Log.Warnw("Metrics unavailable, skipping optimization for variant",
 				"variant", updateVA.Name,
 				"namespace", updateVA.Namespace,
 				"model", modelName,
 				"reason", metricsValidation.Reason,
 				"troubleshooting", metricsValidation.Message)
+			skipVariant(&updateVA, llmdVariantAutoscalingV1alpha1.TypeMetricsAvailable,
+				metricsValidation.Reason, constants.EventReasonMetricsUnavailable, metricsValidation.Message)
 			continue
 		}
 
-		currentAllocation, err := collector.AddMetricsToOptStatus(ctx, &updateVA, deploy, acceleratorCostValFloat, r.PromAPI)
+		currentAllocation, err := collector.AddMetricsToOptStatus(ctx, &updateVA, deploy, resolved.acceleratorCost, r.PromAPI)
 		if err != nil {
 			logger.Log.Error(err, "unable to fetch metrics, skipping this variantAutoscaling loop")
-			// Don't update status here - will be updated in next reconcile when metrics are available
+			skipVariant(&updateVA, llmdVariantAutoscalingV1alpha1.TypeMetricsAvailable,
+				llmdVariantAutoscalingV1alpha1.ReasonPrometheusError, constants.EventReasonMetricsUnavailable,
+				fmt.Sprintf("Unable to collect metrics: %v", err))
 			continue
 		}
 		updateVA.Status.CurrentAlloc = currentAllocation
 
 		if err := utils.AddServerInfoToSystemData(systemData, &updateVA, className); err != nil {
 			logger.Log.Info("variantAutoscaling bad deployment server data, skipping optimization - ", "variantAutoscaling-name: ", updateVA.Name)
+			skipVariant(&updateVA, llmdVariantAutoscalingV1alpha1.TypeConfigurationValid,
+				llmdVariantAutoscalingV1alpha1.ReasonInvalidServerData, constants.EventReasonInvalidServerData,
+				fmt.Sprintf("Invalid server data: %v", err))
 			continue
 		}
 
+		// size for the batch the KV cache can hold, if it rather than the max number of sequences is the limit
+		r.applyKVCacheLimit(ctx, &updateVA, systemData)
+
 		vaFullName := utils.FullName(va.Name, va.Namespace)
 		updateList.Items = append(updateList.Items, updateVA)
 		vaMap[vaFullName] = &va
 	}
+	if err := metrics.NewMetricsEmitter().EmitVariantCounts(ctx, len(updateList.Items), skipped); err != nil {
+		logger.Log.Error(err, "failed to emit variant count metrics")
+	}
 	return &updateList, vaMap, allAnalyzerResponses, nil
 }
 
+// applyKVCacheLimit sets the KVCacheSaturated condition of a variant from its KV cache usage and preemptions, and
+// lowers the max batch size of its server on the current accelerator to the number of requests the KV cache can hold
+// when that is the limit. On the other accelerators of the variant, the limit is scaled by the accelerator memory of a
+// replica, when the memory sizes are configured.
+func (r *VariantAutoscalingReconciler) applyKVCacheLimit(
+	ctx context.Context,
+	va *llmdVariantAutoscalingV1alpha1.VariantAutoscaling,
+	systemData *infernoConfig.SystemData,
+) {
+	kvState, err := collector.CollectKVCacheState(ctx, r.PromAPI, va.Spec.ModelID, va.Namespace)
+	if err != nil {
+		logger.Log.Error(err, "unable to collect KV cache state - ", "variantAutoscaling-name: ", va.Name)
+		return
+	}
+	queueState, err := collector.CollectQueueState(ctx, r.PromAPI, va.Spec.ModelID, va.Namespace)
+	if err != nil {
+		logger.Log.Error(err, "unable to collect queue state - ", "variantAutoscaling-name: ", va.Name)
+		return
+	}
+
+	currentAcc := va.Status.CurrentAlloc.Accelerator
+	currentProfile := findAcceleratorProfile(va, currentAcc)
+	configured := 0
+	if currentProfile != nil {
+		configured = currentProfile.MaxBatchSize
+	}
+	runningPerReplica := 0.0
+	if va.Status.CurrentAlloc.NumReplicas > 0 {
+		runningPerReplica = queueState.Running / float64(va.Status.CurrentAlloc.NumReplicas)
+	}
+	// average running requests per replica over average usage per replica
+	maxBatch, kvBound := kvcache.EffectiveMaxBatch(kvState.AvgUsage, kvState.PreemptionRate, runningPerReplica, configured)
+
+	if kvcache.Saturated(kvState.Usage, kvState.PreemptionRate) {
+		reason := llmdVariantAutoscalingV1alpha1.ReasonKVCacheFull
+		if kvState.Usage < kvcache.SaturationThreshold {
+			reason = llmdVariantAutoscalingV1alpha1.ReasonRequestsPreempted
+		}
+		llmdVariantAutoscalingV1alpha1.SetCondition(va,
+			llmdVariantAutoscalingV1alpha1.TypeKVCacheSaturated,
+			metav1.ConditionTrue,
+			reason,
+			fmt.Sprintf("KV cache usage %.0f%%, %.1f preemptions/min, effective max batch size %d",
+				kvState.Usage*100, kvState.PreemptionRate, maxBatch))
+	} else {
+		llmdVariantAutoscalingV1alpha1.SetCondition(va,
+			llmdVariantAutoscalingV1alpha1.TypeKVCacheSaturated,
+			metav1.ConditionFalse,
+			llmdVariantAutoscalingV1alpha1.ReasonKVCacheAvailable,
+			fmt.Sprintf("KV cache usage %.0f%%", kvState.Usage*100))
+	}
+
+	if !kvBound || currentProfile == nil {
+		return
+	}
+	vaFullName := utils.FullName(va.Name, va.Namespace)
+	limits := map[string]int{currentAcc: maxBatch}
+	currentMemory := replicaMemory(systemData, currentProfile)
+	for i := range va.Spec.ModelProfile.Accelerators {
+		profile := &va.Spec.ModelProfile.Accelerators[i]
+		if profile.Acc == currentAcc {
+			continue
+		}
+		scaled, ok := kvcache.ScaleMaxBatch(maxBatch, currentMemory, replicaMemory(systemData, profile))
+		if !ok || (profile.MaxBatchSize > 0 && scaled >= profile.MaxBatchSize) {
+			continue
+		}
+		limits[profile.Acc] = scaled
+	}
+	for acc, limit := range limits {
+		if err := utils.SetServerAcceleratorMaxBatchSize(systemData, vaFullName, acc, limit); err != nil {
+			logger.Log.Error(err, "unable to set KV cache bound max batch size - ", "variantAutoscaling-name: ", va.Name)
+			return
+		}
+	}
+	logger.Log.Info("KV cache limits batch size - ", "variantAutoscaling-name: ", va.Name,
+		", configured: ", configured, ", effective: ", limits, ", kvCacheUsage: ", kvState.AvgUsage)
+}
+
+// replicaMemory returns the accelerator memory (GB) of a replica of a variant with an accelerator profile; zero if
+// the memory size of the accelerator is not configured.
+func replicaMemory(systemData *infernoConfig.SystemData, profile *llmdVariantAutoscalingV1alpha1.AcceleratorProfile) int {
+	for _, acc := range systemData.Spec.Accelerators.Spec {
+		if acc.Name == profile.Acc {
+			return acc.MemSize * max(acc.Multiplicity, 1) * max(profile.AccCount, 1)
+		}
+	}
+	return 0
+}
+
+// applyArrivalRateForecasts replaces the observed arrival rate of each prepared server with the rate forecast
+// at the configured lead time. Servers without enough history keep their observed arrival rate.
+func (r *VariantAutoscalingReconciler) applyArrivalRateForecasts(
+	ctx context.Context,
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	systemData *infernoConfig.SystemData,
+	forecastConfig forecast.Config,
+	startupLatencies map[string]time.Duration,
+) {
+	metricsEmitter := metrics.NewMetricsEmitter()
+	model := forecast.NewHoltWinters(forecastConfig.SeasonLength)
+
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		observed, err := strconv.ParseFloat(va.Status.CurrentAlloc.Load.ArrivalRate, 32)
+		if err != nil || !utils.CheckValue(observed) {
+			observed = 0
+		}
+
+		history, err := collector.CollectArrivalRateHistory(ctx, r.PromAPI, va.Spec.ModelID, va.Namespace,
+			forecastConfig.Lookback, forecastConfig.Step)
+		if err != nil {
+			logger.Log.Error(err, "unable to collect arrival rate history, using observed arrival rate - ", "variantAutoscaling-name: ", va.Name)
+			continue
+		}
+
+		vaFullName := utils.FullName(va.Name, va.Namespace)
+		leadTime := max(forecastConfig.LeadTime, startupLatencies[vaFullName])
+		predicted, err := model.Forecast(history, forecast.HorizonSteps(leadTime, forecastConfig.Step))
+		if err != nil {
+			logger.Log.Debug("Unable to forecast arrival rate, using observed arrival rate - ", "variantAutoscaling-name: ", va.Name, ", reason: ", err)
+			continue
+		}
+		predicted = max(predicted, 0)
+
+		if err := utils.SetServerArrivalRate(systemData, vaFullName, float32(predicted)); err != nil {
+			logger.Log.Error(err, "unable to set forecast arrival rate - ", "variantAutoscaling-name: ", va.Name)
+			continue
+		}
+		logger.Log.Debug("Using forecast arrival rate - ", "variantAutoscaling-name: ", va.Name,
+			", observed: ", observed, ", forecast: ", predicted, ", leadTime: ", leadTime)
+
+		if err := metricsEmitter.EmitForecastMetrics(ctx, va, observed, predicted); err != nil {
+			logger.Log.Error(err, "failed to emit forecast metrics - ", "variantAutoscaling-name: ", va.Name)
+		}
+	}
+}
+
+// collectStartupLatencies returns the startup latency of each prepared variant, keyed by full name.
+// The latency configured in the spec takes precedence over the one learned from pod readiness, which is learned
+// from the pods created within the configured window and capped at the configured maximum.
+func (r *VariantAutoscalingReconciler) collectStartupLatencies(
+	ctx context.Context,
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	cfg stabilizer.Config,
+) map[string]time.Duration {
+	metricsEmitter := metrics.NewMetricsEmitter()
+	startupLatencies := make(map[string]time.Duration, len(updateList.Items))
+	since := r.now().Add(-cfg.StartupLatencyWindow)
+
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		var latency time.Duration
+		if va.Spec.StartupLatency != nil {
+			latency = va.Spec.StartupLatency.Duration
+		} else {
+			var deploy appsv1.Deployment
+			if err := utils.GetDeploymentWithBackoff(ctx, r.Client, va.Name, va.Namespace, &deploy); err != nil {
+				logger.Log.Error(err, "failed to get Deployment for startup latency - ", "variantAutoscaling-name: ", va.Name)
+				continue
+			}
+			learned, err := collector.EstimateStartupLatency(ctx, r.Client, deploy, since)
+			if err != nil {
+				logger.Log.Error(err, "unable to estimate startup latency - ", "variantAutoscaling-name: ", va.Name)
+				continue
+			}
+			if learned > cfg.MaxStartupLatency {
+				logger.Log.Debug("Capping learned startup latency - ", "variantAutoscaling-name: ", va.Name,
+					", learned: ", learned, ", max: ", cfg.MaxStartupLatency)
+				learned = cfg.MaxStartupLatency
+			}
+			latency = learned
+		}
+		if latency <= 0 {
+			continue
+		}
+		startupLatencies[utils.FullName(va.Name, va.Namespace)] = latency
+		logger.Log.Debug("Using startup latency - ", "variantAutoscaling-name: ", va.Name, ", startupLatency: ", latency)
+
+		if err := metricsEmitter.EmitStartupLatencyMetric(ctx, va, latency); err != nil {
+			logger.Log.Error(err, "failed to emit startup latency metric - ", "variantAutoscaling-name: ", va.Name)
+		}
+	}
+	return startupLatencies
+}
+
+// applyRampHeadroom raises the arrival rate of each prepared server that is ramping up to the rate expected
+// once a replica started now would be ready.
+func (r *VariantAutoscalingReconciler) applyRampHeadroom(
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	systemData *infernoConfig.SystemData,
+	startupLatencies map[string]time.Duration,
+	cfg stabilizer.Config,
+) {
+	now := r.now()
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		observed, err := strconv.ParseFloat(va.Status.CurrentAlloc.Load.ArrivalRate, 32)
+		if err != nil || !utils.CheckValue(observed) {
+			continue
+		}
+		vaFullName := utils.FullName(va.Name, va.Namespace)
+		rate := r.stabilizer.RampHeadroom(vaFullName, observed, startupLatencies[vaFullName], now, cfg)
+		if rate <= observed {
+			continue
+		}
+		if err := utils.SetServerArrivalRate(systemData, vaFullName, float32(rate)); err != nil {
+			logger.Log.Error(err, "unable to set arrival rate with ramp headroom - ", "variantAutoscaling-name: ", va.Name)
+			continue
+		}
+		logger.Log.Debug("Adding ramp headroom to arrival rate - ", "variantAutoscaling-name: ", va.Name,
+			", observed: ", observed, ", sized for: ", rate)
+	}
+}
+
+// emitPredictionMetrics emits, for each variant, the performance predicted for its applied allocation and the
+// SLO targets of its service class, so that predictions can be compared with the observed performance. When the
+// number of replicas was adjusted after the optimization, the performance is predicted for the adjusted number.
+func (r *VariantAutoscalingReconciler) emitPredictionMetrics(
+	ctx context.Context,
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	system *inferno.System,
+	optimizedAllocation map[string]llmdVariantAutoscalingV1alpha1.OptimizedAlloc,
+	serviceClassCm map[string]string,
+) {
+	metricsEmitter := metrics.NewMetricsEmitter()
+
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		if entry, _, err := utils.FindModelSLO(serviceClassCm, va.Spec.ModelID); err == nil {
+			if err := metricsEmitter.EmitSLOMetrics(ctx, va, float64(entry.SLOTPOT), float64(entry.SLOTTFT)); err != nil {
+				logger.Log.Error(err, "failed to emit SLO metrics - ", "variantAutoscaling-name: ", va.Name)
+			}
+		}
+
+		vaFullName := utils.FullName(va.Name, va.Namespace)
+		applied, ok := optimizedAllocation[va.Name]
+		server := system.Server(vaFullName)
+		if !ok || server == nil || server.Allocation() == nil || server.Allocation().Accelerator() != applied.Accelerator {
+			continue
+		}
+		alloc := server.Allocation()
+		itl, ttft, rho := alloc.ITL(), alloc.TTFT(), alloc.Rho()
+		if applied.NumReplicas != alloc.NumReplicas() {
+			predicted := inferno.PredictPerformance(vaFullName, applied.Accelerator, applied.NumReplicas)
+			if predicted == nil {
+				continue
+			}
+			itl, ttft, rho = predicted.ITL, predicted.TTFT, predicted.Rho
+		}
+		if err := metricsEmitter.EmitPredictionMetrics(ctx, va, applied.Accelerator,
+			float64(itl), float64(ttft), float64(rho), float64(alloc.MaxRPM())); err != nil {
+			logger.Log.Error(err, "failed to emit prediction metrics - ", "variantAutoscaling-name: ", va.Name)
+		}
+	}
+}
+
+// forgetDeletedVariants forgets the scaling and prediction error state of the variants that were active in the
+// last cycle but no longer are, so that a variant later created with the same name starts afresh.
+func (r *VariantAutoscalingReconciler) forgetDeletedVariants(activeVAs []llmdVariantAutoscalingV1alpha1.VariantAutoscaling) {
+	active := make(map[string]bool, len(activeVAs))
+	for _, va := range activeVAs {
+		active[utils.FullName(va.Name, va.Namespace)] = true
+	}
+	for vaFullName := range r.knownVariants {
+		if active[vaFullName] {
+			continue
+		}
+		logger.Log.Info("Forgetting state of variant that no longer exists - ", "variantAutoscaling: ", vaFullName)
+		r.stabilizer.Forget(vaFullName)
+		r.driftTracker.Forget(vaFullName)
+	}
+	r.knownVariants = active
+}
+
+// retainVariantMetrics deletes the metric series of all variants but the given ones, i.e., of the variants that
+// were deleted or are skipped from optimization, as they have no current recommendation.
+func (r *VariantAutoscalingReconciler) retainVariantMetrics(vas []llmdVariantAutoscalingV1alpha1.VariantAutoscaling) {
+	for _, vaFullName := range metrics.NewMetricsEmitter().DeleteStaleVariantMetrics(vas) {
+		logger.Log.Info("Deleted metrics of variant without a recommendation - ", "variantAutoscaling: ", vaFullName)
+	}
+}
+
+// recommendedVariants returns the variants of the update list that have an optimized allocation
+func recommendedVariants(updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	optimizedAllocation map[string]llmdVariantAutoscalingV1alpha1.OptimizedAlloc) []llmdVariantAutoscalingV1alpha1.VariantAutoscaling {
+	var recommended []llmdVariantAutoscalingV1alpha1.VariantAutoscaling
+	for _, va := range updateList.Items {
+		if _, ok := optimizedAllocation[va.Name]; ok {
+			recommended = append(recommended, va)
+		}
+	}
+	return recommended
+}
+
+// findAcceleratorProfile returns the profile of a variant on an accelerator, or nil if it has none.
+func findAcceleratorProfile(va *llmdVariantAutoscalingV1alpha1.VariantAutoscaling, acc string) *llmdVariantAutoscalingV1alpha1.AcceleratorProfile {
+	for j := range va.Spec.ModelProfile.Accelerators {
+		if va.Spec.ModelProfile.Accelerators[j].Acc == acc {
+			return &va.Spec.ModelProfile.Accelerators[j]
+		}
+	}
+	return nil
+}
+
+// trackModelDrift evaluates the queueing model of each variant at its current number of replicas and observed load,
+// and compares the predicted ITL and TTFT with the observed ones. The max batch size is the one the optimizer sizes the
+// variant with, including the limit of the KV cache on its accelerator. The PerformanceModelDrift condition is set True
+// once the larger relative error stays above the threshold for the configured number of consecutive cycles.
+// Variants without replicas, load or latency observations, or whose replicas are predicted to be saturated,
+// are not evaluated and keep their condition, but their count of consecutive cycles restarts.
+func (r *VariantAutoscalingReconciler) trackModelDrift(
+	ctx context.Context,
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	systemData *infernoConfig.SystemData,
+	cfg drift.Config,
+) {
+	metricsEmitter := metrics.NewMetricsEmitter()
+	tracker := r.driftTracker
+
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		vaFullName := utils.FullName(va.Name, va.Namespace)
+		current := va.Status.CurrentAlloc
+
+		arrivalRate, _ := strconv.ParseFloat(current.Load.ArrivalRate, 64)
+		observedITL, _ := strconv.ParseFloat(current.ITLAverage, 64)
+		observedTTFT, _ := strconv.ParseFloat(current.TTFTAverage, 64)
+		if current.NumReplicas <= 0 || arrivalRate <= 0 || observedITL <= 0 || observedTTFT <= 0 {
+			tracker.Forget(vaFullName)
+			continue
+		}
+
+		profile := findAcceleratorProfile(va, current.Accelerator)
+		if profile == nil {
+			tracker.Forget(vaFullName)
+			continue
+		}
+		avgInTokens, _ := strconv.ParseFloat(current.Load.AvgInputTokens, 64)
+		avgOutTokens, _ := strconv.ParseFloat(current.Load.AvgOutputTokens, 64)
+		maxBatchSize := utils.ServerMaxBatchSize(systemData, vaFullName, current.Accelerator)
+		evaluator, err := backlog.NewEvaluatorWithBatchSize(profile, maxBatchSize,
+			int(math.Round(avgInTokens)), int(math.Round(avgOutTokens)))
+		if err != nil {
+			logger.Log.Debug("Unable to build queue model, skipping drift detection - ", "variantAutoscaling-name: ", va.Name, ", reason: ", err)
+			tracker.Forget(vaFullName)
+			continue
+		}
+		predicted, err := evaluator.Predict(current.NumReplicas, arrivalRate)
+		if err != nil || predicted.Saturated {
+			tracker.Forget(vaFullName)
+			continue
+		}
+
+		itlError := drift.RelativeError(predicted.ITL, observedITL)
+		ttftError := drift.RelativeError(predicted.TTFT, observedTTFT)
+		cycles, drifting := tracker.Observe(vaFullName, math.Max(itlError, ttftError), cfg)
+		logger.Log.Debug("Prediction error - ", "variantAutoscaling-name: ", va.Name,
+			", itlError: ", itlError, ", ttftError: ", ttftError, ", cycles: ", cycles)
+		if err := metricsEmitter.EmitDriftMetrics(ctx, va, itlError, ttftError, drifting); err != nil {
+			logger.Log.Error(err, "failed to emit drift metrics - ", "variantAutoscaling-name: ", va.Name)
+		}
+
+		message := fmt.Sprintf("Predicted ITL %.2f ms vs observed %.2f ms, predicted TTFT %.2f ms vs observed %.2f ms",
+			predicted.ITL, observedITL, predicted.TTFT, observedTTFT)
+		if !drifting {
+			llmdVariantAutoscalingV1alpha1.SetCondition(va,
+				llmdVariantAutoscalingV1alpha1.TypePerformanceModelDrift,
+				metav1.ConditionFalse,
+				llmdVariantAutoscalingV1alpha1.ReasonPredictionAccurate,
+				message)
+			continue
+		}
+		if !llmdVariantAutoscalingV1alpha1.IsConditionTrue(va, llmdVariantAutoscalingV1alpha1.TypePerformanceModelDrift) {
+			r.recordEvent(va, corev1.EventTypeWarning, constants.EventReasonPerformanceModelDrift,
+				"Prediction error above %.2f for %d cycles: %s", cfg.Threshold, cycles, message)
+		}
+		llmdVariantAutoscalingV1alpha1.SetCondition(va,
+			llmdVariantAutoscalingV1alpha1.TypePerformanceModelDrift,
+			metav1.ConditionTrue,
+			llmdVariantAutoscalingV1alpha1.ReasonPredictionErrorHigh,
+			fmt.Sprintf("Prediction error above %.2f for %d cycles: %s", cfg.Threshold, cycles, message))
+	}
+}
+
+// fitBudget removes the replicas added by the queue backlog scale-ups and the scale-downs held within the startup
+// latency, beyond the optimized allocations, where they would exceed the cost budget. It returns the optimized and
+// applied allocations of the variants, by full name.
+func (r *VariantAutoscalingReconciler) fitBudget(
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	system *inferno.System,
+	optimizedAllocation map[string]llmdVariantAutoscalingV1alpha1.OptimizedAlloc,
+	cfg budget.Config,
+	scalingReasons map[string]string,
+) map[string]*budget.Allocation {
+	allocs := make(map[string]*budget.Allocation)
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		vaFullName := utils.FullName(va.Name, va.Namespace)
+		alloc, ok := optimizedAllocation[va.Name]
+		server := system.Server(vaFullName)
+		if !ok || server == nil || server.Allocation() == nil {
+			continue
+		}
+		acc, model := system.Accelerator(alloc.Accelerator), system.Model(server.ModelName())
+		if acc == nil || model == nil {
+			continue
+		}
+		allocs[vaFullName] = &budget.Allocation{
+			Namespace:      va.Namespace,
+			Priority:       server.Priority(),
+			CostPerReplica: float64(acc.Cost()) * float64(model.NumInstances(alloc.Accelerator)),
+			Optimized:      server.Allocation().NumReplicas(),
+			Applied:        alloc.NumReplicas,
+		}
+	}
+	if !cfg.Limited() {
+		return allocs
+	}
+
+	removed := cfg.Fit(allocs)
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		vaFullName := utils.FullName(va.Name, va.Namespace)
+		if removed[vaFullName] == 0 {
+			continue
+		}
+		alloc := optimizedAllocation[va.Name]
+		applied := allocs[vaFullName].Applied
+		logger.Log.Info("Replicas added after optimization exceed the cost budget, removing them - ",
+			"variantAutoscaling-name: ", va.Name, ", adjusted: ", alloc.NumReplicas, ", applied: ", applied)
+		addRecommendationNote(va, fmt.Sprintf("reduced from %d to %d replicas to fit the cost budget", alloc.NumReplicas, applied))
+		alloc.NumReplicas = applied
+		optimizedAllocation[va.Name] = alloc
+		if applied <= allocs[vaFullName].Optimized {
+			delete(scalingReasons, va.Name)
+		}
+	}
+	return allocs
+}
+
+// reportBudget emits the cost ceilings and the headroom left by the applied allocations, and sets the
+// BudgetConstrained condition of each variant according to whether the optimizer allocated it fewer replicas than
+// needed to meet its SLO to fit the budget. Degraded variants not scaled up for a queue backlog are given the cost
+// budget scaling reason.
+func (r *VariantAutoscalingReconciler) reportBudget(
+	ctx context.Context,
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	system *inferno.System,
+	cfg budget.Config,
+	allocs map[string]*budget.Allocation,
+	scalingReasons map[string]string,
+) {
+	metricsEmitter := metrics.NewMetricsEmitter()
+
+	limits := make(map[string]float64)
+	headrooms := make(map[string]float64)
+	for _, usage := range system.BudgetUsage() {
+		limits[usage.Namespace] = float64(usage.Limit)
+		headrooms[usage.Namespace] = float64(usage.Limit) - budget.Cost(allocs, usage.Namespace)
+		logger.Log.Debug("Cost budget usage - ", usage)
+	}
+	if err := metricsEmitter.EmitBudgetMetrics(ctx, limits, headrooms); err != nil {
+		logger.Log.Error(err, "failed to emit budget metrics")
+	}
+
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		server := system.Server(utils.FullName(va.Name, va.Namespace))
+		if server == nil {
+			continue
+		}
+		required := server.RequiredReplicas()
+		if required <= 0 || server.Allocation() == nil {
+			// also clear the condition of variants degraded before the budget was removed
+			if cfg.Limited() || llmdVariantAutoscalingV1alpha1.IsConditionTrue(va, llmdVariantAutoscalingV1alpha1.TypeBudgetConstrained) {
+				llmdVariantAutoscalingV1alpha1.SetCondition(va,
+					llmdVariantAutoscalingV1alpha1.TypeBudgetConstrained,
+					metav1.ConditionFalse,
+					llmdVariantAutoscalingV1alpha1.ReasonWithinBudget,
+					"Allocation needed to meet the SLO fits the cost budget")
+			}
+			if err := metricsEmitter.EmitBudgetReductionMetric(ctx, va, 0); err != nil {
+				logger.Log.Error(err, "failed to emit budget reduction metric - ", "variantAutoscaling-name: ", va.Name)
+			}
+			continue
+		}
+
+		allocated := server.Allocation().NumReplicas()
+		message := fmt.Sprintf("Allocated %d of %d replicas needed to meet the SLO to fit the cost budget", allocated, required)
+		logger.Log.Info("Replicas reduced to fit the cost budget - ", "variantAutoscaling-name: ", va.Name,
+			", allocated: ", allocated, ", required: ", required)
+		if !llmdVariantAutoscalingV1alpha1.IsConditionTrue(va, llmdVariantAutoscalingV1alpha1.TypeBudgetConstrained) {
+			r.recordEvent(va, corev1.EventTypeWarning, constants.EventReasonBudgetConstrained, "%s", message)
+		}
+		llmdVariantAutoscalingV1alpha1.SetCondition(va,
+			llmdVariantAutoscalingV1alpha1.TypeBudgetConstrained,
+			metav1.ConditionTrue,
+			llmdVariantAutoscalingV1alpha1.ReasonReplicasReducedByBudget,
+			message)
+		if err := metricsEmitter.EmitBudgetReductionMetric(ctx, va, required-allocated); err != nil {
+			logger.Log.Error(err, "failed to emit budget reduction metric - ", "variantAutoscaling-name: ", va.Name)
+		}
+		if _, ok := scalingReasons[va.Name]; !ok {
+			scalingReasons[va.Name] = constants.ScalingReasonCostBudget
+		}
+	}
+}
+
+// createPlan orders the changes from the current to the optimized allocations of the variants into a plan, scale-downs
+// and accelerator releases first, then scale-ups depending on the releases they need if capacity is limited, and
+// exports the plan as metrics.
+func (r *VariantAutoscalingReconciler) createPlan(
+	ctx context.Context,
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	system *inferno.System,
+	optimizedAllocation map[string]llmdVariantAutoscalingV1alpha1.OptimizedAlloc,
+	limited bool,
+) *infernoSolver.Plan {
+	diffs := make(map[string]*inferno.AllocationDiff)
+	vaByServer := make(map[string]*llmdVariantAutoscalingV1alpha1.VariantAutoscaling)
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		serverName := utils.FullName(va.Name, va.Namespace)
+		server := system.Server(serverName)
+		alloc, ok := optimizedAllocation[va.Name]
+		if server == nil || !ok {
+			continue
+		}
+		desired := inferno.AllocationFromData(&infernoConfig.AllocationData{
+			Accelerator: alloc.Accelerator,
+			NumReplicas: alloc.NumReplicas,
+		})
+		current := server.CurAllocation()
+		if current != nil && current.Accelerator() == "" {
+			current = nil
+		}
+		if diff := inferno.CreateAllocationDiff(current, desired); diff != nil {
+			diffs[serverName] = diff
+			vaByServer[serverName] = va
+		}
+	}
+	plan := infernoSolver.CreatePlan(diffs, limited)
+
+	steps := make([]metrics.PlanStepLabels, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		va := vaByServer[step.ServerName]
+		steps = append(steps, metrics.PlanStepLabels{
+			VariantName: va.Name,
+			Namespace:   va.Namespace,
+			Action:      string(step.Action),
+		})
+	}
+	if err := metrics.NewMetricsEmitter().EmitPlanMetrics(ctx, steps); err != nil {
+		logger.Log.Error(err, "failed to emit plan metrics")
+	}
+	if len(plan.Steps) > 0 {
+		logger.Log.Info("Allocation plan - ", plan)
+	}
+	return plan
+}
+
+// submitPlan reports the accelerator changes of the plan, which the direct actuator does not apply, when newly
+// recommended, and submits the plan for execution, superseding the plan of the last optimization if still executing.
+func (r *VariantAutoscalingReconciler) submitPlan(
+	ctx context.Context,
+	plan *infernoSolver.Plan,
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	optimizedAllocation map[string]llmdVariantAutoscalingV1alpha1.OptimizedAlloc,
+	cfg actuator.Config,
+) {
+	if plan == nil || len(plan.Steps) == 0 {
+		return
+	}
+	changes := actuator.AcceleratorChanges(plan)
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		// report the change once, when the recommended accelerator changes, rather than every cycle
+		target := optimizedAllocation[va.Name].Accelerator
+		if !changes[utils.FullName(va.Name, va.Namespace)] || va.Status.DesiredOptimizedAlloc.Accelerator == target {
+			continue
+		}
+		r.recordEvent(va, corev1.EventTypeWarning, constants.EventReasonAcceleratorChangeSkipped,
+			"Not moving from %s to %s in direct actuation mode, as it would scale the Deployment to zero first; change the accelerator of the Deployment to apply it",
+			va.Status.CurrentAlloc.Accelerator, target)
+	}
+
+	if r.planExecutor == nil {
+		r.executePlan(ctx, plan, updateList, cfg)
+		return
+	}
+	r.planExecutor.Submit(func(ctx context.Context) {
+		r.executePlan(ctx, plan, updateList, cfg)
+	})
+}
+
+// executePlan scales the Deployments of the variants step by step following the plan, waiting for each step to
+// converge. The remaining steps are abandoned if a step fails, and the plan of the next optimization resumes them.
+func (r *VariantAutoscalingReconciler) executePlan(
+	ctx context.Context,
+	plan *infernoSolver.Plan,
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	cfg actuator.Config,
+) {
+	if plan == nil || len(plan.Steps) == 0 {
+		return
+	}
+	targets := make(map[string]types.NamespacedName)
+	vaByServer := make(map[string]*llmdVariantAutoscalingV1alpha1.VariantAutoscaling)
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		serverName := utils.FullName(va.Name, va.Namespace)
+		targets[serverName] = types.NamespacedName{Name: va.Name, Namespace: va.Namespace}
+		vaByServer[serverName] = va
+	}
+
+	completed, err := actuator.NewDirectActuator(r.Client, cfg.StepTimeout).Execute(ctx, plan, targets)
+	if err != nil && ctx.Err() != nil {
+		logger.Log.Info("Allocation plan superseded, abandoning the remaining steps - ", "completed_steps: ", completed, ", total_steps: ", len(plan.Steps))
+		return
+	}
+	if err != nil {
+		logger.Log.Error(err, "failed to execute allocation plan - ", "completed_steps: ", completed, ", total_steps: ", len(plan.Steps))
+		if va, ok := vaByServer[plan.Steps[completed].ServerName]; ok {
+			r.recordEvent(va, corev1.EventTypeWarning, constants.EventReasonActuationFailed,
+				"Step %d of the allocation plan failed, abandoning the remaining steps: %v", completed, err)
+		}
+		return
+	}
+	logger.Log.Info("Allocation plan executed - ", "steps: ", completed)
+}
+
+// recordSnapshot keeps the system data and allocation solution of an optimization cycle, the solution being dropped
+// if the optimization failed, and writes them to the snapshot ConfigMap, if configured.
+func (r *VariantAutoscalingReconciler) recordSnapshot(
+	ctx context.Context,
+	cfg snapshot.Config,
+	systemData *infernoConfig.SystemData,
+	solution *infernoConfig.AllocationSolution,
+	optimizeErr error,
+) {
+	if r.Snapshots == nil {
+		return
+	}
+	if optimizeErr != nil {
+		solution = nil
+	}
+	snap, err := r.Snapshots.Record(r.now(), systemData, solution)
+	if err != nil {
+		logger.Log.Error(err, "failed to record optimization snapshot")
+		return
+	}
+	if cfg.ConfigMapName == "" {
+		return
+	}
+	if err := r.writeSnapshotConfigMap(ctx, cfg.ConfigMapName, snap); err != nil {
+		logger.Log.Error(err, "failed to write optimization snapshot - ", "configMap: ", cfg.ConfigMapName)
+	}
+}
+
+// writeSnapshotConfigMap writes a snapshot to a ConfigMap in the configuration namespace, creating it if missing.
+func (r *VariantAutoscalingReconciler) writeSnapshotConfigMap(ctx context.Context, name string, snap *snapshot.Snapshot) error {
+	cm := corev1.ConfigMap{}
+	err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: r.Scope.configNamespace()}, &cm)
+	if apierrors.IsNotFound(err) {
+		cm = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: r.Scope.configNamespace()},
+			Data:       snap.Data(),
+		}
+		return r.Create(ctx, &cm)
+	}
+	if err != nil {
+		return err
+	}
+	cm.Data = snap.Data()
+	return r.Update(ctx, &cm)
+}
+
+// applyQueueBacklogScaleUps compares the observed waiting and running requests of each variant with those
+// predicted by the queueing model, and raises the optimized number of replicas if the observed backlog exceeds
+// what the current replicas can drain within the TTFT SLO. It returns the scaling reason of the variants it scaled up.
+func (r *VariantAutoscalingReconciler) applyQueueBacklogScaleUps(
+	ctx context.Context,
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	optimizedAllocation map[string]llmdVariantAutoscalingV1alpha1.OptimizedAlloc,
+	serviceClassCm map[string]string,
+) map[string]string {
+	metricsEmitter := metrics.NewMetricsEmitter()
+	scalingReasons := make(map[string]string)
+
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		alloc, ok := optimizedAllocation[va.Name]
+		if !ok {
+			continue
+		}
+		current := va.Status.CurrentAlloc
+
+		observed, err := collector.CollectQueueState(ctx, r.PromAPI, va.Spec.ModelID, va.Namespace)
+		if err != nil {
+			logger.Log.Error(err, "unable to collect queue state - ", "variantAutoscaling-name: ", va.Name)
+			continue
+		}
+
+		profile := findAcceleratorProfile(va, current.Accelerator)
+		if profile == nil {
+			logger.Log.Debug("No accelerator profile for current accelerator, skipping queue analysis - ",
+				"variantAutoscaling-name: ", va.Name, ", accelerator: ", current.Accelerator)
+			continue
+		}
+
+		arrivalRate, _ := strconv.ParseFloat(current.Load.ArrivalRate, 64)
+		avgInTokens, _ := strconv.ParseFloat(current.Load.AvgInputTokens, 64)
+		avgOutTokens, _ := strconv.ParseFloat(current.Load.AvgOutputTokens, 64)
+		evaluator, err := backlog.NewEvaluator(profile, int(math.Round(avgInTokens)), int(math.Round(avgOutTokens)))
+		if err != nil {
+			logger.Log.Debug("Unable to build queue model, skipping queue analysis - ", "variantAutoscaling-name: ", va.Name, ", reason: ", err)
+			continue
+		}
+
+		predicted, err := evaluator.Predict(current.NumReplicas, arrivalRate)
+		if err != nil {
+			logger.Log.Debug("Unable to predict queue state - ", "variantAutoscaling-name: ", va.Name, ", reason: ", err)
+		} else {
+			logger.Log.Debug("Queue state - ", "variantAutoscaling-name: ", va.Name,
+				", observed: ", observed, ", predicted: ", predicted)
+			if err := metricsEmitter.EmitQueueMetrics(ctx, va, observed.Waiting, predicted.Waiting, observed.Running, predicted.Running); err != nil {
+				logger.Log.Error(err, "failed to emit queue metrics - ", "variantAutoscaling-name: ", va.Name)
+			}
+		}
+
+		entry, _, err := utils.FindModelSLO(serviceClassCm, va.Spec.ModelID)
+		if err != nil {
+			continue
+		}
+		target := backlog.EmergencyReplicas(observed.Waiting, current.NumReplicas, evaluator.DrainablePerReplica(float64(entry.SLOTTFT)))
+		if target <= alloc.NumReplicas {
+			continue
+		}
+		if alloc.Accelerator != current.Accelerator {
+			logger.Log.Info("Queue backlog exceeds SLO, but optimized allocation moves to another accelerator - ",
+				"variantAutoscaling-name: ", va.Name, ", waiting: ", observed.Waiting, ", accelerator: ", alloc.Accelerator)
+			continue
+		}
+		logger.Log.Info("Queue backlog exceeds SLO, scaling up - ", "variantAutoscaling-name: ", va.Name,
+			", waiting: ", observed.Waiting, ", optimized: ", alloc.NumReplicas, ", applied: ", target)
+		addRecommendationNote(va, fmt.Sprintf("raised from %d to %d replicas to drain %.0f waiting requests within the TTFT SLO",
+			alloc.NumReplicas, target, observed.Waiting))
+		alloc.NumReplicas = target
+		optimizedAllocation[va.Name] = alloc
+		scalingReasons[va.Name] = constants.ScalingReasonQueueBacklog
+	}
+	return scalingReasons
+}
+
+// stabilizeScaleDowns holds the optimized number of replicas of each variant at the highest recommendation
+// made within its startup latency, so that a scale-down is not applied if it would have to be reversed before
+// a replacement replica could be ready.
+func (r *VariantAutoscalingReconciler) stabilizeScaleDowns(
+	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
+	optimizedAllocation map[string]llmdVariantAutoscalingV1alpha1.OptimizedAlloc,
+	startupLatencies map[string]time.Duration,
+) {
+	now := r.now()
+	for i := range updateList.Items {
+		va := &updateList.Items[i]
+		alloc, ok := optimizedAllocation[va.Name]
+		if !ok {
+			continue
+		}
+		vaFullName := utils.FullName(va.Name, va.Namespace)
+		replicas := r.stabilizer.Stabilize(vaFullName, alloc.NumReplicas, startupLatencies[vaFullName], now)
+		if replicas != alloc.NumReplicas {
+			logger.Log.Info("Holding scale-down within startup latency - ", "variantAutoscaling-name: ", va.Name,
+				", optimized: ", alloc.NumReplicas, ", applied: ", replicas, ", startupLatency: ", startupLatencies[vaFullName])
+			addRecommendationNote(va, fmt.Sprintf("scale-down from %d to %d replicas held within the startup latency of %s",
+				replicas, alloc.NumReplicas, startupLatencies[vaFullName]))
+			alloc.NumReplicas = replicas
+			optimizedAllocation[va.Name] = alloc
+		}
+	}
+}
+
+// skipVariant reports why a variant is skipped from optimization: it sets the given condition to False, updates
+// the Ready condition and writes the status of the variant, and records a Warning event.
+func (r *VariantAutoscalingReconciler) skipVariant(
+	ctx context.Context,
+	va *llmdVariantAutoscalingV1alpha1.VariantAutoscaling,
+	conditionType, conditionReason, eventReason, message string,
+) {
+	llmdVariantAutoscalingV1alpha1.SetCondition(va, conditionType, metav1.ConditionFalse, conditionReason, message)
+	llmdVariantAutoscalingV1alpha1.SetReadyCondition(va)
+	if err := utils.UpdateStatusWithBackoff(ctx, r.Client, va, utils.StandardBackoff, "VariantAutoscaling"); err != nil {
+		logger.Log.Error(err, "failed to update status of skipped variantAutoscaling - ", "variantAutoscaling-name: ", va.Name)
+	}
+	r.recordEvent(va, corev1.EventTypeWarning, eventReason, "Skipping optimization: %s", message)
+}
+
+// recordEvent records a Kubernetes Event on a variant, if an event recorder is configured
+func (r *VariantAutoscalingReconciler) recordEvent(va *llmdVariantAutoscalingV1alpha1.VariantAutoscaling, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(va, eventType, reason, messageFmt, args...)
+}
+
+// recordRecommendationChange records an event and counts a scaling operation when the optimized allocation of a
+// variant differs from the previous one, or, on the first optimization, from the current allocation.
+func (r *VariantAutoscalingReconciler) recordRecommendationChange(
+	ctx context.Context,
+	va *llmdVariantAutoscalingV1alpha1.VariantAutoscaling,
+	previous, desired llmdVariantAutoscalingV1alpha1.OptimizedAlloc,
+	reason string,
+) {
+	if previous.Accelerator == "" {
+		previous.Accelerator = va.Status.CurrentAlloc.Accelerator
+		previous.NumReplicas = va.Status.CurrentAlloc.NumReplicas
+	}
+	if previous.Accelerator == desired.Accelerator && previous.NumReplicas == desired.NumReplicas {
+		return
+	}
+	r.recordEvent(va, corev1.EventTypeNormal, constants.EventReasonRecommendationChanged,
+		"Recommendation changed from %d to %d replicas, accelerator %s -> %s (%s)",
+		previous.NumReplicas, desired.NumReplicas, previous.Accelerator, desired.Accelerator, reason)
+
+	var direction string
+	switch {
+	case desired.NumReplicas > previous.NumReplicas:
+		direction = "up"
+	case desired.NumReplicas < previous.NumReplicas:
+		direction = "down"
+	default:
+		// accelerator change at the same number of replicas
+		return
+	}
+	if err := metrics.NewMetricsEmitter().EmitReplicaScalingMetrics(ctx, va, direction, reason); err != nil {
+		logger.Log.Error(err, "failed to emit replica scaling metrics - ", "variantAutoscaling-name: ", va.Name)
+	}
+}
+
+// addRecommendationNote appends to the recommendation reason of a variant an adjustment made after optimization
+func addRecommendationNote(va *llmdVariantAutoscalingV1alpha1.VariantAutoscaling, note string) {
+	if va.Status.Recommendation == nil {
+		va.Status.Recommendation = &llmdVariantAutoscalingV1alpha1.Recommendation{}
+	}
+	if va.Status.Recommendation.Reason == "" {
+		va.Status.Recommendation.Reason = note
+		return
+	}
+	va.Status.Recommendation.Reason += "; " + note
+}
+
 // applyOptimizedAllocations applies the optimized allocation to all VariantAutoscaling resources.
 func (r *VariantAutoscalingReconciler) applyOptimizedAllocations(
 	ctx context.Context,
 	updateList *llmdVariantAutoscalingV1alpha1.VariantAutoscalingList,
 	optimizedAllocation map[string]llmdVariantAutoscalingV1alpha1.OptimizedAlloc,
+	scalingReasons map[string]string,
 ) error {
 	logger.Log.Debug("Optimization metrics emitted, starting to process variants - ", "variant_count: ", len(updateList.Items))
 
@@ -360,6 +1456,7 @@ func (r *VariantAutoscalingReconciler) applyOptimizedAllocations(
 		// Note: ownerReference is now set earlier in prepareVariantAutoscalings
 		// This ensures it's set even if metrics aren't available yet
 
+		previousAlloc := updateVa.Status.DesiredOptimizedAlloc
 		updateVa.Status.CurrentAlloc = va.Status.CurrentAlloc
 		updateVa.Status.DesiredOptimizedAlloc = optimizedAllocation[va.Name]
 		updateVa.Status.Actuation.Applied = false // No longer directly applying changes
@@ -368,6 +1465,7 @@ func (r *VariantAutoscalingReconciler) applyOptimizedAllocations(
 		// This ensures we don't lose the MetricsAvailable condition when fetching fresh copy from API
 		// Always copy, even if empty, to preserve conditions set during prepareVariantAutoscalings
 		updateVa.Status.Conditions = va.Status.Conditions
+		updateVa.Status.Recommendation = va.Status.Recommendation
 
 		// Set OptimizationReady condition to True on successful optimization
 		llmdVariantAutoscalingV1alpha1.SetCondition(&updateVa,
@@ -377,6 +1475,7 @@ func (r *VariantAutoscalingReconciler) applyOptimizedAllocations(
 			fmt.Sprintf("Optimization completed: %d replicas on %s",
 				updateVa.Status.DesiredOptimizedAlloc.NumReplicas,
 				updateVa.Status.DesiredOptimizedAlloc.Accelerator))
+		llmdVariantAutoscalingV1alpha1.SetReadyCondition(&updateVa)
 
 		act := actuator.NewActuator(r.Client)
 
@@ -392,6 +1491,12 @@ func (r *VariantAutoscalingReconciler) applyOptimizedAllocations(
 			logger.Log.Error(err, "failed to patch status for variantAutoscaling after retries - ", "variantAutoscaling-name: ", updateVa.Name)
 			continue
 		}
+
+		reason, ok := scalingReasons[va.Name]
+		if !ok {
+			reason = constants.ScalingReasonOptimization
+		}
+		r.recordRecommendationChange(ctx, &updateVa, previousAlloc, updateVa.Status.DesiredOptimizedAlloc, reason)
 	}
 
 	logger.Log.Debug("Completed variant processing loop")
@@ -442,7 +1547,17 @@ func (r *VariantAutoscalingReconciler) SetupWithManager(mgr ctrl.Manager) error
 		return fmt.Errorf("failed to create prometheus client: %w", err)
 	}
 
-	r.PromAPI = promv1.NewAPI(promClient)
+	// time and count failures of all queries made while reconciling
+	r.PromAPI = metrics.InstrumentPromAPI(promv1.NewAPI(promClient))
+
+	// record the objects read and the queries made in each optimization cycle
+	if r.Trace != nil {
+		r.Client = trace.RecordingClient(r.Client)
+		r.PromAPI = trace.RecordingPromAPI(r.PromAPI)
+	}
+
+	// republish the last recommendations as soon as this replica is elected
+	metrics.DefaultLeaderGate().OnElected(r.publishRecommendations)
 
 	// Validate that the API is working by testing a simple query with retry logic
 	if err := utils.ValidatePrometheusAPI(context.Background(), r.PromAPI); err != nil {
@@ -453,37 +1568,32 @@ func (r *VariantAutoscalingReconciler) SetupWithManager(mgr ctrl.Manager) error
 
 	//logger.Log.Info("Prometheus client initialized (validation skipped)")
 
+	// the global optimization runs on its own, periodically and when triggered by reconciles or configuration changes
+	r.optimizationLoop = newOptimizationLoop(r.optimize, minOptimizationInterval)
+	if err := mgr.Add(r.optimizationLoop); err != nil {
+		return fmt.Errorf("failed to add optimization loop: %w", err)
+	}
+
+	// plans of the direct actuator execute on their own, superseded by the plan of the next optimization
+	r.planExecutor = newPlanExecutor()
+	if err := mgr.Add(r.planExecutor); err != nil {
+		return fmt.Errorf("failed to add plan executor: %w", err)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&llmdVariantAutoscalingV1alpha1.VariantAutoscaling{}).
-		// Watch the specific ConfigMap to trigger global reconcile
-		Watches(
-			&corev1.ConfigMap{},
-			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
-				if obj.GetName() == configMapName && obj.GetNamespace() == configMapNamespace {
-					return []reconcile.Request{{}}
-				}
-				return nil
-			}),
-			// Predicate to filter only the target configmap
-			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
-				return obj.GetName() == configMapName && obj.GetNamespace() == configMapNamespace
-			})),
-		).
 		Named("variantAutoscaling").
-		WithEventFilter(predicate.Funcs{
-			CreateFunc: func(e event.CreateEvent) bool {
-				return true
-			},
-			UpdateFunc: func(e event.UpdateEvent) bool {
-				return false
-			},
-			DeleteFunc: func(e event.DeleteEvent) bool {
-				return false
-			},
-			GenericFunc: func(e event.GenericEvent) bool {
-				return false
-			},
-		}).
+		// variant creation, deletion and spec changes
+		WatchesRawSource(source.Kind(mgr.GetCache(), &llmdVariantAutoscalingV1alpha1.VariantAutoscaling{},
+			&handler.TypedEnqueueRequestForObject[*llmdVariantAutoscalingV1alpha1.VariantAutoscaling]{},
+			variantAutoscalingPredicate())).
+		// changes to the pod template of the target Deployment of a variant
+		WatchesRawSource(source.Kind(mgr.GetCache(), &appsv1.Deployment{},
+			handler.TypedEnqueueRequestsFromMapFunc(r.deploymentVariant),
+			deploymentPredicate())).
+		// changes to the optimization, accelerator cost and service class ConfigMaps affect all variants
+		WatchesRawSource(source.Kind(mgr.GetCache(), &corev1.ConfigMap{},
+			triggerOptimizationHandler[*corev1.ConfigMap](r),
+			configMapPredicate(r.Scope.configNamespace()))).
 		Complete(r)
 }
 
@@ -549,7 +1659,7 @@ func (r *VariantAutoscalingReconciler) getPrometheusConfigFromEnv() (*interfaces
 
 func (r *VariantAutoscalingReconciler) getPrometheusConfigFromConfigMap(ctx context.Context) (*interfaces.PrometheusConfig, error) {
 	cm := corev1.ConfigMap{}
-	err := utils.GetConfigMapWithBackoff(ctx, r.Client, configMapName, configMapNamespace, &cm)
+	err := utils.GetConfigMapWithBackoff(ctx, r.Client, configMapName, r.Scope.configNamespace(), &cm)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ConfigMap for Prometheus config: %w", err)
 	}
@@ -581,14 +1691,17 @@ func (r *VariantAutoscalingReconciler) getPrometheusConfigFromConfigMap(ctx cont
 	return config, nil
 }
 
-func (r *VariantAutoscalingReconciler) readOptimizationConfig(ctx context.Context) (interval string, err error) {
+// readOptimizationConfig returns the data of the optimization ConfigMap (interval, forecasting options, ...)
+func (r *VariantAutoscalingReconciler) readOptimizationConfig(ctx context.Context) (map[string]string, error) {
 	cm := corev1.ConfigMap{}
-	err = utils.GetConfigMapWithBackoff(ctx, r.Client, configMapName, configMapNamespace, &cm)
+	err := utils.GetConfigMapWithBackoff(ctx, r.Client, configMapName, r.Scope.configNamespace(), &cm)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to get optimization configmap after retries: %w", err)
+		return nil, fmt.Errorf("failed to get optimization configmap after retries: %w", err)
 	}
 
-	interval = cm.Data["GLOBAL_OPT_INTERVAL"]
-	return interval, nil
+	if cm.Data == nil {
+		return map[string]string{}, nil
+	}
+	return cm.Data, nil
 }
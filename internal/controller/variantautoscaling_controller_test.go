@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -29,6 +30,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -137,7 +139,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 			configMap = &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      configMapName,
-					Namespace: configMapNamespace,
+					Namespace: DefaultConfigNamespace,
 				},
 			}
 			err = k8sClient.Delete(ctx, configMap)
@@ -170,7 +172,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 				Scheme: k8sClient.Scheme(),
 			}
 
-			_, err := controllerReconciler.readServiceClassConfig(ctx, "service-classes-config", configMapNamespace)
+			_, err := controllerReconciler.readServiceClassConfig(ctx, "service-classes-config", DefaultConfigNamespace)
 			Expect(err).To(HaveOccurred(), "Expected error when reading missing serviceClass ConfigMap")
 		})
 
@@ -181,7 +183,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 				Scheme: k8sClient.Scheme(),
 			}
 
-			_, err := controllerReconciler.readAcceleratorConfig(ctx, "accelerator-unit-costs", configMapNamespace)
+			_, err := controllerReconciler.readAcceleratorConfig(ctx, "accelerator-unit-costs", DefaultConfigNamespace)
 			Expect(err).To(HaveOccurred(), "Expected error when reading missing accelerator ConfigMap")
 		})
 
@@ -243,7 +245,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 			configMap = &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      configMapName,
-					Namespace: configMapNamespace,
+					Namespace: DefaultConfigNamespace,
 				},
 			}
 			err = k8sClient.Delete(ctx, configMap)
@@ -260,7 +262,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 			configMap := &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      configMapName,
-					Namespace: configMapNamespace,
+					Namespace: DefaultConfigNamespace,
 				},
 			}
 			err := k8sClient.Delete(ctx, configMap)
@@ -269,7 +271,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 			configMap = &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      configMapName,
-					Namespace: configMapNamespace,
+					Namespace: DefaultConfigNamespace,
 					Labels: map[string]string{
 						"app.kubernetes.io/name": "workload-variant-autoscaler",
 					},
@@ -282,9 +284,9 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 			}
 			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
 
-			interval, err := controllerReconciler.readOptimizationConfig(ctx)
+			optimizationConfig, err := controllerReconciler.readOptimizationConfig(ctx)
 			Expect(err).NotTo(HaveOccurred(), "Unexpected error when reading variant autoscaling optimization ConfigMap with missing interval")
-			Expect(interval).To(Equal(""), "Expected empty interval value")
+			Expect(optimizationConfig["GLOBAL_OPT_INTERVAL"]).To(Equal(""), "Expected empty interval value")
 		})
 
 		It("should return empty on variant autoscaling optimization ConfigMap with missing prometheus base URL", func() {
@@ -297,7 +299,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 			configMap := &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      configMapName,
-					Namespace: configMapNamespace,
+					Namespace: DefaultConfigNamespace,
 				},
 			}
 			err := k8sClient.Delete(ctx, configMap)
@@ -306,7 +308,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 			configMap = &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      configMapName,
-					Namespace: configMapNamespace,
+					Namespace: DefaultConfigNamespace,
 					Labels: map[string]string{
 						"app.kubernetes.io/name": "workload-variant-autoscaler",
 					},
@@ -334,7 +336,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 			configMap := &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      configMapName,
-					Namespace: configMapNamespace,
+					Namespace: DefaultConfigNamespace,
 				},
 			}
 			err := k8sClient.Delete(ctx, configMap)
@@ -343,7 +345,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 			configMap = &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      configMapName,
-					Namespace: configMapNamespace,
+					Namespace: DefaultConfigNamespace,
 					Labels: map[string]string{
 						"app.kubernetes.io/name": "workload-variant-autoscaler",
 					},
@@ -370,7 +372,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 			configMap := &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      configMapName,
-					Namespace: configMapNamespace,
+					Namespace: DefaultConfigNamespace,
 				},
 			}
 			err := k8sClient.Delete(ctx, configMap)
@@ -379,7 +381,7 @@ var _ = Describe("VariantAutoscalings Controller", func() {
 			configMap = &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      configMapName,
-					Namespace: configMapNamespace,
+					Namespace: DefaultConfigNamespace,
 					Labels: map[string]string{
 						"app.kubernetes.io/name": "workload-variant-autoscaler",
 					},
@@ -679,7 +681,7 @@ data:
 			configMap = &v1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      configMapName,
-					Namespace: configMapNamespace,
+					Namespace: DefaultConfigNamespace,
 				},
 			}
 			err = k8sClient.Delete(ctx, configMap)
@@ -743,11 +745,11 @@ data:
 			}
 
 			By("Reading the required configmaps")
-			accMap, err := controllerReconciler.readAcceleratorConfig(ctx, "accelerator-unit-costs", configMapNamespace)
+			accMap, err := controllerReconciler.readAcceleratorConfig(ctx, "accelerator-unit-costs", DefaultConfigNamespace)
 			Expect(err).NotTo(HaveOccurred(), "Failed to read accelerator config")
 			Expect(accMap).NotTo(BeNil(), "Accelerator config map should not be nil")
 
-			serviceClassMap, err := controllerReconciler.readServiceClassConfig(ctx, "service-classes-config", configMapNamespace)
+			serviceClassMap, err := controllerReconciler.readServiceClassConfig(ctx, "service-classes-config", DefaultConfigNamespace)
 			Expect(err).NotTo(HaveOccurred(), "Failed to read service class config")
 			Expect(serviceClassMap).NotTo(BeNil(), "Service class config map should not be nil")
 
@@ -790,18 +792,20 @@ data:
 				QueryResults: map[string]model.Value{},
 				QueryErrors:  map[string]error{},
 			}
+			recorder := record.NewFakeRecorder(100)
 
 			controllerReconciler := &VariantAutoscalingReconciler{
-				Client:  k8sClient,
-				Scheme:  k8sClient.Scheme(),
-				PromAPI: mockPromAPI,
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				PromAPI:  mockPromAPI,
+				Recorder: recorder,
 			}
 
 			By("Reading the required configmaps")
-			accMap, err := controllerReconciler.readAcceleratorConfig(ctx, "accelerator-unit-costs", configMapNamespace)
+			accMap, err := controllerReconciler.readAcceleratorConfig(ctx, "accelerator-unit-costs", DefaultConfigNamespace)
 			Expect(err).NotTo(HaveOccurred())
 
-			serviceClassMap, err := controllerReconciler.readServiceClassConfig(ctx, "service-classes-config", configMapNamespace)
+			serviceClassMap, err := controllerReconciler.readServiceClassConfig(ctx, "service-classes-config", DefaultConfigNamespace)
 			Expect(err).NotTo(HaveOccurred())
 
 			var variantAutoscalingList llmdVariantAutoscalingV1alpha1.VariantAutoscalingList
@@ -831,8 +835,16 @@ data:
 						Equal(llmdVariantAutoscalingV1alpha1.ReasonPrometheusError),
 						Equal(llmdVariantAutoscalingV1alpha1.ReasonMetricsMissing),
 					))
+
+					readyCondition := llmdVariantAutoscalingV1alpha1.GetCondition(&updatedVa, llmdVariantAutoscalingV1alpha1.TypeReady)
+					Expect(readyCondition).NotTo(BeNil(),
+						fmt.Sprintf("Ready condition should be written with MetricsAvailable for %s", va.Name))
+					Expect(readyCondition.Status).To(Equal(metav1.ConditionFalse))
 				}
 			}
+
+			By("Checking that skipped variants are reported with Warning events")
+			Expect(recorder.Events).To(Receive(HavePrefix(v1.EventTypeWarning)))
 		})
 
 		It("should set OptimizationReady condition when optimization succeeds", func() {
@@ -844,14 +856,11 @@ data:
 				QueryErrors: map[string]error{},
 			}
 
-			controllerReconciler := &VariantAutoscalingReconciler{
-				Client:  k8sClient,
-				Scheme:  k8sClient.Scheme(),
-				PromAPI: mockPromAPI,
-			}
+			controllerReconciler := NewVariantAutoscalingReconciler(k8sClient, k8sClient.Scheme())
+			controllerReconciler.PromAPI = mockPromAPI
 
-			By("Performing a full reconciliation")
-			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{})
+			By("Performing a global optimization")
+			_, err := controllerReconciler.optimize(ctx)
 			Expect(err).NotTo(HaveOccurred())
 
 			By("Checking that conditions are set correctly")
@@ -871,4 +880,36 @@ data:
 			}
 		})
 	})
+
+	Context("When variants are skipped or deleted", func() {
+		It("should only retain the variants with an optimized allocation", func() {
+			updateList := &llmdVariantAutoscalingV1alpha1.VariantAutoscalingList{Items: []llmdVariantAutoscalingV1alpha1.VariantAutoscaling{
+				{ObjectMeta: metav1.ObjectMeta{Name: "optimized", Namespace: "default"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "unallocated", Namespace: "default"}},
+			}}
+			optimizedAllocation := map[string]llmdVariantAutoscalingV1alpha1.OptimizedAlloc{
+				"optimized": {Accelerator: "A100", NumReplicas: 2},
+			}
+
+			recommended := recommendedVariants(updateList, optimizedAllocation)
+			Expect(recommended).To(HaveLen(1))
+			Expect(recommended[0].Name).To(Equal("optimized"))
+		})
+
+		It("should only forget the state of deleted variants", func() {
+			r := NewVariantAutoscalingReconciler(nil, nil)
+			now := time.Now()
+			kept := llmdVariantAutoscalingV1alpha1.VariantAutoscaling{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "default"}}
+			deleted := llmdVariantAutoscalingV1alpha1.VariantAutoscaling{ObjectMeta: metav1.ObjectMeta{Name: "deleted", Namespace: "default"}}
+			for _, va := range []llmdVariantAutoscalingV1alpha1.VariantAutoscaling{kept, deleted} {
+				r.stabilizer.Stabilize(utils.FullName(va.Name, va.Namespace), 4, time.Minute, now)
+			}
+			r.forgetDeletedVariants([]llmdVariantAutoscalingV1alpha1.VariantAutoscaling{kept, deleted})
+
+			r.forgetDeletedVariants([]llmdVariantAutoscalingV1alpha1.VariantAutoscaling{kept})
+			later := now.Add(time.Second)
+			Expect(r.stabilizer.Stabilize(utils.FullName(kept.Name, kept.Namespace), 1, time.Minute, later)).To(Equal(4))
+			Expect(r.stabilizer.Stabilize(utils.FullName(deleted.Name, deleted.Namespace), 1, time.Minute, later)).To(Equal(1))
+		})
+	})
 })
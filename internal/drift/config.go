@@ -0,0 +1,48 @@
+package drift
+
+import (
+	"strconv"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+)
+
+// ConfigMap keys for drift detection options
+const (
+	KeyDriftThreshold = "WVA_DRIFT_THRESHOLD"
+	KeyDriftCycles    = "WVA_DRIFT_CYCLES"
+)
+
+// default drift detection options
+const (
+	DefaultThreshold = 0.3
+	DefaultCycles    = 5
+)
+
+// Config holds the drift detection options
+type Config struct {
+	Threshold float64 // relative prediction error above which a cycle counts towards drift
+	Cycles    int     // number of consecutive cycles above the threshold to report drift
+}
+
+// ConfigFromData parses drift detection options from the optimization ConfigMap data, using defaults for missing or bad values
+func ConfigFromData(data map[string]string) Config {
+	cfg := Config{
+		Threshold: DefaultThreshold,
+		Cycles:    DefaultCycles,
+	}
+	if val, ok := data[KeyDriftThreshold]; ok && val != "" {
+		if f, err := strconv.ParseFloat(val, 64); err == nil && f > 0 {
+			cfg.Threshold = f
+		} else {
+			logger.Log.Warn("invalid drift threshold, using default", "key", KeyDriftThreshold, "value", val, "default", DefaultThreshold)
+		}
+	}
+	if val, ok := data[KeyDriftCycles]; ok && val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			cfg.Cycles = n
+		} else {
+			logger.Log.Warn("invalid drift cycles, using default", "key", KeyDriftCycles, "value", val, "default", DefaultCycles)
+		}
+	}
+	return cfg
+}
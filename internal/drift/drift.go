@@ -0,0 +1,51 @@
+// Package drift detects when the latencies predicted by the queueing model for the current allocation of a variant
+// persistently differ from the observed latencies, indicating that its performance parameters need recalibration.
+package drift
+
+import (
+	"math"
+	"sync"
+)
+
+// Relative error of a prediction against an observation; 0 if there is no observation
+func RelativeError(predicted, observed float64) float64 {
+	if observed <= 0 {
+		return 0
+	}
+	return math.Abs(predicted-observed) / observed
+}
+
+// Tracker of the number of consecutive cycles the prediction error of each variant stays above the threshold
+type Tracker struct {
+	mu          sync.Mutex
+	consecutive map[string]int
+}
+
+// Create a new tracker
+func NewTracker() *Tracker {
+	return &Tracker{
+		consecutive: make(map[string]int),
+	}
+}
+
+// Observe records the prediction error of a variant in one cycle, and returns the number of consecutive cycles
+// the error has been above the threshold, and whether that reaches the number of cycles to report drift
+func (t *Tracker) Observe(key string, predictionError float64, cfg Config) (cycles int, drifting bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if predictionError <= cfg.Threshold {
+		delete(t.consecutive, key)
+		return 0, false
+	}
+	t.consecutive[key]++
+	cycles = t.consecutive[key]
+	return cycles, cycles >= cfg.Cycles
+}
+
+// Forget the state of a variant
+func (t *Tracker) Forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.consecutive, key)
+}
@@ -0,0 +1,83 @@
+package drift
+
+import (
+	"testing"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.Log = zap.NewNop().Sugar()
+}
+
+func TestRelativeError(t *testing.T) {
+	tests := []struct {
+		predicted float64
+		observed  float64
+		want      float64
+	}{
+		{predicted: 10, observed: 10, want: 0},
+		{predicted: 15, observed: 10, want: 0.5},
+		{predicted: 5, observed: 10, want: 0.5},
+		{predicted: 10, observed: 0, want: 0},
+	}
+	for _, tt := range tests {
+		if got := RelativeError(tt.predicted, tt.observed); got != tt.want {
+			t.Errorf("RelativeError(%v, %v) = %v, want %v", tt.predicted, tt.observed, got, tt.want)
+		}
+	}
+}
+
+func TestTrackerObserve(t *testing.T) {
+	cfg := Config{Threshold: 0.3, Cycles: 3}
+	tracker := NewTracker()
+
+	steps := []struct {
+		err          float64
+		wantCycles   int
+		wantDrifting bool
+	}{
+		{err: 0.5, wantCycles: 1},
+		{err: 0.5, wantCycles: 2},
+		{err: 0.1, wantCycles: 0},
+		{err: 0.4, wantCycles: 1},
+		{err: 0.4, wantCycles: 2},
+		{err: 0.4, wantCycles: 3, wantDrifting: true},
+		{err: 0.9, wantCycles: 4, wantDrifting: true},
+		{err: 0.3, wantCycles: 0},
+	}
+	for i, step := range steps {
+		cycles, drifting := tracker.Observe("va:ns", step.err, cfg)
+		if cycles != step.wantCycles || drifting != step.wantDrifting {
+			t.Errorf("step %d: Observe(%v) = (%d, %v), want (%d, %v)", i, step.err, cycles, drifting, step.wantCycles, step.wantDrifting)
+		}
+	}
+
+	if cycles, _ := tracker.Observe("other:ns", 0.5, cfg); cycles != 1 {
+		t.Errorf("Observe() on another variant = %d cycles, want 1", cycles)
+	}
+	tracker.Forget("other:ns")
+	if cycles, _ := tracker.Observe("other:ns", 0.5, cfg); cycles != 1 {
+		t.Errorf("Observe() after Forget() = %d cycles, want 1", cycles)
+	}
+}
+
+func TestConfigFromData(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]string
+		want Config
+	}{
+		{name: "defaults", data: map[string]string{}, want: Config{Threshold: DefaultThreshold, Cycles: DefaultCycles}},
+		{name: "configured", data: map[string]string{KeyDriftThreshold: "0.5", KeyDriftCycles: "10"}, want: Config{Threshold: 0.5, Cycles: 10}},
+		{name: "invalid", data: map[string]string{KeyDriftThreshold: "-1", KeyDriftCycles: "x"}, want: Config{Threshold: DefaultThreshold, Cycles: DefaultCycles}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConfigFromData(tt.data); got != tt.want {
+				t.Errorf("ConfigFromData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package forecast
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+)
+
+// ConfigMap keys for forecasting options
+const (
+	KeyForecastEnabled      = "WVA_FORECAST_ENABLED"
+	KeyForecastLeadTime     = "WVA_FORECAST_LEAD_TIME"
+	KeyForecastLookback     = "WVA_FORECAST_LOOKBACK"
+	KeyForecastStep         = "WVA_FORECAST_STEP"
+	KeyForecastSeasonLength = "WVA_FORECAST_SEASON_LENGTH"
+)
+
+// default forecasting options
+const (
+	DefaultLeadTime = 2 * time.Minute
+	DefaultLookback = 30 * time.Minute
+	DefaultStep     = 30 * time.Second
+)
+
+// Config holds the arrival rate forecasting options
+type Config struct {
+	Enabled      bool          // use forecast arrival rate instead of the instantaneous rate
+	LeadTime     time.Duration // how far ahead of now to forecast
+	Lookback     time.Duration // length of history used to fit the model
+	Step         time.Duration // resolution of history samples
+	SeasonLength int           // number of samples in one season (0 disables seasonality)
+}
+
+// ConfigFromData parses forecasting options from the optimization ConfigMap data, using defaults for missing or bad values
+func ConfigFromData(data map[string]string) Config {
+	cfg := Config{
+		Enabled:      strings.EqualFold(data[KeyForecastEnabled], "true"),
+		LeadTime:     parseDuration(data, KeyForecastLeadTime, DefaultLeadTime),
+		Lookback:     parseDuration(data, KeyForecastLookback, DefaultLookback),
+		Step:         parseDuration(data, KeyForecastStep, DefaultStep),
+		SeasonLength: 0,
+	}
+	if val, ok := data[KeyForecastSeasonLength]; ok && val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			cfg.SeasonLength = n
+		} else {
+			logger.Log.Warn("invalid forecast season length, disabling seasonality", "key", KeyForecastSeasonLength, "value", val)
+		}
+	}
+	return cfg
+}
+
+// Forecast horizon in number of history steps
+func (c Config) HorizonSteps() int {
+	return HorizonSteps(c.LeadTime, c.Step)
+}
+
+func parseDuration(data map[string]string, key string, def time.Duration) time.Duration {
+	val, ok := data[key]
+	if !ok || val == "" {
+		return def
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		logger.Log.Warn("invalid forecast duration, using default", "key", key, "value", val, "default", def)
+		return def
+	}
+	return d
+}
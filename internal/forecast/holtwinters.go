@@ -0,0 +1,126 @@
+// Package forecast provides arrival rate forecasting used to size servers ahead of load changes.
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// default smoothing factors
+const (
+	DefaultAlpha = 0.5 // level
+	DefaultBeta  = 0.3 // trend
+	DefaultGamma = 0.1 // seasonality
+)
+
+// minimum number of samples needed to fit a trend
+const minSamples = 3
+
+// Holt-Winters (triple exponential smoothing) model with additive trend and seasonality
+type HoltWinters struct {
+	Alpha        float64 // level smoothing factor in (0,1]
+	Beta         float64 // trend smoothing factor in [0,1]
+	Gamma        float64 // seasonal smoothing factor in [0,1]
+	SeasonLength int     // number of samples in one season (seasonality disabled if less than 2)
+}
+
+// Create a new Holt-Winters model with default smoothing factors
+func NewHoltWinters(seasonLength int) *HoltWinters {
+	return &HoltWinters{
+		Alpha:        DefaultAlpha,
+		Beta:         DefaultBeta,
+		Gamma:        DefaultGamma,
+		SeasonLength: seasonLength,
+	}
+}
+
+// Fit the model to a series of equally spaced samples and forecast the value horizon steps after the last sample.
+// Seasonality is only used if the series covers at least two seasons, otherwise a trend-only (Holt) model is fitted.
+func (hw *HoltWinters) Forecast(series []float64, horizon int) (float64, error) {
+	if err := hw.check(); err != nil {
+		return 0, err
+	}
+	if len(series) < minSamples {
+		return 0, fmt.Errorf("not enough samples to forecast: have %d, need at least %d", len(series), minSamples)
+	}
+	if horizon < 0 {
+		return 0, fmt.Errorf("invalid forecast horizon %d", horizon)
+	}
+	m := hw.SeasonLength
+	if m < 2 || len(series) < 2*m {
+		return hw.forecastTrend(series, horizon), nil
+	}
+	return hw.forecastSeasonal(series, horizon), nil
+}
+
+// double exponential smoothing (level and trend)
+func (hw *HoltWinters) forecastTrend(series []float64, horizon int) float64 {
+	level := series[0]
+	trend := series[1] - series[0]
+	for t := 1; t < len(series); t++ {
+		prevLevel := level
+		level = hw.Alpha*series[t] + (1-hw.Alpha)*(level+trend)
+		trend = hw.Beta*(level-prevLevel) + (1-hw.Beta)*trend
+	}
+	return level + float64(horizon)*trend
+}
+
+// triple exponential smoothing (level, trend, and additive seasonality)
+func (hw *HoltWinters) forecastSeasonal(series []float64, horizon int) float64 {
+	m := hw.SeasonLength
+	n := len(series)
+
+	// initialize level and trend from the first two seasons
+	firstAvg := mean(series[:m])
+	secondAvg := mean(series[m : 2*m])
+	level := firstAvg
+	trend := (secondAvg - firstAvg) / float64(m)
+
+	// initialize seasonal components from the first season
+	seasonal := make([]float64, m)
+	for i := 0; i < m; i++ {
+		seasonal[i] = series[i] - firstAvg
+	}
+
+	for t := m; t < n; t++ {
+		prevLevel := level
+		s := seasonal[t%m]
+		level = hw.Alpha*(series[t]-s) + (1-hw.Alpha)*(level+trend)
+		trend = hw.Beta*(level-prevLevel) + (1-hw.Beta)*trend
+		seasonal[t%m] = hw.Gamma*(series[t]-level) + (1-hw.Gamma)*s
+	}
+	return level + float64(horizon)*trend + seasonal[(n-1+horizon)%m]
+}
+
+// check validity of smoothing factors
+func (hw *HoltWinters) check() error {
+	if hw.Alpha <= 0 || hw.Alpha > 1 || hw.Beta < 0 || hw.Beta > 1 || hw.Gamma < 0 || hw.Gamma > 1 {
+		return fmt.Errorf("invalid smoothing factors %s", hw)
+	}
+	return nil
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// Number of steps of a given length needed to cover a lead time (at least one step)
+func HorizonSteps(leadTime time.Duration, step time.Duration) int {
+	if step <= 0 || leadTime <= 0 {
+		return 1
+	}
+	return max(int(math.Ceil(float64(leadTime)/float64(step))), 1)
+}
+
+func (hw *HoltWinters) String() string {
+	return fmt.Sprintf("{alpha=%v, beta=%v, gamma=%v, seasonLength=%d}",
+		hw.Alpha, hw.Beta, hw.Gamma, hw.SeasonLength)
+}
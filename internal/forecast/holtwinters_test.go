@@ -0,0 +1,147 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.Log = zap.NewNop().Sugar()
+}
+
+func TestForecastConstantSeries(t *testing.T) {
+	series := []float64{60, 60, 60, 60, 60, 60}
+	hw := NewHoltWinters(0)
+	got, err := hw.Forecast(series, 4)
+	if err != nil {
+		t.Fatalf("Forecast() unexpected error: %v", err)
+	}
+	if math.Abs(got-60) > 1e-9 {
+		t.Errorf("Forecast() = %v, want 60", got)
+	}
+}
+
+func TestForecastLinearRamp(t *testing.T) {
+	series := make([]float64, 20)
+	for i := range series {
+		series[i] = 10 + 5*float64(i)
+	}
+	hw := NewHoltWinters(0)
+	got, err := hw.Forecast(series, 4)
+	if err != nil {
+		t.Fatalf("Forecast() unexpected error: %v", err)
+	}
+	want := 10 + 5*float64(len(series)-1+4)
+	if math.Abs(got-want) > 1 {
+		t.Errorf("Forecast() = %v, want about %v", got, want)
+	}
+	if got <= series[len(series)-1] {
+		t.Errorf("Forecast() = %v, should be ahead of last sample %v on a ramp", got, series[len(series)-1])
+	}
+}
+
+func TestForecastSeasonal(t *testing.T) {
+	season := []float64{10, 20, 40, 20}
+	series := make([]float64, 0, 5*len(season))
+	for i := 0; i < 5; i++ {
+		series = append(series, season...)
+	}
+	hw := NewHoltWinters(len(season))
+	for horizon := 1; horizon <= len(season); horizon++ {
+		got, err := hw.Forecast(series, horizon)
+		if err != nil {
+			t.Fatalf("Forecast() unexpected error: %v", err)
+		}
+		want := season[(len(series)-1+horizon)%len(season)]
+		if math.Abs(got-want) > 2 {
+			t.Errorf("Forecast(horizon=%d) = %v, want about %v", horizon, got, want)
+		}
+	}
+}
+
+func TestForecastSeasonalFallsBackToTrend(t *testing.T) {
+	// fewer than two seasons of data
+	series := []float64{1, 2, 3, 4, 5}
+	hw := NewHoltWinters(10)
+	got, err := hw.Forecast(series, 1)
+	if err != nil {
+		t.Fatalf("Forecast() unexpected error: %v", err)
+	}
+	if got <= 5 {
+		t.Errorf("Forecast() = %v, want trend continuation above 5", got)
+	}
+}
+
+func TestForecastErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		hw      *HoltWinters
+		series  []float64
+		horizon int
+	}{
+		{name: "too few samples", hw: NewHoltWinters(0), series: []float64{1, 2}, horizon: 1},
+		{name: "negative horizon", hw: NewHoltWinters(0), series: []float64{1, 2, 3}, horizon: -1},
+		{name: "zero alpha", hw: &HoltWinters{Alpha: 0, Beta: 0.1}, series: []float64{1, 2, 3}, horizon: 1},
+		{name: "beta above one", hw: &HoltWinters{Alpha: 0.5, Beta: 1.5}, series: []float64{1, 2, 3}, horizon: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.hw.Forecast(tt.series, tt.horizon); err == nil {
+				t.Errorf("Forecast() expected error")
+			}
+		})
+	}
+}
+
+func TestHorizonSteps(t *testing.T) {
+	tests := []struct {
+		leadTime time.Duration
+		step     time.Duration
+		want     int
+	}{
+		{leadTime: 2 * time.Minute, step: 30 * time.Second, want: 4},
+		{leadTime: 100 * time.Second, step: 30 * time.Second, want: 4},
+		{leadTime: 0, step: 30 * time.Second, want: 1},
+		{leadTime: time.Minute, step: 0, want: 1},
+	}
+	for _, tt := range tests {
+		if got := HorizonSteps(tt.leadTime, tt.step); got != tt.want {
+			t.Errorf("HorizonSteps(%v, %v) = %d, want %d", tt.leadTime, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestConfigFromData(t *testing.T) {
+	cfg := ConfigFromData(map[string]string{})
+	if cfg.Enabled || cfg.LeadTime != DefaultLeadTime || cfg.Lookback != DefaultLookback ||
+		cfg.Step != DefaultStep || cfg.SeasonLength != 0 {
+		t.Errorf("ConfigFromData(empty) = %+v, want defaults", cfg)
+	}
+
+	cfg = ConfigFromData(map[string]string{
+		KeyForecastEnabled:      "true",
+		KeyForecastLeadTime:     "5m",
+		KeyForecastLookback:     "2h",
+		KeyForecastStep:         "1m",
+		KeyForecastSeasonLength: "60",
+	})
+	if !cfg.Enabled || cfg.LeadTime != 5*time.Minute || cfg.Lookback != 2*time.Hour ||
+		cfg.Step != time.Minute || cfg.SeasonLength != 60 {
+		t.Errorf("ConfigFromData() = %+v, unexpected values", cfg)
+	}
+	if cfg.HorizonSteps() != 5 {
+		t.Errorf("HorizonSteps() = %d, want 5", cfg.HorizonSteps())
+	}
+
+	cfg = ConfigFromData(map[string]string{
+		KeyForecastLeadTime:     "bogus",
+		KeyForecastSeasonLength: "-3",
+	})
+	if cfg.LeadTime != DefaultLeadTime || cfg.SeasonLength != 0 {
+		t.Errorf("ConfigFromData(bad values) = %+v, want defaults", cfg)
+	}
+}
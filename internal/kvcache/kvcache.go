@@ -0,0 +1,41 @@
+// Package kvcache derives the effective max batch size of a server when its KV cache, rather than the configured
+// max number of sequences, limits the number of requests that can run concurrently.
+package kvcache
+
+import (
+	"math"
+)
+
+// KV cache usage fraction at or above which the KV cache is considered saturated
+const SaturationThreshold = 0.9
+
+// Check whether the KV cache is saturated, given its usage fraction and the request preemption rate
+func Saturated(usage, preemptionRate float64) bool {
+	return usage >= SaturationThreshold || preemptionRate > 0
+}
+
+// Effective max batch size of a server, given the KV cache usage fraction, the request preemption rate,
+// the average number of running requests per replica, and the configured max batch size.
+// When the KV cache is saturated, the number of requests it can hold at the current request sizes is
+// estimated as running / usage; if that is below the configured max batch size, the KV cache is the binding
+// limit and the estimate is returned, with kvBound set.
+func EffectiveMaxBatch(usage, preemptionRate, runningPerReplica float64, configured int) (maxBatch int, kvBound bool) {
+	if !Saturated(usage, preemptionRate) || usage <= 0 || runningPerReplica <= 0 {
+		return configured, false
+	}
+	capacity := max(int(math.Floor(runningPerReplica/min(usage, 1))), 1)
+	if configured > 0 && capacity >= configured {
+		return configured, false
+	}
+	return capacity, true
+}
+
+// Max batch size of a replica with toMemory GB of accelerator memory, given the KV cache bound max batch size
+// measured on a replica with fromMemory GB, assuming the KV cache grows in proportion to the memory; false if
+// either memory size is unknown.
+func ScaleMaxBatch(maxBatch, fromMemory, toMemory int) (int, bool) {
+	if maxBatch <= 0 || fromMemory <= 0 || toMemory <= 0 {
+		return 0, false
+	}
+	return max(maxBatch*toMemory/fromMemory, 1), true
+}
@@ -0,0 +1,73 @@
+package kvcache
+
+import "testing"
+
+func TestSaturated(t *testing.T) {
+	tests := []struct {
+		usage          float64
+		preemptionRate float64
+		want           bool
+	}{
+		{usage: 0.5, preemptionRate: 0, want: false},
+		{usage: 0.9, preemptionRate: 0, want: true},
+		{usage: 0.5, preemptionRate: 2, want: true},
+	}
+	for _, tt := range tests {
+		if got := Saturated(tt.usage, tt.preemptionRate); got != tt.want {
+			t.Errorf("Saturated(%v, %v) = %v, want %v", tt.usage, tt.preemptionRate, got, tt.want)
+		}
+	}
+}
+
+func TestEffectiveMaxBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		usage          float64
+		preemptionRate float64
+		running        float64
+		configured     int
+		wantBatch      int
+		wantKVBound    bool
+	}{
+		{name: "not saturated", usage: 0.5, running: 20, configured: 64, wantBatch: 64},
+		{name: "cache full below max sequences", usage: 0.95, running: 19, configured: 64, wantBatch: 20, wantKVBound: true},
+		{name: "preempting", usage: 0.8, preemptionRate: 3, running: 16, configured: 64, wantBatch: 20, wantKVBound: true},
+		{name: "max sequences binding", usage: 0.95, running: 64, configured: 64, wantBatch: 64},
+		{name: "usage above one", usage: 1.2, running: 10, configured: 64, wantBatch: 10, wantKVBound: true},
+		{name: "no running requests", usage: 0.95, running: 0, configured: 64, wantBatch: 64},
+		{name: "not configured", usage: 0.95, running: 19, configured: 0, wantBatch: 20, wantKVBound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBatch, gotKVBound := EffectiveMaxBatch(tt.usage, tt.preemptionRate, tt.running, tt.configured)
+			if gotBatch != tt.wantBatch || gotKVBound != tt.wantKVBound {
+				t.Errorf("EffectiveMaxBatch() = (%d, %v), want (%d, %v)", gotBatch, gotKVBound, tt.wantBatch, tt.wantKVBound)
+			}
+		})
+	}
+}
+
+func TestScaleMaxBatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBatch   int
+		fromMemory int
+		toMemory   int
+		wantBatch  int
+		wantOK     bool
+	}{
+		{name: "larger memory", maxBatch: 20, fromMemory: 80, toMemory: 192, wantBatch: 48, wantOK: true},
+		{name: "smaller memory", maxBatch: 20, fromMemory: 80, toMemory: 48, wantBatch: 12, wantOK: true},
+		{name: "at least one", maxBatch: 1, fromMemory: 80, toMemory: 24, wantBatch: 1, wantOK: true},
+		{name: "unknown memory", maxBatch: 20, fromMemory: 80, toMemory: 0},
+		{name: "unknown measured memory", maxBatch: 20, fromMemory: 0, toMemory: 80},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBatch, gotOK := ScaleMaxBatch(tt.maxBatch, tt.fromMemory, tt.toMemory)
+			if gotBatch != tt.wantBatch || gotOK != tt.wantOK {
+				t.Errorf("ScaleMaxBatch() = (%d, %v), want (%d, %v)", gotBatch, gotOK, tt.wantBatch, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// leaderGate is the gate of the recommendation series registered by InitMetrics
+var leaderGate = NewLeaderGate()
+
+// DefaultLeaderGate returns the gate of the recommendation series registered by InitMetrics
+func DefaultLeaderGate() *LeaderGate {
+	return leaderGate
+}
+
+// LeaderGate tracks whether this replica of the controller is the elected leader, and hence whether it exports
+// the per-variant recommendation series that external autoscalers act on. Without leader election, every
+// replica is a leader. With leader election, a replica exports no recommendation series until elected; once it
+// steps down, it keeps exporting its last values, marked stale by the leader gauge, until it exits.
+type LeaderGate struct {
+	mu       sync.Mutex
+	leader   bool
+	resigned bool
+	onElect  []func(ctx context.Context)
+}
+
+// Create a new gate, leading until leader election is enabled
+func NewLeaderGate() *LeaderGate {
+	return &LeaderGate{leader: true}
+}
+
+// EnableLeaderElection makes this replica a follower until elected
+func (g *LeaderGate) EnableLeaderElection() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.leader = false
+	g.resigned = false
+}
+
+// OnElected registers a function called when this replica is elected, e.g., to publish the last recommendations
+// before the first reconciliation completes
+func (g *LeaderGate) OnElected(fn func(ctx context.Context)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.onElect = append(g.onElect, fn)
+}
+
+// IsLeader returns whether this replica is the leader
+func (g *LeaderGate) IsLeader() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.leader
+}
+
+// Exporting returns whether this replica exports recommendation series, i.e., it is or was the leader
+func (g *LeaderGate) Exporting() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.leader || g.resigned
+}
+
+// elected marks this replica as the leader, returning the functions to call on election
+func (g *LeaderGate) elected() []func(ctx context.Context) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.leader = true
+	g.resigned = false
+	return append([]func(ctx context.Context){}, g.onElect...)
+}
+
+// resign marks this replica as no longer the leader
+func (g *LeaderGate) resign() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.leader {
+		g.leader = false
+		g.resigned = true
+	}
+}
+
+// Start is called by the manager once this replica is elected, and returns when it steps down
+func (g *LeaderGate) Start(ctx context.Context) error {
+	for _, fn := range g.elected() {
+		fn(ctx)
+	}
+	<-ctx.Done()
+	g.resign()
+	return nil
+}
+
+// NeedLeaderElection makes the manager start the gate only on the elected replica
+func (g *LeaderGate) NeedLeaderElection() bool {
+	return true
+}
+
+// leaderGatedCollector collects the series of a collector only while its gate is exporting
+type leaderGatedCollector struct {
+	prometheus.Collector
+	gate *LeaderGate
+}
+
+// Collect the series if the gate is exporting
+func (c *leaderGatedCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.gate.Exporting() {
+		c.Collector.Collect(ch)
+	}
+}
+
+// gate a collector by the default leader gate
+func leaderGated(c prometheus.Collector) prometheus.Collector {
+	return &leaderGatedCollector{Collector: c, gate: leaderGate}
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/constants"
+)
+
+// count the series of a metric gathered from a registry
+func gatheredSeries(t *testing.T, registry *prometheus.Registry, name string) int {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() unexpected error: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == name {
+			return len(family.GetMetric())
+		}
+	}
+	return 0
+}
+
+func TestLeaderGateWithoutLeaderElection(t *testing.T) {
+	gate := NewLeaderGate()
+	if !gate.IsLeader() || !gate.Exporting() {
+		t.Errorf("new gate: IsLeader() = %v, Exporting() = %v, want true, true", gate.IsLeader(), gate.Exporting())
+	}
+	if !gate.NeedLeaderElection() {
+		t.Errorf("NeedLeaderElection() = false, want true")
+	}
+}
+
+func TestLeaderGateExportsOnlyOnLeader(t *testing.T) {
+	t.Cleanup(func() { leaderGate = NewLeaderGate() })
+	leaderGate = NewLeaderGate()
+	leaderGate.EnableLeaderElection()
+
+	registry := prometheus.NewRegistry()
+	if err := InitMetrics(registry); err != nil {
+		t.Fatalf("InitMetrics() unexpected error: %v", err)
+	}
+	va := newVA("va", "ns")
+	if err := NewMetricsEmitter().EmitReplicaMetrics(context.Background(), &va, 1, 2, "A100"); err != nil {
+		t.Fatalf("EmitReplicaMetrics() unexpected error: %v", err)
+	}
+
+	// follower
+	if n := gatheredSeries(t, registry, constants.InfernoDesiredReplicas); n != 0 {
+		t.Errorf("follower exports %d desired replicas series, want 0", n)
+	}
+	if got := testutil.ToFloat64(controllerLeader); got != 0 {
+		t.Errorf("follower %s = %v, want 0", constants.InfernoControllerLeader, got)
+	}
+
+	// elected
+	published := make(chan struct{})
+	leaderGate.OnElected(func(ctx context.Context) { close(published) })
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- leaderGate.Start(ctx) }()
+	<-published
+	if n := gatheredSeries(t, registry, constants.InfernoDesiredReplicas); n != 1 {
+		t.Errorf("leader exports %d desired replicas series, want 1", n)
+	}
+	if got := testutil.ToFloat64(controllerLeader); got != 1 {
+		t.Errorf("leader %s = %v, want 1", constants.InfernoControllerLeader, got)
+	}
+
+	// stepped down: the last values are still exported, marked stale by the leader gauge
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Start() unexpected error: %v", err)
+	}
+	if leaderGate.IsLeader() || !leaderGate.Exporting() {
+		t.Errorf("resigned leader: IsLeader() = %v, Exporting() = %v, want false, true", leaderGate.IsLeader(), leaderGate.Exporting())
+	}
+	if n := gatheredSeries(t, registry, constants.InfernoDesiredReplicas); n != 1 {
+		t.Errorf("resigned leader exports %d desired replicas series, want 1", n)
+	}
+	if got := testutil.ToFloat64(controllerLeader); got != 0 {
+		t.Errorf("resigned leader %s = %v, want 0", constants.InfernoControllerLeader, got)
+	}
+}
@@ -3,6 +3,8 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	llmdOptv1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
 	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/constants"
@@ -14,10 +16,57 @@ var (
 	desiredReplicas     *prometheus.GaugeVec
 	currentReplicas     *prometheus.GaugeVec
 	desiredRatio        *prometheus.GaugeVec
+	observedArrivalRate *prometheus.GaugeVec
+	forecastArrivalRate *prometheus.GaugeVec
+	startupLatency      *prometheus.GaugeVec
+
+	observedRequestsWaiting  *prometheus.GaugeVec
+	predictedRequestsWaiting *prometheus.GaugeVec
+	observedRequestsRunning  *prometheus.GaugeVec
+	predictedRequestsRunning *prometheus.GaugeVec
+
+	predictedITL         *prometheus.GaugeVec
+	predictedTTFT        *prometheus.GaugeVec
+	predictedUtilization *prometheus.GaugeVec
+	maxRPMPerReplica     *prometheus.GaugeVec
+	sloITL               *prometheus.GaugeVec
+	sloTTFT              *prometheus.GaugeVec
+
+	itlPredictionError    *prometheus.GaugeVec
+	ttftPredictionError   *prometheus.GaugeVec
+	performanceModelDrift *prometheus.GaugeVec
+
+	budgetReducedReplicas *prometheus.GaugeVec
+	costBudget            *prometheus.GaugeVec
+	costBudgetHeadroom    *prometheus.GaugeVec
+	planStep              *prometheus.GaugeVec
+
+	controllerLeader               prometheus.GaugeFunc
+	reconcileDuration              prometheus.Histogram
+	prometheusQueryDuration        *prometheus.HistogramVec
+	prometheusQueryErrors          *prometheus.CounterVec
+	optimizationDuration           prometheus.Histogram
+	variantsProcessed              prometheus.Histogram
+	variantsSkipped                *prometheus.HistogramVec
+	lastSuccessfulOptimizationTime prometheus.Gauge
 )
 
+// buckets of the numbers of variants in an optimization cycle
+var variantCountBuckets = append([]float64{0}, prometheus.ExponentialBuckets(1, 2, 11)...)
+
+// reasons for which variants have been skipped, observed in each cycle once seen
+var skipReasons = struct {
+	sync.Mutex
+	seen map[string]bool
+}{seen: make(map[string]bool)}
+
 // InitMetrics registers all custom metrics with the provided registry
 func InitMetrics(registry prometheus.Registerer) error {
+	ownedSeries.reset()
+	skipReasons.Lock()
+	skipReasons.seen = make(map[string]bool)
+	skipReasons.Unlock()
+
 	replicaScalingTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: constants.InfernoReplicaScalingTotal,
@@ -46,20 +95,306 @@ func InitMetrics(registry prometheus.Registerer) error {
 		},
 		[]string{constants.LabelVariantName, constants.LabelNamespace, constants.LabelAcceleratorType},
 	)
+	observedArrivalRate = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoObservedArrivalRate,
+			Help: "Observed arrival rate (requests per minute) for each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	forecastArrivalRate = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoForecastArrivalRate,
+			Help: "Forecast arrival rate (requests per minute) at the configured lead time for each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	startupLatency = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoStartupLatencySeconds,
+			Help: "Startup latency (seconds) of a new replica used in scaling decisions for each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	observedRequestsWaiting = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoObservedRequestsWaiting,
+			Help: "Observed number of waiting requests across replicas for each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	predictedRequestsWaiting = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoPredictedRequestsWaiting,
+			Help: "Number of waiting requests across replicas predicted by the queueing model for each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	observedRequestsRunning = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoObservedRequestsRunning,
+			Help: "Observed number of running requests across replicas for each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	predictedRequestsRunning = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoPredictedRequestsRunning,
+			Help: "Number of running requests across replicas predicted by the queueing model for each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	predictedITL = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoPredictedITLMsec,
+			Help: "Average inter token latency (msec) predicted for the applied allocation of each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace, constants.LabelAcceleratorType},
+	)
+	predictedTTFT = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoPredictedTTFTMsec,
+			Help: "Average time to first token (msec) predicted for the applied allocation of each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace, constants.LabelAcceleratorType},
+	)
+	predictedUtilization = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoPredictedUtilization,
+			Help: "Average fraction of the max batch in use predicted for the applied allocation of each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace, constants.LabelAcceleratorType},
+	)
+	maxRPMPerReplica = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoMaxRPMPerReplica,
+			Help: "Maximum request rate (requests per minute) a replica of the applied allocation of each variant can serve within the SLO",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace, constants.LabelAcceleratorType},
+	)
+	sloITL = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoSLOITLMsec,
+			Help: "Inter token latency SLO (msec) of the service class of each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	sloTTFT = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoSLOTTFTMsec,
+			Help: "Time to first token SLO (msec) of the service class of each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	itlPredictionError = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoITLPredictionError,
+			Help: "Relative error of the inter token latency predicted for the current allocation of each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	ttftPredictionError = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoTTFTPredictionError,
+			Help: "Relative error of the time to first token predicted for the current allocation of each variant",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	performanceModelDrift = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoPerformanceModelDrift,
+			Help: "Whether the performance model of each variant has drifted from the observed latencies (1) or not (0)",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	budgetReducedReplicas = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoBudgetReducedReplicas,
+			Help: "Number of replicas removed from the allocation of each variant to fit the cost budget",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace},
+	)
+	costBudget = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoCostBudget,
+			Help: "Cost ceiling of the allocations, cluster-wide or of a namespace",
+		},
+		[]string{constants.LabelScope, constants.LabelNamespace},
+	)
+	costBudgetHeadroom = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoCostBudgetHeadroom,
+			Help: "Cost remaining under the ceiling after the last optimization, cluster-wide or of a namespace",
+		},
+		[]string{constants.LabelScope, constants.LabelNamespace},
+	)
+	planStep = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoPlanStep,
+			Help: "Position of each step of the allocation plan of the last optimization, releases before acquisitions",
+		},
+		[]string{constants.LabelVariantName, constants.LabelNamespace, constants.LabelAction},
+	)
+	controllerLeader = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoControllerLeader,
+			Help: "Whether this controller replica is the elected leader (1) or not (0)",
+		},
+		func() float64 {
+			if leaderGate.IsLeader() {
+				return 1
+			}
+			return 0
+		},
+	)
+	reconcileDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    constants.InfernoReconcileDurationSeconds,
+			Help:    "Duration (seconds) of a reconciliation, i.e., a global optimization cycle",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		},
+	)
+	prometheusQueryDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    constants.InfernoPrometheusQueryDurationSeconds,
+			Help:    "Duration (seconds) of Prometheus queries by queried metric",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{constants.LabelQuery},
+	)
+	prometheusQueryErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: constants.InfernoPrometheusQueryErrorsTotal,
+			Help: "Total number of failed Prometheus queries by queried metric",
+		},
+		[]string{constants.LabelQuery},
+	)
+	optimizationDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    constants.InfernoOptimizationDurationSeconds,
+			Help:    "Time (seconds) the solver takes to find the optimized allocations",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+		},
+	)
+	variantsProcessed = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    constants.InfernoVariantsProcessed,
+			Help:    "Number of variants included in an optimization cycle",
+			Buckets: variantCountBuckets,
+		},
+	)
+	variantsSkipped = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    constants.InfernoVariantsSkipped,
+			Help:    "Number of variants skipped from an optimization cycle by reason",
+			Buckets: variantCountBuckets,
+		},
+		[]string{constants.LabelReason},
+	)
+	lastSuccessfulOptimizationTime = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: constants.InfernoLastSuccessfulOptimizationTimestampSeconds,
+			Help: "Unix time (seconds) of the last successful optimization",
+		},
+	)
 
 	// Register metrics with the registry
-	if err := registry.Register(replicaScalingTotal); err != nil {
+	if err := registry.Register(leaderGated(replicaScalingTotal)); err != nil {
 		return fmt.Errorf("failed to register replicaScalingTotal metric: %w", err)
 	}
-	if err := registry.Register(desiredReplicas); err != nil {
+	if err := registry.Register(leaderGated(desiredReplicas)); err != nil {
 		return fmt.Errorf("failed to register desiredReplicas metric: %w", err)
 	}
-	if err := registry.Register(currentReplicas); err != nil {
+	if err := registry.Register(leaderGated(currentReplicas)); err != nil {
 		return fmt.Errorf("failed to register currentReplicas metric: %w", err)
 	}
-	if err := registry.Register(desiredRatio); err != nil {
+	if err := registry.Register(leaderGated(desiredRatio)); err != nil {
 		return fmt.Errorf("failed to register desiredRatio metric: %w", err)
 	}
+	if err := registry.Register(leaderGated(observedArrivalRate)); err != nil {
+		return fmt.Errorf("failed to register observedArrivalRate metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(forecastArrivalRate)); err != nil {
+		return fmt.Errorf("failed to register forecastArrivalRate metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(startupLatency)); err != nil {
+		return fmt.Errorf("failed to register startupLatency metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(observedRequestsWaiting)); err != nil {
+		return fmt.Errorf("failed to register observedRequestsWaiting metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(predictedRequestsWaiting)); err != nil {
+		return fmt.Errorf("failed to register predictedRequestsWaiting metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(observedRequestsRunning)); err != nil {
+		return fmt.Errorf("failed to register observedRequestsRunning metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(predictedRequestsRunning)); err != nil {
+		return fmt.Errorf("failed to register predictedRequestsRunning metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(predictedITL)); err != nil {
+		return fmt.Errorf("failed to register predictedITL metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(predictedTTFT)); err != nil {
+		return fmt.Errorf("failed to register predictedTTFT metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(predictedUtilization)); err != nil {
+		return fmt.Errorf("failed to register predictedUtilization metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(maxRPMPerReplica)); err != nil {
+		return fmt.Errorf("failed to register maxRPMPerReplica metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(sloITL)); err != nil {
+		return fmt.Errorf("failed to register sloITL metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(sloTTFT)); err != nil {
+		return fmt.Errorf("failed to register sloTTFT metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(itlPredictionError)); err != nil {
+		return fmt.Errorf("failed to register itlPredictionError metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(ttftPredictionError)); err != nil {
+		return fmt.Errorf("failed to register ttftPredictionError metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(performanceModelDrift)); err != nil {
+		return fmt.Errorf("failed to register performanceModelDrift metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(budgetReducedReplicas)); err != nil {
+		return fmt.Errorf("failed to register budgetReducedReplicas metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(costBudget)); err != nil {
+		return fmt.Errorf("failed to register costBudget metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(costBudgetHeadroom)); err != nil {
+		return fmt.Errorf("failed to register costBudgetHeadroom metric: %w", err)
+	}
+	if err := registry.Register(leaderGated(planStep)); err != nil {
+		return fmt.Errorf("failed to register planStep metric: %w", err)
+	}
+	if err := registry.Register(controllerLeader); err != nil {
+		return fmt.Errorf("failed to register controllerLeader metric: %w", err)
+	}
+	if err := registry.Register(reconcileDuration); err != nil {
+		return fmt.Errorf("failed to register reconcileDuration metric: %w", err)
+	}
+	if err := registry.Register(prometheusQueryDuration); err != nil {
+		return fmt.Errorf("failed to register prometheusQueryDuration metric: %w", err)
+	}
+	if err := registry.Register(prometheusQueryErrors); err != nil {
+		return fmt.Errorf("failed to register prometheusQueryErrors metric: %w", err)
+	}
+	if err := registry.Register(optimizationDuration); err != nil {
+		return fmt.Errorf("failed to register optimizationDuration metric: %w", err)
+	}
+	if err := registry.Register(variantsProcessed); err != nil {
+		return fmt.Errorf("failed to register variantsProcessed metric: %w", err)
+	}
+	if err := registry.Register(variantsSkipped); err != nil {
+		return fmt.Errorf("failed to register variantsSkipped metric: %w", err)
+	}
+	if err := registry.Register(lastSuccessfulOptimizationTime); err != nil {
+		return fmt.Errorf("failed to register lastSuccessfulOptimizationTime metric: %w", err)
+	}
 
 	return nil
 }
@@ -112,15 +447,240 @@ func (m *MetricsEmitter) EmitReplicaMetrics(ctx context.Context, va *llmdOptv1al
 		return fmt.Errorf("replica metrics not initialized")
 	}
 
-	currentReplicas.With(baseLabels).Set(float64(current))
-	desiredReplicas.With(baseLabels).Set(float64(desired))
+	ownedSeries.set(currentReplicas, baseLabels, float64(current))
+	ownedSeries.set(desiredReplicas, baseLabels, float64(desired))
 
 	// Avoid division by 0 if current replicas is zero: set the ratio to the desired replicas
 	// Going 0 -> N is treated by using `desired_ratio = N`
 	if current == 0 {
-		desiredRatio.With(baseLabels).Set(float64(desired))
+		ownedSeries.set(desiredRatio, baseLabels, float64(desired))
 		return nil
 	}
-	desiredRatio.With(baseLabels).Set(float64(desired) / float64(current))
+	ownedSeries.set(desiredRatio, baseLabels, float64(desired)/float64(current))
+	return nil
+}
+
+// EmitForecastMetrics emits the observed and forecast arrival rates (requests per minute)
+func (m *MetricsEmitter) EmitForecastMetrics(ctx context.Context, va *llmdOptv1alpha1.VariantAutoscaling, observed, forecast float64) error {
+	labels := prometheus.Labels{
+		constants.LabelVariantName: va.Name,
+		constants.LabelNamespace:   va.Namespace,
+	}
+
+	// These operations are local and should never fail, but we handle errors for debugging
+	if observedArrivalRate == nil || forecastArrivalRate == nil {
+		return fmt.Errorf("forecast metrics not initialized")
+	}
+
+	ownedSeries.set(observedArrivalRate, labels, observed)
+	ownedSeries.set(forecastArrivalRate, labels, forecast)
+	return nil
+}
+
+// EmitStartupLatencyMetric emits the startup latency used in scaling decisions
+func (m *MetricsEmitter) EmitStartupLatencyMetric(ctx context.Context, va *llmdOptv1alpha1.VariantAutoscaling, latency time.Duration) error {
+	labels := prometheus.Labels{
+		constants.LabelVariantName: va.Name,
+		constants.LabelNamespace:   va.Namespace,
+	}
+
+	// These operations are local and should never fail, but we handle errors for debugging
+	if startupLatency == nil {
+		return fmt.Errorf("startup latency metric not initialized")
+	}
+
+	ownedSeries.set(startupLatency, labels, latency.Seconds())
+	return nil
+}
+
+// EmitQueueMetrics emits the observed and predicted numbers of waiting and running requests across replicas
+func (m *MetricsEmitter) EmitQueueMetrics(ctx context.Context, va *llmdOptv1alpha1.VariantAutoscaling,
+	observedWaiting, predictedWaiting, observedRunning, predictedRunning float64) error {
+	labels := prometheus.Labels{
+		constants.LabelVariantName: va.Name,
+		constants.LabelNamespace:   va.Namespace,
+	}
+
+	// These operations are local and should never fail, but we handle errors for debugging
+	if observedRequestsWaiting == nil || predictedRequestsWaiting == nil ||
+		observedRequestsRunning == nil || predictedRequestsRunning == nil {
+		return fmt.Errorf("queue metrics not initialized")
+	}
+
+	ownedSeries.set(observedRequestsWaiting, labels, observedWaiting)
+	ownedSeries.set(predictedRequestsWaiting, labels, predictedWaiting)
+	ownedSeries.set(observedRequestsRunning, labels, observedRunning)
+	ownedSeries.set(predictedRequestsRunning, labels, predictedRunning)
+	return nil
+}
+
+// EmitPredictionMetrics emits the performance predicted for the applied allocation of a variant
+func (m *MetricsEmitter) EmitPredictionMetrics(ctx context.Context, va *llmdOptv1alpha1.VariantAutoscaling,
+	acceleratorType string, itl, ttft, utilization, maxRPM float64) error {
+	labels := prometheus.Labels{
+		constants.LabelVariantName:     va.Name,
+		constants.LabelNamespace:       va.Namespace,
+		constants.LabelAcceleratorType: acceleratorType,
+	}
+
+	// These operations are local and should never fail, but we handle errors for debugging
+	if predictedITL == nil || predictedTTFT == nil || predictedUtilization == nil || maxRPMPerReplica == nil {
+		return fmt.Errorf("prediction metrics not initialized")
+	}
+
+	ownedSeries.set(predictedITL, labels, itl)
+	ownedSeries.set(predictedTTFT, labels, ttft)
+	ownedSeries.set(predictedUtilization, labels, utilization)
+	ownedSeries.set(maxRPMPerReplica, labels, maxRPM)
+	return nil
+}
+
+// EmitSLOMetrics emits the ITL and TTFT SLO targets (msec) of a variant
+func (m *MetricsEmitter) EmitSLOMetrics(ctx context.Context, va *llmdOptv1alpha1.VariantAutoscaling, itl, ttft float64) error {
+	labels := prometheus.Labels{
+		constants.LabelVariantName: va.Name,
+		constants.LabelNamespace:   va.Namespace,
+	}
+
+	// These operations are local and should never fail, but we handle errors for debugging
+	if sloITL == nil || sloTTFT == nil {
+		return fmt.Errorf("SLO metrics not initialized")
+	}
+
+	ownedSeries.set(sloITL, labels, itl)
+	ownedSeries.set(sloTTFT, labels, ttft)
+	return nil
+}
+
+// EmitDriftMetrics emits the relative prediction errors of the ITL and TTFT of a variant, and whether its
+// performance model has drifted
+func (m *MetricsEmitter) EmitDriftMetrics(ctx context.Context, va *llmdOptv1alpha1.VariantAutoscaling,
+	itlError, ttftError float64, drifting bool) error {
+	labels := prometheus.Labels{
+		constants.LabelVariantName: va.Name,
+		constants.LabelNamespace:   va.Namespace,
+	}
+
+	// These operations are local and should never fail, but we handle errors for debugging
+	if itlPredictionError == nil || ttftPredictionError == nil || performanceModelDrift == nil {
+		return fmt.Errorf("drift metrics not initialized")
+	}
+
+	ownedSeries.set(itlPredictionError, labels, itlError)
+	ownedSeries.set(ttftPredictionError, labels, ttftError)
+	if drifting {
+		ownedSeries.set(performanceModelDrift, labels, 1)
+	} else {
+		ownedSeries.set(performanceModelDrift, labels, 0)
+	}
+	return nil
+}
+
+// EmitBudgetReductionMetric emits the number of replicas removed from the allocation of a variant to fit the cost budget
+func (m *MetricsEmitter) EmitBudgetReductionMetric(ctx context.Context, va *llmdOptv1alpha1.VariantAutoscaling, reduced int) error {
+	labels := prometheus.Labels{
+		constants.LabelVariantName: va.Name,
+		constants.LabelNamespace:   va.Namespace,
+	}
+
+	// These operations are local and should never fail, but we handle errors for debugging
+	if budgetReducedReplicas == nil {
+		return fmt.Errorf("budgetReducedReplicas metric not initialized")
+	}
+
+	ownedSeries.set(budgetReducedReplicas, labels, float64(reduced))
+	return nil
+}
+
+// EmitBudgetMetrics replaces the cost ceilings and their headroom, keyed by namespace, the empty namespace being the
+// cluster-wide ceiling; the series of ceilings no longer configured are deleted
+func (m *MetricsEmitter) EmitBudgetMetrics(ctx context.Context, limits, headrooms map[string]float64) error {
+	// These operations are local and should never fail, but we handle errors for debugging
+	if costBudget == nil || costBudgetHeadroom == nil {
+		return fmt.Errorf("budget metrics not initialized")
+	}
+
+	costBudget.Reset()
+	costBudgetHeadroom.Reset()
+	for namespace, limit := range limits {
+		labels := prometheus.Labels{
+			constants.LabelScope:     constants.BudgetScopeNamespace,
+			constants.LabelNamespace: namespace,
+		}
+		if namespace == "" {
+			labels[constants.LabelScope] = constants.BudgetScopeCluster
+		}
+		costBudget.With(labels).Set(limit)
+		costBudgetHeadroom.With(labels).Set(headrooms[namespace])
+	}
+	return nil
+}
+
+// PlanStepLabels identifies a step of the allocation plan in the plan step metric
+type PlanStepLabels struct {
+	VariantName string
+	Namespace   string
+	Action      string
+}
+
+// EmitPlanMetrics replaces the steps of the allocation plan, in order; the series of steps of earlier plans are deleted
+func (m *MetricsEmitter) EmitPlanMetrics(ctx context.Context, steps []PlanStepLabels) error {
+	// These operations are local and should never fail, but we handle errors for debugging
+	if planStep == nil {
+		return fmt.Errorf("plan metrics not initialized")
+	}
+
+	planStep.Reset()
+	for i, step := range steps {
+		planStep.With(prometheus.Labels{
+			constants.LabelVariantName: step.VariantName,
+			constants.LabelNamespace:   step.Namespace,
+			constants.LabelAction:      step.Action,
+		}).Set(float64(i + 1))
+	}
+	return nil
+}
+
+// EmitReconcileDuration emits the duration of a reconciliation
+func (m *MetricsEmitter) EmitReconcileDuration(ctx context.Context, duration time.Duration) error {
+	// These operations are local and should never fail, but we handle errors for debugging
+	if reconcileDuration == nil {
+		return fmt.Errorf("reconcile duration metric not initialized")
+	}
+
+	reconcileDuration.Observe(duration.Seconds())
+	return nil
+}
+
+// EmitOptimizationMetrics emits the solver time of a successful optimization and records when it completed
+func (m *MetricsEmitter) EmitOptimizationMetrics(ctx context.Context, solutionTime time.Duration, completed time.Time) error {
+	// These operations are local and should never fail, but we handle errors for debugging
+	if optimizationDuration == nil || lastSuccessfulOptimizationTime == nil {
+		return fmt.Errorf("optimization metrics not initialized")
+	}
+
+	optimizationDuration.Observe(solutionTime.Seconds())
+	lastSuccessfulOptimizationTime.Set(float64(completed.UnixNano()) / 1e9)
+	return nil
+}
+
+// EmitVariantCounts observes the number of variants included in an optimization cycle, and the number of variants
+// skipped by reason; zero is observed for the reasons of skips in earlier cycles, so that each reason has an
+// observation per cycle once seen
+func (m *MetricsEmitter) EmitVariantCounts(ctx context.Context, processed int, skipped map[string]int) error {
+	// These operations are local and should never fail, but we handle errors for debugging
+	if variantsProcessed == nil || variantsSkipped == nil {
+		return fmt.Errorf("variant count metrics not initialized")
+	}
+
+	variantsProcessed.Observe(float64(processed))
+	skipReasons.Lock()
+	defer skipReasons.Unlock()
+	for reason := range skipped {
+		skipReasons.seen[reason] = true
+	}
+	for reason := range skipReasons.seen {
+		variantsSkipped.With(prometheus.Labels{constants.LabelReason: reason}).Observe(float64(skipped[reason]))
+	}
 	return nil
 }
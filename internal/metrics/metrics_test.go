@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/constants"
+)
+
+// histogram returns the sample count and sum of a histogram
+func histogram(t *testing.T, h prometheus.Histogram) (uint64, float64) {
+	t.Helper()
+	var metric dto.Metric
+	if err := h.Write(&metric); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+}
+
+func TestEmitVariantCounts(t *testing.T) {
+	if err := InitMetrics(prometheus.NewRegistry()); err != nil {
+		t.Fatalf("InitMetrics() unexpected error: %v", err)
+	}
+	m := NewMetricsEmitter()
+	ctx := context.Background()
+
+	if err := m.EmitVariantCounts(ctx, 3, map[string]int{"SLONotFound": 2}); err != nil {
+		t.Fatalf("EmitVariantCounts() unexpected error: %v", err)
+	}
+	if err := m.EmitVariantCounts(ctx, 5, map[string]int{"MetricsMissing": 1}); err != nil {
+		t.Fatalf("EmitVariantCounts() unexpected error: %v", err)
+	}
+
+	if count, sum := histogram(t, variantsProcessed); count != 2 || sum != 8 {
+		t.Errorf("variants processed: count = %d, sum = %v, want 2, 8", count, sum)
+	}
+	// a reason is observed in every cycle once seen, as zero when no variant is skipped for it
+	for reason, want := range map[string]struct {
+		count uint64
+		sum   float64
+	}{
+		"SLONotFound":    {count: 2, sum: 2},
+		"MetricsMissing": {count: 1, sum: 1},
+	} {
+		h := variantsSkipped.With(prometheus.Labels{constants.LabelReason: reason}).(prometheus.Histogram)
+		if count, sum := histogram(t, h); count != want.count || sum != want.sum {
+			t.Errorf("variants skipped{%s}: count = %d, sum = %v, want %d, %v", reason, count, sum, want.count, want.sum)
+		}
+	}
+	if got := testutil.CollectAndCount(variantsSkipped); got != 2 {
+		t.Errorf("variants skipped series = %d, want 2", got)
+	}
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/constants"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+)
+
+// metric name of a selector, e.g., vllm:num_requests_waiting in sum(vllm:num_requests_waiting{model_name="m"})
+var selectorMetricName = regexp.MustCompile(`([a-zA-Z_:][a-zA-Z0-9_:]*)\{`)
+
+// bare metric name, e.g., up
+var bareMetricName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// instrumentedPromAPI is a Prometheus API whose instant and range queries are timed and counted
+// when they fail, labeled by the metric they read
+type instrumentedPromAPI struct {
+	promv1.API
+}
+
+// InstrumentPromAPI wraps a Prometheus API so that the duration and failures of its queries are observed
+func InstrumentPromAPI(api promv1.API) promv1.API {
+	return &instrumentedPromAPI{API: api}
+}
+
+// Query performs an instant query, observing its duration and failure
+func (a *instrumentedPromAPI) Query(ctx context.Context, query string, ts time.Time, opts ...promv1.Option) (model.Value, promv1.Warnings, error) {
+	start := time.Now()
+	val, warn, err := a.API.Query(ctx, query, ts, opts...)
+	observeQuery(query, time.Since(start), err)
+	return val, warn, err
+}
+
+// QueryRange performs a range query, observing its duration and failure
+func (a *instrumentedPromAPI) QueryRange(ctx context.Context, query string, r promv1.Range, opts ...promv1.Option) (model.Value, promv1.Warnings, error) {
+	start := time.Now()
+	val, warn, err := a.API.QueryRange(ctx, query, r, opts...)
+	observeQuery(query, time.Since(start), err)
+	return val, warn, err
+}
+
+// observe the duration and failure of a query, if the metrics are initialized
+func observeQuery(query string, duration time.Duration, err error) {
+	labels := prometheus.Labels{constants.LabelQuery: QueryMetricName(query)}
+	if prometheusQueryDuration != nil {
+		prometheusQueryDuration.With(labels).Observe(duration.Seconds())
+	}
+	if err != nil && prometheusQueryErrors != nil {
+		prometheusQueryErrors.With(labels).Inc()
+	}
+}
+
+// QueryMetricName returns the name of the first metric selected by a query, or the query itself if it is
+// a bare metric name, so that queries can be labeled without the unbounded cardinality of their label values
+func QueryMetricName(query string) string {
+	if match := selectorMetricName.FindStringSubmatch(query); match != nil {
+		return match[1]
+	}
+	if bareMetricName.MatchString(query) {
+		return query
+	}
+	return "other"
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+
+	testutils "github.com/llm-d-incubation/workload-variant-autoscaler/test/utils"
+)
+
+func TestQueryMetricName(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "up", want: "up"},
+		{query: `vllm:request_success_total{model_name="m",namespace="ns"}`, want: "vllm:request_success_total"},
+		{query: `sum(rate(vllm:num_preemptions_total{model_name="m"}[1m]))`, want: "vllm:num_preemptions_total"},
+		{query: `sum(rate(vllm:request_prompt_tokens_sum{model_name="m"}[1m]))/sum(rate(vllm:request_prompt_tokens_count{model_name="m"}[1m]))`,
+			want: "vllm:request_prompt_tokens_sum"},
+		{query: "1 + 1", want: "other"},
+	}
+	for _, tt := range tests {
+		if got := QueryMetricName(tt.query); got != tt.want {
+			t.Errorf("QueryMetricName(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestInstrumentPromAPI(t *testing.T) {
+	if err := InitMetrics(prometheus.NewRegistry()); err != nil {
+		t.Fatalf("InitMetrics() unexpected error: %v", err)
+	}
+	okQuery := `vllm:num_requests_waiting{model_name="m"}`
+	failedQuery := `vllm:num_requests_running{model_name="m"}`
+	api := InstrumentPromAPI(&testutils.MockPromAPI{
+		QueryResults: map[string]model.Value{okQuery: model.Vector{}},
+		QueryErrors:  map[string]error{failedQuery: errors.New("unavailable")},
+	})
+
+	if _, _, err := api.Query(context.Background(), okQuery, time.Now()); err != nil {
+		t.Fatalf("Query() unexpected error: %v", err)
+	}
+	if _, _, err := api.Query(context.Background(), failedQuery, time.Now()); err == nil {
+		t.Fatalf("Query() expected error")
+	}
+
+	if got := testutil.CollectAndCount(prometheusQueryDuration); got != 2 {
+		t.Errorf("query duration series = %d, want 2", got)
+	}
+	if got := testutil.ToFloat64(prometheusQueryErrors.WithLabelValues("vllm:num_requests_running")); got != 1 {
+		t.Errorf("errors for failed query = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(prometheusQueryErrors.WithLabelValues("vllm:num_requests_waiting")); got != 0 {
+		t.Errorf("errors for successful query = %v, want 0", got)
+	}
+}
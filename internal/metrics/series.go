@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"sync"
+
+	llmdOptv1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/constants"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/utils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ownedSeries holds the label sets of the per-variant gauge series set by the emitter, so that the series of
+// variants that no longer exist, or of an accelerator a variant no longer uses, stop being exported
+var ownedSeries = &seriesOwner{
+	series: make(map[string]map[*prometheus.GaugeVec]prometheus.Labels),
+}
+
+// seriesOwner tracks the last label set of each gauge, per variant (keyed by full name)
+type seriesOwner struct {
+	mu     sync.Mutex
+	series map[string]map[*prometheus.GaugeVec]prometheus.Labels
+}
+
+// set the value of the series of a gauge for a variant, deleting the previous series of the variant on that
+// gauge if its labels changed (e.g., a different accelerator)
+func (o *seriesOwner) set(vec *prometheus.GaugeVec, labels prometheus.Labels, value float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key := utils.FullName(labels[constants.LabelVariantName], labels[constants.LabelNamespace])
+	vecs, ok := o.series[key]
+	if !ok {
+		vecs = make(map[*prometheus.GaugeVec]prometheus.Labels)
+		o.series[key] = vecs
+	}
+	if previous, ok := vecs[vec]; ok && !sameLabels(previous, labels) {
+		vec.Delete(previous)
+	}
+	vecs[vec] = labels
+	vec.With(labels).Set(value)
+}
+
+// forget all series, e.g., when the gauges are recreated
+func (o *seriesOwner) reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.series = make(map[string]map[*prometheus.GaugeVec]prometheus.Labels)
+}
+
+// delete all the series of a variant, including its counter series
+func (o *seriesOwner) delete(name, namespace string) {
+	key := utils.FullName(name, namespace)
+	for vec, labels := range o.series[key] {
+		vec.Delete(labels)
+	}
+	delete(o.series, key)
+
+	if replicaScalingTotal != nil {
+		replicaScalingTotal.DeletePartialMatch(prometheus.Labels{
+			constants.LabelVariantName: name,
+			constants.LabelNamespace:   namespace,
+		})
+	}
+}
+
+// retain the series of the given variants, deleting those of all other variants, and return the full names of
+// the variants whose series were deleted
+func (o *seriesOwner) retain(keep map[string]bool) []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var deleted []string
+	for key, vecs := range o.series {
+		if keep[key] {
+			continue
+		}
+		for _, labels := range vecs {
+			o.delete(labels[constants.LabelVariantName], labels[constants.LabelNamespace])
+			break
+		}
+		deleted = append(deleted, key)
+	}
+	return deleted
+}
+
+// check if two label sets are equal
+func sameLabels(a, b prometheus.Labels) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// DeleteStaleVariantMetrics deletes the per-variant series of all variants not in the given list, e.g., deleted,
+// renamed or skipped variants, so that consumers such as the HPA do not act on their last values. It returns the full
+// names of the variants whose series were deleted.
+func (m *MetricsEmitter) DeleteStaleVariantMetrics(vas []llmdOptv1alpha1.VariantAutoscaling) []string {
+	keep := make(map[string]bool, len(vas))
+	for _, va := range vas {
+		keep[utils.FullName(va.Name, va.Namespace)] = true
+	}
+	return ownedSeries.retain(keep)
+}
+
+// DeleteVariantMetrics deletes all the per-variant series of a variant
+func (m *MetricsEmitter) DeleteVariantMetrics(name, namespace string) {
+	ownedSeries.mu.Lock()
+	defer ownedSeries.mu.Unlock()
+	ownedSeries.delete(name, namespace)
+}
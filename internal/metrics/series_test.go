@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	llmdOptv1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/constants"
+)
+
+func newVA(name, namespace string) llmdOptv1alpha1.VariantAutoscaling {
+	return llmdOptv1alpha1.VariantAutoscaling{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+}
+
+func TestAcceleratorChangeDeletesPreviousSeries(t *testing.T) {
+	if err := InitMetrics(prometheus.NewRegistry()); err != nil {
+		t.Fatalf("InitMetrics() unexpected error: %v", err)
+	}
+	m := NewMetricsEmitter()
+	va := newVA("va", "ns")
+	ctx := context.Background()
+
+	if err := m.EmitReplicaMetrics(ctx, &va, 1, 2, "A100"); err != nil {
+		t.Fatalf("EmitReplicaMetrics() unexpected error: %v", err)
+	}
+	if err := m.EmitReplicaMetrics(ctx, &va, 2, 2, "H100"); err != nil {
+		t.Fatalf("EmitReplicaMetrics() unexpected error: %v", err)
+	}
+	for name, vec := range map[string]*prometheus.GaugeVec{
+		"currentReplicas": currentReplicas,
+		"desiredReplicas": desiredReplicas,
+		"desiredRatio":    desiredRatio,
+	} {
+		if got := testutil.CollectAndCount(vec); got != 1 {
+			t.Errorf("%s series = %d, want 1", name, got)
+		}
+	}
+	got := testutil.ToFloat64(currentReplicas.With(prometheus.Labels{
+		constants.LabelVariantName:     "va",
+		constants.LabelNamespace:       "ns",
+		constants.LabelAcceleratorType: "H100",
+	}))
+	if got != 2 {
+		t.Errorf("currentReplicas{H100} = %v, want 2", got)
+	}
+}
+
+func TestEmitPredictionMetrics(t *testing.T) {
+	if err := InitMetrics(prometheus.NewRegistry()); err != nil {
+		t.Fatalf("InitMetrics() unexpected error: %v", err)
+	}
+	m := NewMetricsEmitter()
+	va := newVA("va", "ns")
+	ctx := context.Background()
+
+	if err := m.EmitPredictionMetrics(ctx, &va, "A100", 20, 200, 0.5, 60); err != nil {
+		t.Fatalf("EmitPredictionMetrics() unexpected error: %v", err)
+	}
+	if err := m.EmitPredictionMetrics(ctx, &va, "H100", 12.345, 150.5, 0.25, 120); err != nil {
+		t.Fatalf("EmitPredictionMetrics() unexpected error: %v", err)
+	}
+	labels := prometheus.Labels{
+		constants.LabelVariantName:     "va",
+		constants.LabelNamespace:       "ns",
+		constants.LabelAcceleratorType: "H100",
+	}
+	for name, tt := range map[string]struct {
+		vec  *prometheus.GaugeVec
+		want float64
+	}{
+		"predictedITL":         {vec: predictedITL, want: 12.345},
+		"predictedTTFT":        {vec: predictedTTFT, want: 150.5},
+		"predictedUtilization": {vec: predictedUtilization, want: 0.25},
+		"maxRPMPerReplica":     {vec: maxRPMPerReplica, want: 120},
+	} {
+		if got := testutil.CollectAndCount(tt.vec); got != 1 {
+			t.Errorf("%s series = %d, want 1", name, got)
+		}
+		if got := testutil.ToFloat64(tt.vec.With(labels)); got != tt.want {
+			t.Errorf("%s{H100} = %v, want %v", name, got, tt.want)
+		}
+	}
+}
+
+func TestDeleteStaleVariantMetrics(t *testing.T) {
+	if err := InitMetrics(prometheus.NewRegistry()); err != nil {
+		t.Fatalf("InitMetrics() unexpected error: %v", err)
+	}
+	m := NewMetricsEmitter()
+	kept, deleted := newVA("kept", "ns"), newVA("deleted", "ns")
+	ctx := context.Background()
+
+	for _, va := range []*llmdOptv1alpha1.VariantAutoscaling{&kept, &deleted} {
+		if err := m.EmitReplicaMetrics(ctx, va, 1, 2, "A100"); err != nil {
+			t.Fatalf("EmitReplicaMetrics() unexpected error: %v", err)
+		}
+		if err := m.EmitSLOMetrics(ctx, va, 20, 200); err != nil {
+			t.Fatalf("EmitSLOMetrics() unexpected error: %v", err)
+		}
+		if err := m.EmitReplicaScalingMetrics(ctx, va, "up", constants.ScalingReasonOptimization); err != nil {
+			t.Fatalf("EmitReplicaScalingMetrics() unexpected error: %v", err)
+		}
+	}
+
+	got := m.DeleteStaleVariantMetrics([]llmdOptv1alpha1.VariantAutoscaling{kept})
+	if len(got) != 1 || got[0] != "deleted:ns" {
+		t.Errorf("DeleteStaleVariantMetrics() = %v, want [deleted:ns]", got)
+	}
+	for name, c := range map[string]prometheus.Collector{
+		"desiredReplicas":     desiredReplicas,
+		"sloITL":              sloITL,
+		"replicaScalingTotal": replicaScalingTotal,
+	} {
+		if n := testutil.CollectAndCount(c); n != 1 {
+			t.Errorf("%s series = %d, want 1", name, n)
+		}
+	}
+
+	m.DeleteVariantMetrics("kept", "ns")
+	if n := testutil.CollectAndCount(desiredReplicas); n != 0 {
+		t.Errorf("desiredReplicas series after DeleteVariantMetrics = %d, want 0", n)
+	}
+	if n := testutil.CollectAndCount(replicaScalingTotal); n != 0 {
+		t.Errorf("replicaScalingTotal series after DeleteVariantMetrics = %d, want 0", n)
+	}
+	if got := m.DeleteStaleVariantMetrics(nil); len(got) != 0 {
+		t.Errorf("DeleteStaleVariantMetrics() after deletion = %v, want none", got)
+	}
+}
+
+func TestEmitBudgetMetricsReplacesCeilings(t *testing.T) {
+	if err := InitMetrics(prometheus.NewRegistry()); err != nil {
+		t.Fatalf("InitMetrics() unexpected error: %v", err)
+	}
+	m := NewMetricsEmitter()
+	ctx := context.Background()
+
+	if err := m.EmitBudgetMetrics(ctx, map[string]float64{"": 100, "ns": 40}, map[string]float64{"": 10, "ns": -5}); err != nil {
+		t.Fatalf("EmitBudgetMetrics() unexpected error: %v", err)
+	}
+	if got := testutil.CollectAndCount(costBudget); got != 2 {
+		t.Errorf("costBudget series = %d, want 2", got)
+	}
+	got := testutil.ToFloat64(costBudgetHeadroom.With(prometheus.Labels{
+		constants.LabelScope:     constants.BudgetScopeNamespace,
+		constants.LabelNamespace: "ns",
+	}))
+	if got != -5 {
+		t.Errorf("costBudgetHeadroom{ns} = %v, want -5", got)
+	}
+
+	// the namespace ceiling was removed from the configuration
+	if err := m.EmitBudgetMetrics(ctx, map[string]float64{"": 100}, map[string]float64{"": 20}); err != nil {
+		t.Fatalf("EmitBudgetMetrics() unexpected error: %v", err)
+	}
+	if got := testutil.CollectAndCount(costBudgetHeadroom); got != 1 {
+		t.Errorf("costBudgetHeadroom series = %d, want 1", got)
+	}
+	got = testutil.ToFloat64(costBudgetHeadroom.With(prometheus.Labels{
+		constants.LabelScope:     constants.BudgetScopeCluster,
+		constants.LabelNamespace: "",
+	}))
+	if got != 20 {
+		t.Errorf("costBudgetHeadroom{cluster} = %v, want 20", got)
+	}
+}
@@ -3,6 +3,8 @@ package optimizer
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 
 	llmdOptv1alpha1 "github.com/llm-d-incubation/workload-variant-autoscaler/api/v1alpha1"
 	interfaces "github.com/llm-d-incubation/workload-variant-autoscaler/internal/interfaces"
@@ -52,3 +54,86 @@ func (engine *VariantAutoscalingsEngine) Optimize(ctx context.Context,
 	}
 	return optimizedAllocMap, nil
 }
+
+// Describe, for each variant, the allocations evaluated in the last optimization and the reason for the choice
+func (engine *VariantAutoscalingsEngine) Recommendations(vaList llmdOptv1alpha1.VariantAutoscalingList) map[string]*llmdOptv1alpha1.Recommendation {
+	recommendations := make(map[string]*llmdOptv1alpha1.Recommendation)
+	for _, va := range vaList.Items {
+		server := engine.system.Server(utils.FullName(va.Name, va.Namespace))
+		if server == nil {
+			continue
+		}
+		recommendations[va.Name] = createRecommendation(server)
+	}
+	return recommendations
+}
+
+// create a recommendation from the candidate and chosen allocations of a server
+func createRecommendation(server *inferno.Server) *llmdOptv1alpha1.Recommendation {
+	recommendation := &llmdOptv1alpha1.Recommendation{
+		Saturated: server.Saturated(),
+	}
+
+	accelerators := make([]string, 0, len(server.AllAllocations()))
+	for gName := range server.AllAllocations() {
+		accelerators = append(accelerators, gName)
+	}
+	sort.Strings(accelerators)
+	for _, gName := range accelerators {
+		recommendation.Candidates = append(recommendation.Candidates, createCandidateAllocation(server.AllAllocations()[gName]))
+	}
+
+	alloc := server.Allocation()
+	if alloc == nil {
+		recommendation.Reason = fmt.Sprintf("no feasible allocation among %d candidates", len(accelerators)) + cappedReason(server)
+		return recommendation
+	}
+	recommended := createCandidateAllocation(alloc)
+	recommendation.Recommended = &recommended
+
+	switch {
+	case server.RequiredReplicas() > 0:
+		recommendation.Reason = fmt.Sprintf("%d replicas on %s, reduced from %d needed to meet the SLO at the current load to fit the cost budget",
+			alloc.NumReplicas(), alloc.Accelerator(), server.RequiredReplicas())
+	case len(accelerators) == 1 && server.KeepAccelerator():
+		recommendation.Reason = fmt.Sprintf("%d replicas on %s meet the SLO at the current load, keeping the current accelerator",
+			alloc.NumReplicas(), alloc.Accelerator())
+	case len(accelerators) == 1:
+		recommendation.Reason = fmt.Sprintf("%d replicas on %s meet the SLO at the current load, the only feasible candidate",
+			alloc.NumReplicas(), alloc.Accelerator())
+	default:
+		recommendation.Reason = fmt.Sprintf("%d replicas on %s meet the SLO at the current load with the lowest value among %d candidates",
+			alloc.NumReplicas(), alloc.Accelerator(), len(accelerators))
+	}
+	recommendation.Reason += cappedReason(server)
+	if recommendation.Saturated {
+		recommendation.Reason += "; the allocation is saturated by the current load"
+	}
+	return recommendation
+}
+
+// cappedReason explains why a server was not allocated its preferred allocation under limited capacity, if so
+func cappedReason(server *inferno.Server) string {
+	switch server.CappedBy() {
+	case inferno.CappedByCapacity:
+		return "; capped by the accelerator capacity"
+	case inferno.CappedByQuota:
+		return "; capped by the accelerator quota of the namespace"
+	}
+	return ""
+}
+
+// create a candidate allocation from an inferno allocation
+func createCandidateAllocation(alloc *inferno.Allocation) llmdOptv1alpha1.CandidateAllocation {
+	return llmdOptv1alpha1.CandidateAllocation{
+		Accelerator:      alloc.Accelerator(),
+		NumReplicas:      alloc.NumReplicas(),
+		MaxBatch:         alloc.MaxBatchSize(),
+		Cost:             strconv.FormatFloat(float64(alloc.Cost()), 'f', 2, 32),
+		Value:            strconv.FormatFloat(float64(alloc.Value()), 'f', 2, 32),
+		ITLPredicted:     strconv.FormatFloat(float64(alloc.ITL()), 'f', 2, 32),
+		TTFTPredicted:    strconv.FormatFloat(float64(alloc.TTFT()), 'f', 2, 32),
+		Utilization:      strconv.FormatFloat(float64(alloc.Rho()), 'f', 2, 32),
+		MaxRPMPerReplica: strconv.FormatFloat(float64(alloc.MaxRPM()), 'f', 2, 32),
+	}
+}
@@ -454,6 +454,17 @@ var _ = Describe("Optimizer", Ordered, func() {
 				logger.Log.Info("Optimized allocation entry - ", "key: ", key, ", value: ", value)
 				Expect(value.NumReplicas).To(BeNumerically(">", 1), "Expected optimized number of replicas to be higher than 1 under high load for VariantAutoscaling - ", key)
 			}
+
+			By("Describing the optimization decisions")
+			recommendations := engine.Recommendations(updateList)
+			Expect(recommendations).To(HaveLen(len(updateList.Items)))
+			for key, recommendation := range recommendations {
+				Expect(recommendation.Candidates).NotTo(BeEmpty(), "Expected evaluated candidates for VariantAutoscaling - ", key)
+				Expect(recommendation.Recommended).NotTo(BeNil(), "Expected a recommended allocation for VariantAutoscaling - ", key)
+				Expect(recommendation.Recommended.NumReplicas).To(Equal(optimizedAllocs[key].NumReplicas))
+				Expect(recommendation.Recommended.Accelerator).To(Equal(optimizedAllocs[key].Accelerator))
+				Expect(recommendation.Reason).NotTo(BeEmpty())
+			}
 		})
 	})
 })
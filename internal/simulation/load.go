@@ -0,0 +1,117 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// LoadTrace gives the arrival rate of a server (requests/min) over the time since the start of a simulation
+type LoadTrace interface {
+	Rate(t time.Duration) float64
+}
+
+// Constant arrival rate
+type Constant struct {
+	Value float64
+}
+
+func (l Constant) Rate(t time.Duration) float64 {
+	return l.Value
+}
+
+// Diurnal arrival rate, varying as a cosine between Min and Max, with a maximum at PeakAt and every Period after
+type Diurnal struct {
+	Min    float64
+	Max    float64
+	Period time.Duration
+	PeakAt time.Duration
+}
+
+func (l Diurnal) Rate(t time.Duration) float64 {
+	if l.Period <= 0 {
+		return l.Max
+	}
+	phase := 2 * math.Pi * float64(t-l.PeakAt) / float64(l.Period)
+	return l.Min + (l.Max-l.Min)*(1+math.Cos(phase))/2
+}
+
+// Step in arrival rate, from Before to After at time At
+type Step struct {
+	Before float64
+	After  float64
+	At     time.Duration
+}
+
+func (l Step) Rate(t time.Duration) float64 {
+	if t < l.At {
+		return l.Before
+	}
+	return l.After
+}
+
+// Bursty arrival rate: time is divided in periods of length Burst, each of which is a burst at rate Peak with
+// probability Probability, and at rate Base otherwise. The bursts are drawn from Seed, so that a trace is repeatable.
+type Bursty struct {
+	Base        float64
+	Peak        float64
+	Burst       time.Duration
+	Probability float64
+	Seed        uint64
+}
+
+func (l Bursty) Rate(t time.Duration) float64 {
+	if l.Burst <= 0 || t < 0 {
+		return l.Base
+	}
+	period := uint64(t / l.Burst)
+	// uniform in [0, 1) from the top 53 bits of a hash of the seed and period
+	if float64(splitMix64(l.Seed^splitMix64(period))>>11)/(1<<53) < l.Probability {
+		return l.Peak
+	}
+	return l.Base
+}
+
+// Sum of the arrival rates of traces, e.g. bursts on top of diurnal traffic
+type Sum []LoadTrace
+
+func (l Sum) Rate(t time.Duration) float64 {
+	var rate float64
+	for _, trace := range l {
+		rate += trace.Rate(t)
+	}
+	return rate
+}
+
+// ParseShape returns the trace of a named load shape, for a server with the given nominal arrival rate (requests/min):
+//   - constant: the nominal rate
+//   - diurnal: from the nominal rate divided by factor to the nominal rate, peaking in the middle of each period
+//   - step: the nominal rate, multiplied by factor after one period
+//   - bursty: the nominal rate, multiplied by factor in bursts, each tenth of a period being a burst with probability 0.2
+func ParseShape(shape string, rate, factor float64, period time.Duration, seed uint64) (LoadTrace, error) {
+	if factor <= 0 {
+		return nil, fmt.Errorf("invalid load factor %v", factor)
+	}
+	if period <= 0 && shape != "constant" {
+		return nil, fmt.Errorf("invalid load period %v", period)
+	}
+	switch shape {
+	case "constant":
+		return Constant{Value: rate}, nil
+	case "diurnal":
+		return Diurnal{Min: rate / factor, Max: rate, Period: period, PeakAt: period / 2}, nil
+	case "step":
+		return Step{Before: rate, After: rate * factor, At: period}, nil
+	case "bursty":
+		return Bursty{Base: rate, Peak: rate * factor, Burst: period / 10, Probability: 0.2, Seed: seed}, nil
+	}
+	return nil, fmt.Errorf("unknown load shape %q, expected constant, diurnal, step or bursty", shape)
+}
+
+// splitMix64 is a bijective hash of 64-bit values
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
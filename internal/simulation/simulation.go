@@ -0,0 +1,516 @@
+// Package simulation evaluates the autoscaling control loop offline: synthetic load traces drive the collection of
+// arrival rates, the optimization of allocations, a modeled actuation delay and the startup of new replicas, and the
+// performance of the servers is predicted by the queueing model. This compares autoscaling policies (stabilization,
+// rate windows, solver modes) on SLO attainment, cost and replica churn before changing the controller.
+package simulation
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/stabilizer"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/analyzer"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/planner"
+)
+
+const (
+	// default simulation step
+	DefaultStep = 10 * time.Second
+	// default interval between optimization cycles, as GLOBAL_OPT_INTERVAL
+	DefaultInterval = 60 * time.Second
+	// default window of the collected arrival rate, as in the Prometheus queries of the collector
+	DefaultRateWindow = time.Minute
+)
+
+// ramp headroom options, the defaults of the controller
+var rampConfig = stabilizer.ConfigFromData(nil)
+
+// Scenario of a simulation: the servers and the load on them, and how fast the cluster acts on recommendations
+type Scenario struct {
+	System         *config.SystemData   // servers with their initial allocations and token counts, models, service classes
+	Loads          map[string]LoadTrace // arrival rates of servers by name; others keep the arrival rate of the system data
+	Duration       time.Duration        // simulated time
+	Step           time.Duration        // resolution of the simulation, DefaultStep if zero
+	ActuationDelay time.Duration        // from a recommendation to the scaling of the Deployment (HPA or KEDA sync)
+	StartupLatency time.Duration        // from the scaling of the Deployment to new replicas being ready
+}
+
+// Policy of the autoscaler under evaluation
+type Policy struct {
+	Interval            time.Duration         // between optimization cycles, DefaultInterval if zero
+	RateWindow          time.Duration         // window over which the collected arrival rate is averaged, DefaultRateWindow if zero
+	StabilizationWindow time.Duration         // hold scale-downs while a higher recommendation was made within the window
+	RampHeadroom        bool                  // size for the arrival rate extrapolated to when new replicas are ready
+	Optimizer           *config.OptimizerSpec // solver mode, that of the system data if nil
+}
+
+// ControllerPolicy returns the policy of the controller, which holds scale-downs and adds ramp headroom over the
+// startup latency
+func ControllerPolicy(startupLatency time.Duration) *Policy {
+	return &Policy{
+		Interval:            DefaultInterval,
+		RateWindow:          DefaultRateWindow,
+		StabilizationWindow: startupLatency,
+		RampHeadroom:        true,
+	}
+}
+
+// Sample of the state of a server at a step
+type Sample struct {
+	Time         time.Duration `json:"time"`
+	Server       string        `json:"server"`
+	ArrivalRate  float64       `json:"arrivalRate"`  // req/min
+	ObservedRate float64       `json:"observedRate"` // req/min, collected at the last optimization cycle
+	Accelerator  string        `json:"accelerator"`
+	Desired      int           `json:"desired"`  // number of replicas last recommended
+	Replicas     int           `json:"replicas"` // number of replicas provisioned, ready or starting
+	Ready        int           `json:"ready"`    // number of replicas serving the load
+	TTFT         float64       `json:"ttft"`     // predicted average time to first token (msec), zero if overloaded
+	ITL          float64       `json:"itl"`      // predicted average inter-token latency (msec), zero if overloaded
+	WithinSLO    bool          `json:"withinSLO"`
+	Cost         float64       `json:"cost"` // cents/hr
+}
+
+// Summary of a simulation, for a server or the whole system
+type Summary struct {
+	SLOAttainment      float64 `json:"sloAttainment"`      // fraction of requests arriving while the server meets its SLOs
+	Cost               float64 `json:"cost"`               // cents over the simulated time
+	AvgReplicas        float64 `json:"avgReplicas"`        // time average number of replicas provisioned
+	MaxReplicas        int     `json:"maxReplicas"`        // maximum number of replicas provisioned
+	ScaleUps           int     `json:"scaleUps"`           // number of actuations adding replicas
+	ScaleDowns         int     `json:"scaleDowns"`         // number of actuations removing replicas
+	AcceleratorChanges int     `json:"acceleratorChanges"` // number of actuations replacing replicas on another accelerator
+	ReplicasStarted    int     `json:"replicasStarted"`
+	ReplicasStopped    int     `json:"replicasStopped"`
+
+	requests    float64 // requests arriving
+	served      float64 // requests arriving within SLO
+	replicaTime float64 // replica steps
+}
+
+// Result of a simulation
+type Result struct {
+	Samples      []Sample            `json:"samples"` // by time, then server name
+	Servers      map[string]*Summary `json:"servers"`
+	Total        *Summary            `json:"total"`
+	Cycles       int                 `json:"cycles"`       // number of optimization cycles
+	FailedCycles int                 `json:"failedCycles"` // number of cycles in which the optimization failed
+}
+
+// replicas on an accelerator, some of them starting
+type group struct {
+	accelerator string
+	ready       int
+	starting    []time.Duration // times at which starting replicas will be ready
+}
+
+func (g *group) replicas() int {
+	return g.ready + len(g.starting)
+}
+
+// state of a server
+type server struct {
+	spec     *config.ServerSpec
+	load     LoadTrace
+	current  *group
+	retiring *group // replicas on the previous accelerator, serving until the current ones are ready
+	desired  int
+	observed float64
+	summary  *Summary
+}
+
+// actuation of a recommendation
+type actuation struct {
+	at          time.Duration
+	server      *server
+	accelerator string
+	replicas    int
+}
+
+// Run the control loop over a scenario with a policy
+func Run(scenario *Scenario, policy *Policy) (*Result, error) {
+	if scenario == nil || scenario.System == nil || scenario.Duration <= 0 || scenario.Step < 0 ||
+		scenario.ActuationDelay < 0 || scenario.StartupLatency < 0 {
+		return nil, fmt.Errorf("invalid scenario")
+	}
+	if policy == nil || policy.Interval < 0 || policy.RateWindow < 0 || policy.StabilizationWindow < 0 {
+		return nil, fmt.Errorf("invalid policy")
+	}
+	step := defaultDuration(scenario.Step, DefaultStep)
+	interval := defaultDuration(policy.Interval, DefaultInterval)
+	rateWindow := defaultDuration(policy.RateWindow, DefaultRateWindow)
+
+	system, err := planner.Clone(scenario.System)
+	if err != nil {
+		return nil, err
+	}
+	if policy.Optimizer != nil {
+		system.Spec.Optimizer.Spec = *policy.Optimizer
+	}
+	perf := newPerformance(&system.Spec)
+
+	servers := make([]*server, 0, len(system.Spec.Servers.Spec))
+	result := &Result{Servers: make(map[string]*Summary), Total: &Summary{}}
+	for name := range scenario.Loads {
+		if !slices.ContainsFunc(system.Spec.Servers.Spec, func(s config.ServerSpec) bool { return s.Name == name }) {
+			return nil, fmt.Errorf("load of unknown server %s", name)
+		}
+	}
+	for i := range system.Spec.Servers.Spec {
+		spec := &system.Spec.Servers.Spec[i]
+		load := scenario.Loads[spec.Name]
+		if load == nil {
+			load = Constant{Value: float64(spec.CurrentAlloc.Load.ArrivalRate)}
+		}
+		s := &server{
+			spec:    spec,
+			load:    load,
+			current: &group{accelerator: spec.CurrentAlloc.Accelerator, ready: spec.CurrentAlloc.NumReplicas},
+			desired: spec.CurrentAlloc.NumReplicas,
+			summary: &Summary{},
+		}
+		servers = append(servers, s)
+		result.Servers[spec.Name] = s.summary
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].spec.Name < servers[j].spec.Name })
+
+	stab := stabilizer.NewStabilizer()
+	start := time.Unix(0, 0)
+	pending := make([]actuation, 0)
+	nextCycle := time.Duration(0)
+	for t := time.Duration(0); t < scenario.Duration; t += step {
+		// scale Deployments, then mark replicas ready
+		due := 0
+		for due < len(pending) && pending[due].at <= t {
+			pending[due].apply(t, scenario.StartupLatency)
+			due++
+		}
+		pending = pending[due:]
+		for _, s := range servers {
+			s.startReplicas(t)
+		}
+
+		if t >= nextCycle {
+			nextCycle += interval
+			result.Cycles++
+			for _, s := range servers {
+				s.observed = averageRate(s.load, t, rateWindow, step)
+			}
+			recommended, err := optimize(system, servers, perf, stab, policy, scenario.StartupLatency, start.Add(t))
+			if err != nil {
+				result.FailedCycles++
+			}
+			for _, a := range recommended {
+				a.at = t + scenario.ActuationDelay
+				pending = append(pending, a)
+			}
+		}
+
+		for _, s := range servers {
+			result.Samples = append(result.Samples, s.sample(t, step, perf))
+		}
+	}
+
+	for _, s := range servers {
+		s.summary.finish(scenario.Duration, step)
+		result.Total.add(s.summary)
+	}
+	result.Total.finish(scenario.Duration, step)
+	result.Total.MaxReplicas = maxTotalReplicas(result.Samples)
+	return result, nil
+}
+
+// optimize runs an optimization cycle on the observed arrival rates and current allocations, returning the
+// recommendations to actuate
+func optimize(system *config.SystemData, servers []*server, perf *performance, stab *stabilizer.Stabilizer,
+	policy *Policy, startupLatency time.Duration, now time.Time) ([]actuation, error) {
+
+	data, err := planner.Clone(system)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*server, len(servers))
+	for _, s := range servers {
+		byName[s.spec.Name] = s
+	}
+	for i := range data.Spec.Servers.Spec {
+		spec := &data.Spec.Servers.Spec[i]
+		s := byName[spec.Name]
+		rate := s.observed
+		if policy.RampHeadroom {
+			rate = stab.RampHeadroom(spec.Name, rate, startupLatency, now, rampConfig)
+		}
+		spec.CurrentAlloc.Accelerator = s.current.accelerator
+		spec.CurrentAlloc.NumReplicas = s.current.replicas()
+		spec.CurrentAlloc.Cost = float32(float64(s.current.replicas()) * perf.replicaCost(spec, s.current.accelerator))
+		spec.CurrentAlloc.Load.ArrivalRate = float32(rate)
+	}
+	optimized, err := planner.Optimize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	recommended := make([]actuation, 0, len(servers))
+	for _, s := range servers {
+		alloc, ok := optimized.Solution.Spec[s.spec.Name]
+		if !ok {
+			continue
+		}
+		replicas := stab.Stabilize(s.spec.Name, alloc.NumReplicas, policy.StabilizationWindow, now)
+		s.desired = replicas
+		recommended = append(recommended, actuation{server: s, accelerator: alloc.Accelerator, replicas: replicas})
+	}
+	return recommended, nil
+}
+
+// apply scales the Deployment of a server: replicas on another accelerator replace the current ones once ready
+func (a *actuation) apply(t, startupLatency time.Duration) {
+	s := a.server
+	current := s.current
+	if a.accelerator != current.accelerator && current.replicas() > 0 {
+		if s.retiring == nil && current.ready > 0 {
+			// the ready replicas serve until the new ones are ready, the starting ones are stopped
+			s.summary.ReplicasStopped += len(current.starting)
+			current.starting = nil
+			s.retiring = current
+		} else {
+			// replaced again before ready
+			s.summary.ReplicasStopped += current.replicas()
+		}
+		s.summary.AcceleratorChanges++
+		s.current = &group{accelerator: a.accelerator}
+		s.current.start(a.replicas, t+startupLatency)
+		s.summary.ReplicasStarted += a.replicas
+		return
+	}
+	current.accelerator = a.accelerator
+	switch delta := a.replicas - current.replicas(); {
+	case delta > 0:
+		current.start(delta, t+startupLatency)
+		s.summary.ScaleUps++
+		s.summary.ReplicasStarted += delta
+	case delta < 0:
+		current.stop(-delta)
+		s.summary.ScaleDowns++
+		s.summary.ReplicasStopped += -delta
+	}
+}
+
+func (g *group) start(n int, readyAt time.Duration) {
+	for range n {
+		g.starting = append(g.starting, readyAt)
+	}
+}
+
+// stop removes replicas, those starting last first
+func (g *group) stop(n int) {
+	numStarting := min(n, len(g.starting))
+	g.starting = g.starting[:len(g.starting)-numStarting]
+	g.ready -= n - numStarting
+}
+
+// startReplicas marks the replicas started by a time as ready, and stops the retiring replicas once all the
+// current ones are ready
+func (s *server) startReplicas(t time.Duration) {
+	g := s.current
+	starting := g.starting[:0]
+	for _, readyAt := range g.starting {
+		if readyAt <= t {
+			g.ready++
+		} else {
+			starting = append(starting, readyAt)
+		}
+	}
+	g.starting = starting
+	if s.retiring != nil && len(g.starting) == 0 {
+		s.summary.ReplicasStopped += s.retiring.replicas()
+		s.retiring = nil
+	}
+}
+
+// sample evaluates a server over a step, updating its summary
+func (s *server) sample(t, step time.Duration, perf *performance) Sample {
+	serving := s.current
+	if s.retiring != nil {
+		serving = s.retiring
+	}
+	rate := s.load.Rate(t)
+	sample := Sample{
+		Time:         t,
+		Server:       s.spec.Name,
+		ArrivalRate:  rate,
+		ObservedRate: s.observed,
+		Accelerator:  serving.accelerator,
+		Desired:      s.desired,
+		Replicas:     s.current.replicas(),
+		Ready:        serving.ready,
+		WithinSLO:    true,
+	}
+	sample.Cost = float64(s.current.replicas()) * perf.replicaCost(s.spec, s.current.accelerator)
+	if s.retiring != nil {
+		sample.Replicas += s.retiring.replicas()
+		sample.Cost += float64(s.retiring.replicas()) * perf.replicaCost(s.spec, s.retiring.accelerator)
+	}
+	if rate > 0 {
+		sample.TTFT, sample.ITL, sample.WithinSLO = perf.evaluate(s.spec, serving.accelerator, serving.ready, rate)
+	}
+
+	minutes := step.Minutes()
+	summary := s.summary
+	summary.requests += rate * minutes
+	if sample.WithinSLO {
+		summary.served += rate * minutes
+	}
+	summary.Cost += sample.Cost * step.Hours()
+	summary.replicaTime += float64(sample.Replicas)
+	summary.MaxReplicas = max(summary.MaxReplicas, sample.Replicas)
+	return sample
+}
+
+// finish computes the averages of a summary
+func (m *Summary) finish(duration, step time.Duration) {
+	m.SLOAttainment = 1
+	if m.requests > 0 {
+		m.SLOAttainment = m.served / m.requests
+	}
+	if numSteps := float64((duration + step - 1) / step); numSteps > 0 {
+		m.AvgReplicas = m.replicaTime / numSteps
+	}
+}
+
+// add the totals of a summary to another
+func (m *Summary) add(other *Summary) {
+	m.Cost += other.Cost
+	m.ScaleUps += other.ScaleUps
+	m.ScaleDowns += other.ScaleDowns
+	m.AcceleratorChanges += other.AcceleratorChanges
+	m.ReplicasStarted += other.ReplicasStarted
+	m.ReplicasStopped += other.ReplicasStopped
+	m.requests += other.requests
+	m.served += other.served
+	m.replicaTime += other.replicaTime
+}
+
+// maxTotalReplicas returns the maximum over time of the number of replicas of all servers
+func maxTotalReplicas(samples []Sample) int {
+	totals := make(map[time.Duration]int)
+	maxTotal := 0
+	for _, sample := range samples {
+		totals[sample.Time] += sample.Replicas
+		maxTotal = max(maxTotal, totals[sample.Time])
+	}
+	return maxTotal
+}
+
+// averageRate returns the average arrival rate over the window ending at a time, as collected from Prometheus;
+// the rate at a step holds until the next step
+func averageRate(load LoadTrace, t, window, step time.Duration) float64 {
+	numSamples := max(int(window/step), 1)
+	var sum float64
+	for i := 1; i <= numSamples; i++ {
+		sum += load.Rate(max(t-time.Duration(i)*step, 0))
+	}
+	return sum / float64(numSamples)
+}
+
+func defaultDuration(d, defaultValue time.Duration) time.Duration {
+	if d == 0 {
+		return defaultValue
+	}
+	return d
+}
+
+// performance of servers, predicted by the queueing model as in the optimizer
+type performance struct {
+	spec      *config.SystemSpec
+	analyzers map[string]*analyzer.QueueAnalyzer // by server and accelerator
+}
+
+func newPerformance(spec *config.SystemSpec) *performance {
+	return &performance{spec: spec, analyzers: make(map[string]*analyzer.QueueAnalyzer)}
+}
+
+func (p *performance) perfData(model, accelerator string) *config.ModelAcceleratorPerfData {
+	i := slices.IndexFunc(p.spec.Models.PerfData, func(d config.ModelAcceleratorPerfData) bool {
+		return d.Name == model && d.Acc == accelerator
+	})
+	if i < 0 {
+		return nil
+	}
+	return &p.spec.Models.PerfData[i]
+}
+
+// replicaCost returns the cost of a replica of a server on an accelerator (cents/hr)
+func (p *performance) replicaCost(server *config.ServerSpec, accelerator string) float64 {
+	i := slices.IndexFunc(p.spec.Accelerators.Spec, func(a config.AcceleratorSpec) bool { return a.Name == accelerator })
+	if i < 0 {
+		return 0
+	}
+	count := 1
+	if perf := p.perfData(server.Model, accelerator); perf != nil && perf.AccCount > 0 {
+		count = perf.AccCount
+	}
+	return float64(p.spec.Accelerators.Spec[i].Cost) * float64(count)
+}
+
+// queueAnalyzer returns the queueing model of a replica of a server on an accelerator, configured as in the
+// optimizer; nil if there is no performance data
+func (p *performance) queueAnalyzer(server *config.ServerSpec, accelerator string) *analyzer.QueueAnalyzer {
+	key := server.Name + "/" + accelerator
+	if qa, ok := p.analyzers[key]; ok {
+		return qa
+	}
+	var qa *analyzer.QueueAnalyzer
+	load := server.CurrentAlloc.Load
+	if perf := p.perfData(server.Model, accelerator); perf != nil && load.AvgOutTokens > 0 {
+		batchSize := server.MaxBatchSize
+		if n := server.AcceleratorMaxBatchSize[accelerator]; n > 0 {
+			batchSize = n
+		}
+		if batchSize <= 0 {
+			batchSize = max(perf.MaxBatchSize*perf.AtTokens/load.AvgOutTokens, 1)
+		}
+		qa, _ = analyzer.NewQueueAnalyzer(&analyzer.Configuration{
+			MaxBatchSize: batchSize,
+			MaxQueueSize: batchSize * config.MaxQueueToBatchRatio,
+			ServiceParms: &analyzer.ServiceParms{
+				Prefill: &analyzer.PrefillParms{Gamma: perf.PrefillParms.Gamma, Delta: perf.PrefillParms.Delta},
+				Decode:  &analyzer.DecodeParms{Alpha: perf.DecodeParms.Alpha, Beta: perf.DecodeParms.Beta},
+			},
+		}, &analyzer.RequestSize{AvgInputTokens: load.AvgInTokens, AvgOutputTokens: load.AvgOutTokens})
+	}
+	p.analyzers[key] = qa
+	return qa
+}
+
+// evaluate predicts the latencies of a server with ready replicas on an accelerator under an arrival rate
+// (req/min), and whether they meet the SLOs of its service class; overloaded servers miss their SLOs
+func (p *performance) evaluate(server *config.ServerSpec, accelerator string, ready int, rate float64) (ttft, itl float64, withinSLO bool) {
+	qa := p.queueAnalyzer(server, accelerator)
+	if qa == nil || ready <= 0 {
+		return 0, 0, false
+	}
+	metrics, err := qa.Analyze(float32(rate / 60 / float64(ready)))
+	if err != nil {
+		return 0, 0, false
+	}
+	ttft = float64(metrics.AvgWaitTime + metrics.AvgPrefillTime)
+	itl = float64(metrics.AvgTokenTime)
+	for _, class := range p.spec.ServiceClasses.Spec {
+		if class.Name != server.Class {
+			continue
+		}
+		for _, target := range class.ModelTargets {
+			if target.Model != server.Model {
+				continue
+			}
+			if target.SLO_TTFT > 0 && ttft > float64(target.SLO_TTFT) || target.SLO_ITL > 0 && itl > float64(target.SLO_ITL) {
+				return ttft, itl, false
+			}
+		}
+	}
+	return ttft, itl, true
+}
@@ -0,0 +1,278 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+)
+
+const testServer = "chat:ns"
+
+// a server of llama-8b on an accelerator, which may change if not A100
+func testSystem(rate float32, replicas int, accelerator string) *config.SystemData {
+	return &config.SystemData{Spec: config.SystemSpec{
+		Accelerators: config.AcceleratorData{Spec: []config.AcceleratorSpec{
+			{Name: "A100", Type: "NVIDIA-A100-PCIE-80GB", Multiplicity: 1, Cost: 40},
+			{Name: "G2", Type: "Intel-Gaudi-2-96GB", Multiplicity: 1, Cost: 23},
+		}},
+		Models: config.ModelData{PerfData: []config.ModelAcceleratorPerfData{
+			{Name: "llama-8b", Acc: "A100", AccCount: 1, MaxBatchSize: 48, AtTokens: 512,
+				DecodeParms:  config.DecodeParms{Alpha: 9.8, Beta: 0.18},
+				PrefillParms: config.PrefillParms{Gamma: 2.9, Delta: 0.04}},
+			{Name: "llama-8b", Acc: "G2", AccCount: 1, MaxBatchSize: 32, AtTokens: 512,
+				DecodeParms:  config.DecodeParms{Alpha: 14.2, Beta: 0.3},
+				PrefillParms: config.PrefillParms{Gamma: 4.4, Delta: 0.07}},
+		}},
+		ServiceClasses: config.ServiceClassData{Spec: []config.ServiceClassSpec{
+			{Name: "premium", Priority: 1, ModelTargets: []config.ModelTarget{{Model: "llama-8b", SLO_ITL: 24, SLO_TTFT: 500}}},
+		}},
+		Servers: config.ServerData{Spec: []config.ServerSpec{{
+			Name: testServer, Namespace: "ns", Class: "premium", Model: "llama-8b", MinNumReplicas: 1,
+			KeepAccelerator: accelerator == "A100",
+			CurrentAlloc: config.AllocationData{Accelerator: accelerator, NumReplicas: replicas,
+				Load: config.ServerLoadSpec{ArrivalRate: rate, AvgInTokens: 256, AvgOutTokens: 128}},
+		}}},
+		Optimizer: config.OptimizerData{Spec: config.OptimizerSpec{Unlimited: true}},
+	}}
+}
+
+func TestLoadTraces(t *testing.T) {
+	diurnal := Diurnal{Min: 100, Max: 300, Period: 24 * time.Hour, PeakAt: 14 * time.Hour}
+	step := Step{Before: 100, After: 200, At: time.Hour}
+	tests := []struct {
+		name  string
+		trace LoadTrace
+		at    time.Duration
+		want  float64
+	}{
+		{"constant", Constant{Value: 100}, time.Hour, 100},
+		{"diurnal peak", diurnal, 14 * time.Hour, 300},
+		{"diurnal trough", diurnal, 2 * time.Hour, 100},
+		{"diurnal next peak", diurnal, 38 * time.Hour, 300},
+		{"diurnal midway", diurnal, 8 * time.Hour, 200},
+		{"before step", step, time.Hour - time.Second, 100},
+		{"after step", step, time.Hour, 200},
+		{"sum", Sum{Constant{Value: 100}, step}, 2 * time.Hour, 300},
+	}
+	for _, tt := range tests {
+		if got := tt.trace.Rate(tt.at); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Rate(%v) = %v, want %v", tt.name, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestBursty(t *testing.T) {
+	bursty := Bursty{Base: 100, Peak: 500, Burst: time.Minute, Probability: 0.2, Seed: 7}
+	bursts := 0
+	const numPeriods = 10000
+	for i := range numPeriods {
+		start := time.Duration(i) * time.Minute
+		rate := bursty.Rate(start)
+		if rate != 100 && rate != 500 {
+			t.Fatalf("Rate(%v) = %v, want base or peak", start, rate)
+		}
+		// constant over a burst period, and the same for the same seed
+		if end := start + time.Minute - time.Second; bursty.Rate(end) != rate || bursty.Rate(start) != rate {
+			t.Fatalf("Rate changed within period %d", i)
+		}
+		if rate == 500 {
+			bursts++
+		}
+	}
+	if fraction := float64(bursts) / numPeriods; math.Abs(fraction-0.2) > 0.02 {
+		t.Errorf("Expected bursts in 20%% of periods, got %v", fraction)
+	}
+	other := bursty
+	other.Seed = 8
+	same := 0
+	for i := range 100 {
+		if other.Rate(time.Duration(i)*time.Minute) == bursty.Rate(time.Duration(i)*time.Minute) {
+			same++
+		}
+	}
+	if same == 100 {
+		t.Error("Expected different bursts with another seed")
+	}
+}
+
+func TestParseShape(t *testing.T) {
+	for _, shape := range []string{"constant", "diurnal", "step", "bursty"} {
+		trace, err := ParseShape(shape, 100, 2, time.Hour, 1)
+		if err != nil {
+			t.Fatalf("ParseShape(%s) error = %v", shape, err)
+		}
+		for _, at := range []time.Duration{0, 30 * time.Minute, 90 * time.Minute} {
+			if rate := trace.Rate(at); rate < 50 || rate > 200 {
+				t.Errorf("%s: Rate(%v) = %v, want within [50, 200]", shape, at, rate)
+			}
+		}
+	}
+	if trace, _ := ParseShape("step", 100, 2, time.Hour, 1); trace.Rate(time.Hour) != 200 {
+		t.Errorf("Expected step to twice the rate after one period")
+	}
+	for _, args := range []struct {
+		shape  string
+		factor float64
+		period time.Duration
+	}{
+		{"sawtooth", 2, time.Hour},
+		{"diurnal", 0, time.Hour},
+		{"diurnal", 2, 0},
+	} {
+		if _, err := ParseShape(args.shape, 100, args.factor, args.period, 1); err == nil {
+			t.Errorf("ParseShape(%v) expected error", args)
+		}
+	}
+}
+
+func TestRunSteadyLoad(t *testing.T) {
+	result, err := Run(&Scenario{System: testSystem(600, 1, "A100"), Duration: 30 * time.Minute}, &Policy{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Cycles != 30 || result.FailedCycles != 0 || len(result.Samples) != 180 {
+		t.Fatalf("Expected 30 cycles and 180 samples, got %d cycles (%d failed) and %d samples",
+			result.Cycles, result.FailedCycles, len(result.Samples))
+	}
+	total := result.Total
+	if total.SLOAttainment != 1 || total.ScaleUps+total.ScaleDowns+total.AcceleratorChanges != 0 || total.AvgReplicas != 1 {
+		t.Errorf("Expected one replica meeting SLOs without scaling, got %+v", total)
+	}
+	// one A100 for half an hour
+	if math.Abs(total.Cost-20) > 1e-9 {
+		t.Errorf("Expected cost of 20 cents, got %v", total.Cost)
+	}
+	if *result.Servers[testServer] != *total {
+		t.Errorf("Expected the total of the only server, got %+v and %+v", result.Servers[testServer], total)
+	}
+	sample := result.Samples[len(result.Samples)-1]
+	if !sample.WithinSLO || sample.TTFT <= 0 || sample.TTFT > 500 || sample.ITL <= 0 || sample.ITL > 24 {
+		t.Errorf("Expected predicted latencies within SLOs, got %+v", sample)
+	}
+}
+
+func TestRunStartupLatency(t *testing.T) {
+	load := map[string]LoadTrace{testServer: Step{Before: 600, After: 2400, At: 10 * time.Minute}}
+	attainment := make([]float64, 0)
+	for _, startupLatency := range []time.Duration{0, 2 * time.Minute, 10 * time.Minute} {
+		result, err := Run(&Scenario{
+			System:         testSystem(600, 1, "A100"),
+			Loads:          load,
+			Duration:       40 * time.Minute,
+			ActuationDelay: 30 * time.Second,
+			StartupLatency: startupLatency,
+		}, &Policy{})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		total := result.Total
+		if total.ScaleUps == 0 || total.ReplicasStarted != total.MaxReplicas-1 || total.ScaleDowns != 0 {
+			t.Errorf("startup %v: expected scale-ups only, got %+v", startupLatency, total)
+		}
+		last := result.Samples[len(result.Samples)-1]
+		if !last.WithinSLO || last.Ready != total.MaxReplicas {
+			t.Errorf("startup %v: expected all replicas ready and meeting SLOs in the end, got %+v", startupLatency, last)
+		}
+		attainment = append(attainment, total.SLOAttainment)
+	}
+	if !(attainment[0] > attainment[1] && attainment[1] > attainment[2]) {
+		t.Errorf("Expected SLO attainment to decrease with startup latency, got %v", attainment)
+	}
+}
+
+func TestRunRateWindow(t *testing.T) {
+	scenario := &Scenario{
+		System:         testSystem(600, 1, "A100"),
+		Loads:          map[string]LoadTrace{testServer: Step{Before: 600, After: 2400, At: 10 * time.Minute}},
+		Duration:       30 * time.Minute,
+		StartupLatency: time.Minute,
+	}
+	short, err := Run(scenario, &Policy{RateWindow: time.Minute})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	long, err := Run(scenario, &Policy{RateWindow: 10 * time.Minute})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	// a longer window observes the step later, scaling in more steps
+	if long.Total.SLOAttainment >= short.Total.SLOAttainment || long.Total.ScaleUps <= short.Total.ScaleUps {
+		t.Errorf("Expected later and more scale-ups with a longer rate window, got %+v and %+v", short.Total, long.Total)
+	}
+	at := func(result *Result, t time.Duration) Sample {
+		return result.Samples[int(t/DefaultStep)]
+	}
+	if observed := at(long, 15*time.Minute).ObservedRate; math.Abs(observed-1500) > 1e-9 {
+		t.Errorf("Expected observed rate averaged over 10 minutes, got %v", observed)
+	}
+}
+
+func TestRunStabilization(t *testing.T) {
+	scenario := &Scenario{
+		System: testSystem(600, 1, "A100"),
+		Loads: map[string]LoadTrace{
+			testServer: Bursty{Base: 600, Peak: 2400, Burst: 2 * time.Minute, Probability: 0.3, Seed: 1},
+		},
+		Duration:       2 * time.Hour,
+		StartupLatency: 2 * time.Minute,
+	}
+	unstabilized, err := Run(scenario, &Policy{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	stabilized, err := Run(scenario, &Policy{StabilizationWindow: 10 * time.Minute})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	// holding scale-downs trades cost for fewer replica restarts and better SLO attainment under bursts
+	if stabilized.Total.ReplicasStarted >= unstabilized.Total.ReplicasStarted ||
+		stabilized.Total.ScaleDowns >= unstabilized.Total.ScaleDowns ||
+		stabilized.Total.SLOAttainment <= unstabilized.Total.SLOAttainment ||
+		stabilized.Total.Cost <= unstabilized.Total.Cost {
+		t.Errorf("Expected less churn and higher attainment at a higher cost with stabilization, got %+v and %+v",
+			unstabilized.Total, stabilized.Total)
+	}
+}
+
+func TestRunAcceleratorChange(t *testing.T) {
+	system := testSystem(600, 1, "A100")
+	system.Spec.Servers.Spec[0].KeepAccelerator = false
+	system.Spec.Accelerators.Spec[0].Cost = 400
+	result, err := Run(&Scenario{System: system, Duration: 20 * time.Minute, StartupLatency: 5 * time.Minute},
+		&Policy{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	total := result.Total
+	if total.AcceleratorChanges != 1 || total.ReplicasStopped != 1 || total.SLOAttainment != 1 {
+		t.Fatalf("Expected the A100 replica replaced without missing SLOs, got %+v", total)
+	}
+	// the A100 replica serves until the G2 replicas are ready
+	during := result.Samples[int(4*time.Minute/DefaultStep)]
+	after := result.Samples[len(result.Samples)-1]
+	if during.Accelerator != "A100" || during.Ready != 1 || during.Replicas != 1+total.ReplicasStarted {
+		t.Errorf("Expected the A100 replica serving while G2 replicas start, got %+v", during)
+	}
+	if after.Accelerator != "G2" || after.Ready != total.ReplicasStarted || after.Cost >= 400 {
+		t.Errorf("Expected G2 replicas serving in the end, got %+v", after)
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	if _, err := Run(&Scenario{System: testSystem(600, 1, "A100")}, &Policy{}); err == nil {
+		t.Error("Expected error for no duration")
+	}
+	if _, err := Run(&Scenario{System: testSystem(600, 1, "A100"), Duration: time.Hour}, nil); err == nil {
+		t.Error("Expected error for no policy")
+	}
+	scenario := &Scenario{
+		System:   testSystem(600, 1, "A100"),
+		Loads:    map[string]LoadTrace{"other:ns": Constant{Value: 1}},
+		Duration: time.Hour,
+	}
+	if _, err := Run(scenario, &Policy{}); err == nil {
+		t.Error("Expected error for load of unknown server")
+	}
+}
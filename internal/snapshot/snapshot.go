@@ -0,0 +1,138 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	infernoConfig "github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+)
+
+// ConfigMap key for snapshot options
+const (
+	KeySnapshotConfigMap = "WVA_SNAPSHOT_CONFIGMAP"
+)
+
+// Paths of the debug endpoints serving the last snapshot on the metrics server
+const (
+	SystemPath   = "/debug/snapshot/system"
+	SolutionPath = "/debug/snapshot/solution"
+)
+
+// Keys of the snapshot in the ConfigMap it is written to
+const (
+	KeySystem   = "system.json"
+	KeySolution = "solution.json"
+	KeyTime     = "time"
+)
+
+// Config holds the snapshot options
+type Config struct {
+	ConfigMapName string // ConfigMap, in the configuration namespace, to write the snapshots to; none if empty
+}
+
+// ConfigFromData parses snapshot options from the optimization ConfigMap data
+func ConfigFromData(data map[string]string) Config {
+	return Config{
+		ConfigMapName: strings.TrimSpace(data[KeySnapshotConfigMap]),
+	}
+}
+
+// Snapshot of an optimization cycle: the system data input to the optimizer, in the format read by the offline
+// planner, and the allocation solution output, both in JSON
+type Snapshot struct {
+	Time     time.Time
+	System   []byte
+	Solution []byte // nil if the optimization failed
+}
+
+// Data returns the snapshot as ConfigMap data
+func (s *Snapshot) Data() map[string]string {
+	data := map[string]string{
+		KeyTime:   s.Time.UTC().Format(time.RFC3339),
+		KeySystem: string(s.System),
+	}
+	if s.Solution != nil {
+		data[KeySolution] = string(s.Solution)
+	}
+	return data
+}
+
+// Store keeps the snapshot of the last optimization cycle and serves it over HTTP
+type Store struct {
+	mu   sync.RWMutex
+	last *Snapshot
+}
+
+// NewStore creates an empty snapshot store
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Record the system data and allocation solution of an optimization cycle, replacing the previous snapshot; the
+// data are serialized right away, so they may be modified afterwards. The solution is nil if the optimization failed.
+func (s *Store) Record(t time.Time, system *infernoConfig.SystemData, solution *infernoConfig.AllocationSolution) (*Snapshot, error) {
+	snapshot := &Snapshot{Time: t}
+	var err error
+	if snapshot.System, err = json.MarshalIndent(system, "", "  "); err != nil {
+		return nil, err
+	}
+	if solution != nil {
+		if snapshot.Solution, err = json.MarshalIndent(solution, "", "  "); err != nil {
+			return nil, err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last = snapshot
+	return snapshot, nil
+}
+
+// Last returns the snapshot of the last optimization cycle; nil if none was recorded
+func (s *Store) Last() *Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last
+}
+
+// Handlers returns the debug endpoints serving the system data and the allocation solution of the last snapshot,
+// by path
+func (s *Store) Handlers() map[string]http.Handler {
+	return map[string]http.Handler{
+		SystemPath: s.handler(func(snapshot *Snapshot) []byte {
+			return snapshot.System
+		}),
+		SolutionPath: s.handler(func(snapshot *Snapshot) []byte {
+			return snapshot.Solution
+		}),
+	}
+}
+
+// handler serves a part of the last snapshot, with the time of its cycle as the last modification time
+func (s *Store) handler(part func(*Snapshot) []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		snapshot := s.Last()
+		if snapshot == nil {
+			http.Error(w, "no optimization cycle recorded", http.StatusNotFound)
+			return
+		}
+		body := part(snapshot)
+		if body == nil {
+			http.Error(w, "no allocation solution in the last optimization cycle", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Last-Modified", snapshot.Time.UTC().Format(http.TimeFormat))
+		if _, err := w.Write(body); err != nil {
+			logger.Log.Debug("failed to write snapshot response", "path", req.URL.Path, "error", err)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	infernoConfig "github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/planner"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.Log = zap.NewNop().Sugar()
+}
+
+func testSystemData() *infernoConfig.SystemData {
+	return &infernoConfig.SystemData{Spec: infernoConfig.SystemSpec{
+		Accelerators: infernoConfig.AcceleratorData{Spec: []infernoConfig.AcceleratorSpec{
+			{Name: "A100", Type: "A100", Multiplicity: 1, Cost: 40},
+		}},
+		Servers: infernoConfig.ServerData{Spec: []infernoConfig.ServerSpec{
+			{Name: "chat:team-a", Model: "granite-13b", CurrentAlloc: infernoConfig.AllocationData{
+				Accelerator: "A100", NumReplicas: 2, Load: infernoConfig.ServerLoadSpec{ArrivalRate: 120},
+			}},
+		}},
+		Optimizer: infernoConfig.OptimizerData{Spec: infernoConfig.OptimizerSpec{Unlimited: true}},
+	}}
+}
+
+func TestConfigFromData(t *testing.T) {
+	if cfg := ConfigFromData(map[string]string{}); cfg.ConfigMapName != "" {
+		t.Errorf("Expected no ConfigMap by default, got %q", cfg.ConfigMapName)
+	}
+	if cfg := ConfigFromData(map[string]string{KeySnapshotConfigMap: " wva-snapshot "}); cfg.ConfigMapName != "wva-snapshot" {
+		t.Errorf("Expected ConfigMap wva-snapshot, got %q", cfg.ConfigMapName)
+	}
+}
+
+func TestStore_Record(t *testing.T) {
+	store := NewStore()
+	if store.Last() != nil {
+		t.Fatal("Expected no snapshot in a new store")
+	}
+
+	data := testSystemData()
+	solution := &infernoConfig.AllocationSolution{Spec: map[string]infernoConfig.AllocationData{
+		"chat:team-a": {Accelerator: "A100", NumReplicas: 3},
+	}}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	snap, err := store.Record(now, data, solution)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if store.Last() != snap {
+		t.Error("Expected the recorded snapshot to be the last")
+	}
+
+	// the snapshot is not affected by later changes to the data
+	data.Spec.Servers.Spec[0].CurrentAlloc.Load.ArrivalRate = 0
+	read, err := planner.ReadSystemData(bytes.NewReader(snap.System))
+	if err != nil {
+		t.Fatalf("Expected system data readable by the planner, got error %v", err)
+	}
+	if rate := read.Spec.Servers.Spec[0].CurrentAlloc.Load.ArrivalRate; rate != 120 {
+		t.Errorf("Expected arrival rate 120 in the snapshot, got %v", rate)
+	}
+
+	cmData := snap.Data()
+	if cmData[KeyTime] != "2025-06-01T12:00:00Z" || cmData[KeySystem] == "" || cmData[KeySolution] == "" {
+		t.Errorf("Unexpected ConfigMap data %v", cmData)
+	}
+
+	// a failed optimization has no solution
+	snap, err = store.Record(now.Add(time.Minute), data, nil)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if snap.Solution != nil {
+		t.Errorf("Expected no solution, got %s", snap.Solution)
+	}
+	if _, ok := snap.Data()[KeySolution]; ok {
+		t.Error("Expected no solution in the ConfigMap data")
+	}
+}
+
+func TestStore_Handlers(t *testing.T) {
+	store := NewStore()
+	handlers := store.Handlers()
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handlers[path].ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	if rec := serve(http.MethodGet, SystemPath); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected %d before any cycle, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	if _, err := store.Record(now, testSystemData(), nil); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	rec := serve(http.MethodGet, SystemPath)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected JSON content, got %q", got)
+	}
+	if got := rec.Header().Get("Last-Modified"); got != now.Format(http.TimeFormat) {
+		t.Errorf("Expected last modification at the cycle time, got %q", got)
+	}
+	if _, err := planner.ReadSystemData(rec.Body); err != nil {
+		t.Errorf("Expected system data readable by the planner, got error %v", err)
+	}
+	if rec := serve(http.MethodGet, SolutionPath); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected %d without a solution, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	solution := &infernoConfig.AllocationSolution{Spec: map[string]infernoConfig.AllocationData{
+		"chat:team-a": {Accelerator: "A100", NumReplicas: 3},
+	}}
+	if _, err := store.Record(now, testSystemData(), solution); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	rec = serve(http.MethodGet, SolutionPath)
+	got := &infernoConfig.AllocationSolution{}
+	if err := json.NewDecoder(rec.Body).Decode(got); err != nil {
+		t.Fatalf("Failed to decode solution: %v", err)
+	}
+	if got.Spec["chat:team-a"].NumReplicas != 3 {
+		t.Errorf("Expected the recorded solution, got %v", got)
+	}
+
+	if rec := serve(http.MethodPost, SystemPath); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected %d for POST, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
@@ -0,0 +1,75 @@
+package stabilizer
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+)
+
+// ConfigMap keys for startup latency and ramp headroom options
+const (
+	KeyMaxStartupLatency    = "WVA_MAX_STARTUP_LATENCY"
+	KeyStartupLatencyWindow = "WVA_STARTUP_LATENCY_WINDOW"
+	KeyRampSlopeWindow      = "WVA_RAMP_SLOPE_WINDOW"
+	KeyMaxRampHeadroom      = "WVA_MAX_RAMP_HEADROOM"
+)
+
+// default startup latency and ramp headroom options
+const (
+	DefaultMaxStartupLatency    = 30 * time.Minute
+	DefaultStartupLatencyWindow = 24 * time.Hour
+	DefaultRampSlopeWindow      = time.Minute
+	DefaultMaxRampHeadroom      = 2.0
+)
+
+// Config holds the startup latency and ramp headroom options
+type Config struct {
+	MaxStartupLatency    time.Duration // upper bound of the startup latency learned from pod readiness
+	StartupLatencyWindow time.Duration // age beyond which pods are ignored when learning the startup latency
+	RampSlopeWindow      time.Duration // minimum time over which the slope of the arrival rate is measured
+	MaxRampHeadroom      float64       // upper bound of the arrival rate sized for during a ramp, as a multiple of the observed rate
+}
+
+// ConfigFromData parses startup latency and ramp headroom options from the optimization ConfigMap data, using defaults for missing or bad values
+func ConfigFromData(data map[string]string) Config {
+	cfg := Config{
+		MaxStartupLatency:    DefaultMaxStartupLatency,
+		StartupLatencyWindow: DefaultStartupLatencyWindow,
+		RampSlopeWindow:      DefaultRampSlopeWindow,
+		MaxRampHeadroom:      DefaultMaxRampHeadroom,
+	}
+	if val, ok := data[KeyMaxStartupLatency]; ok && val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			cfg.MaxStartupLatency = d
+		} else {
+			logger.Log.Warn("invalid max startup latency, using default", "key", KeyMaxStartupLatency, "value", val,
+				"default", DefaultMaxStartupLatency)
+		}
+	}
+	if val, ok := data[KeyStartupLatencyWindow]; ok && val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			cfg.StartupLatencyWindow = d
+		} else {
+			logger.Log.Warn("invalid startup latency window, using default", "key", KeyStartupLatencyWindow, "value", val,
+				"default", DefaultStartupLatencyWindow)
+		}
+	}
+	if val, ok := data[KeyRampSlopeWindow]; ok && val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			cfg.RampSlopeWindow = d
+		} else {
+			logger.Log.Warn("invalid ramp slope window, using default", "key", KeyRampSlopeWindow, "value", val,
+				"default", DefaultRampSlopeWindow)
+		}
+	}
+	if val, ok := data[KeyMaxRampHeadroom]; ok && val != "" {
+		if f, err := strconv.ParseFloat(val, 64); err == nil && f >= 1 {
+			cfg.MaxRampHeadroom = f
+		} else {
+			logger.Log.Warn("invalid max ramp headroom, using default", "key", KeyMaxRampHeadroom, "value", val,
+				"default", DefaultMaxRampHeadroom)
+		}
+	}
+	return cfg
+}
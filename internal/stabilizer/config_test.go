@@ -0,0 +1,49 @@
+package stabilizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.Log = zap.NewNop().Sugar()
+}
+
+func TestConfigFromData(t *testing.T) {
+	defaults := Config{
+		MaxStartupLatency:    DefaultMaxStartupLatency,
+		StartupLatencyWindow: DefaultStartupLatencyWindow,
+		RampSlopeWindow:      DefaultRampSlopeWindow,
+		MaxRampHeadroom:      DefaultMaxRampHeadroom,
+	}
+	tests := []struct {
+		name string
+		data map[string]string
+		want Config
+	}{
+		{name: "defaults", data: map[string]string{}, want: defaults},
+		{
+			name: "configured",
+			data: map[string]string{KeyMaxStartupLatency: "10m", KeyStartupLatencyWindow: "6h",
+				KeyRampSlopeWindow: "2m", KeyMaxRampHeadroom: "1.5"},
+			want: Config{MaxStartupLatency: 10 * time.Minute, StartupLatencyWindow: 6 * time.Hour,
+				RampSlopeWindow: 2 * time.Minute, MaxRampHeadroom: 1.5},
+		},
+		{
+			name: "invalid",
+			data: map[string]string{KeyMaxStartupLatency: "0s", KeyStartupLatencyWindow: "x",
+				KeyRampSlopeWindow: "-1m", KeyMaxRampHeadroom: "0.5"},
+			want: defaults,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConfigFromData(tt.data); got != tt.want {
+				t.Errorf("ConfigFromData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
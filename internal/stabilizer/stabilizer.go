@@ -0,0 +1,94 @@
+// Package stabilizer adjusts scaling decisions for the time new replicas take to load the model and become ready.
+package stabilizer
+
+import (
+	"sync"
+	"time"
+)
+
+// recommendation of a number of replicas made at some time
+type recommendation struct {
+	replicas int
+	at       time.Time
+}
+
+// arrival rate observed at some time
+type rateSample struct {
+	rate float64
+	at   time.Time
+}
+
+// Stabilizer keeps, per variant, the recent replica recommendations and arrival rates.
+// Replicas are not available until the startup latency has elapsed, so:
+//   - during a ramp, capacity is sized for the arrival rate expected once new replicas are ready;
+//   - a scale-down is held until no higher recommendation was made within the startup latency,
+//     as it would otherwise need to be reversed before a replacement replica could be ready.
+type Stabilizer struct {
+	mu              sync.Mutex
+	recommendations map[string][]recommendation
+	rates           map[string][]rateSample
+}
+
+// Create a new stabilizer
+func NewStabilizer() *Stabilizer {
+	return &Stabilizer{
+		recommendations: make(map[string][]recommendation),
+		rates:           make(map[string][]rateSample),
+	}
+}
+
+// Record the desired number of replicas for a variant and return the number of replicas to apply:
+// the highest recommendation made within the window (normally the startup latency) ending now.
+func (s *Stabilizer) Stabilize(key string, desired int, window time.Duration, now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := make([]recommendation, 0, len(s.recommendations[key])+1)
+	stabilized := desired
+	for _, rec := range s.recommendations[key] {
+		if now.Sub(rec.at) > window {
+			continue
+		}
+		kept = append(kept, rec)
+		stabilized = max(stabilized, rec.replicas)
+	}
+	s.recommendations[key] = append(kept, recommendation{replicas: desired, at: now})
+	return stabilized
+}
+
+// Record the observed arrival rate for a variant and return the arrival rate to size for: if the rate increased
+// since the last rate observed at least the slope window ago, the rate extrapolated linearly to when a replica started
+// now would be ready, capped at a multiple of the observed rate; otherwise the observed rate. Measuring the slope over
+// the window rather than between consecutive cycles, which can be seconds apart, keeps the noise of the observed rate
+// from being amplified by the startup latency.
+func (s *Stabilizer) RampHeadroom(key string, rate float64, startup time.Duration, now time.Time, cfg Config) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// keep the samples within the window and the last one before it
+	samples := append(s.rates[key], rateSample{rate: rate, at: now})
+	base := -1
+	for i, sample := range samples {
+		if now.Sub(sample.at) >= cfg.RampSlopeWindow {
+			base = i
+		}
+	}
+	s.rates[key] = samples[max(base, 0):]
+	if base < 0 || startup <= 0 {
+		return rate
+	}
+	prev := samples[base]
+	if rate <= prev.rate {
+		return rate
+	}
+	slope := (rate - prev.rate) / now.Sub(prev.at).Seconds()
+	return min(rate+slope*startup.Seconds(), rate*cfg.MaxRampHeadroom)
+}
+
+// Drop all state kept for a variant
+func (s *Stabilizer) Forget(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.recommendations, key)
+	delete(s.rates, key)
+}
@@ -0,0 +1,88 @@
+package stabilizer
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStabilizeHoldsScaleDown(t *testing.T) {
+	s := NewStabilizer()
+	start := time.Now()
+	window := 5 * time.Minute
+
+	steps := []struct {
+		offset  time.Duration
+		desired int
+		want    int
+	}{
+		{offset: 0, desired: 4, want: 4},
+		{offset: time.Minute, desired: 6, want: 6},                 // scale-up applied immediately
+		{offset: 2 * time.Minute, desired: 2, want: 6},             // scale-down held
+		{offset: 6*time.Minute + time.Second, desired: 2, want: 2}, // recommendation of 6 expired
+		{offset: 7 * time.Minute, desired: 1, want: 2},             // held by recent recommendation of 2
+		{offset: 7*time.Minute + time.Second, desired: 3, want: 3}, // scale-up applied immediately
+	}
+	for _, step := range steps {
+		if got := s.Stabilize("va", step.desired, window, start.Add(step.offset)); got != step.want {
+			t.Errorf("Stabilize(desired=%d, at=%v) = %d, want %d", step.desired, step.offset, got, step.want)
+		}
+	}
+}
+
+func TestStabilizeZeroWindow(t *testing.T) {
+	s := NewStabilizer()
+	now := time.Now()
+	s.Stabilize("va", 5, 0, now)
+	if got := s.Stabilize("va", 1, 0, now.Add(time.Second)); got != 1 {
+		t.Errorf("Stabilize() = %d, want 1 with no window", got)
+	}
+}
+
+func TestStabilizeKeysAreIndependent(t *testing.T) {
+	s := NewStabilizer()
+	now := time.Now()
+	s.Stabilize("a", 5, time.Minute, now)
+	if got := s.Stabilize("b", 1, time.Minute, now); got != 1 {
+		t.Errorf("Stabilize(b) = %d, want 1", got)
+	}
+	s.Forget("a")
+	if got := s.Stabilize("a", 1, time.Minute, now); got != 1 {
+		t.Errorf("Stabilize(a) after Forget = %d, want 1", got)
+	}
+}
+
+func TestRampHeadroom(t *testing.T) {
+	s := NewStabilizer()
+	now := time.Now()
+	startup := 2 * time.Minute
+	cfg := Config{RampSlopeWindow: time.Minute, MaxRampHeadroom: 2}
+
+	if got := s.RampHeadroom("va", 60, startup, now, cfg); got != 60 {
+		t.Errorf("RampHeadroom(first sample) = %v, want 60", got)
+	}
+	// no sample the slope window ago yet
+	if got := s.RampHeadroom("va", 62, startup, now.Add(5*time.Second), cfg); got != 62 {
+		t.Errorf("RampHeadroom(within window) = %v, want 62", got)
+	}
+	// rate increased by 30 req/min over one minute, so expect another 60 req/min after two minutes
+	if got := s.RampHeadroom("va", 90, startup, now.Add(time.Minute), cfg); math.Abs(got-150) > 1e-9 {
+		t.Errorf("RampHeadroom(ramp) = %v, want 150", got)
+	}
+	// jitter 5 seconds later is measured against the sample a minute ago, not amplified
+	if got := s.RampHeadroom("va", 92, startup, now.Add(time.Minute+5*time.Second), cfg); math.Abs(got-92-60) > 1e-9 {
+		t.Errorf("RampHeadroom(jitter) = %v, want 152", got)
+	}
+	// decreasing rate gets no headroom
+	if got := s.RampHeadroom("va", 80, startup, now.Add(2*time.Minute), cfg); got != 80 {
+		t.Errorf("RampHeadroom(decreasing) = %v, want 80", got)
+	}
+	// no startup latency, no headroom
+	if got := s.RampHeadroom("va", 100, 0, now.Add(3*time.Minute), cfg); got != 100 {
+		t.Errorf("RampHeadroom(no startup) = %v, want 100", got)
+	}
+	// a steep ramp is capped at a multiple of the observed rate
+	if got := s.RampHeadroom("va", 200, 10*time.Minute, now.Add(4*time.Minute), cfg); got != 400 {
+		t.Errorf("RampHeadroom(capped) = %v, want 400", got)
+	}
+}
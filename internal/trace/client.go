@@ -0,0 +1,58 @@
+package trace
+
+import (
+	"context"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// recordingClient is a Kubernetes client adding the objects it reads to the cycle of the context, if any
+type recordingClient struct {
+	client.Client
+}
+
+// RecordingClient wraps a Kubernetes client so that the objects read in a cycle are recorded
+func RecordingClient(c client.Client) client.Client {
+	return &recordingClient{Client: c}
+}
+
+// Get reads an object, recording it
+func (c *recordingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.Client.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+	if cycle := CycleFrom(ctx); cycle != nil {
+		if err := cycle.AddObject(obj, c.Scheme()); err != nil {
+			logger.Log.Warn("failed to record object - ", "key: ", key, ", error: ", err)
+		}
+	}
+	return nil
+}
+
+// List reads a list of objects, recording its items
+func (c *recordingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	cycle := CycleFrom(ctx)
+	if cycle == nil {
+		return nil
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		logger.Log.Warn("failed to record list - ", "error: ", err)
+		return nil
+	}
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		if err := cycle.AddObject(obj, c.Scheme()); err != nil {
+			logger.Log.Warn("failed to record object - ", "name: ", obj.GetName(), ", error: ", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package trace
+
+import (
+	collector "github.com/llm-d-incubation/workload-variant-autoscaler/internal/collector"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// lastAppliedAnnotation holds a copy of the object applied with kubectl, as large as the object itself
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// compact returns a copy of an object with only the fields the controller reads, so that a trace grows with the
+// number of variants rather than with the size of their pod specs: pods keep the fields of their startup latency,
+// deployments drop their pod template spec, and no object keeps managed fields or the last applied
+// configuration
+func compact(obj client.Object) client.Object {
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		obj = collector.StartupLatencyFields(o)
+	case *appsv1.Deployment:
+		d := o.DeepCopy()
+		d.Spec.Template.Spec = corev1.PodSpec{}
+		obj = d
+	default:
+		obj = obj.DeepCopyObject().(client.Object)
+	}
+	obj.SetManagedFields(nil)
+	if annotations := obj.GetAnnotations(); annotations[lastAppliedAnnotation] != "" {
+		delete(annotations, lastAppliedAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+	return obj
+}
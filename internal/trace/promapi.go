@@ -0,0 +1,92 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// recordingPromAPI is a Prometheus API adding the results of its queries to the cycle of the context, if any
+type recordingPromAPI struct {
+	promv1.API
+}
+
+// RecordingPromAPI wraps a Prometheus API so that the instant and range queries made in a cycle are recorded
+func RecordingPromAPI(api promv1.API) promv1.API {
+	return &recordingPromAPI{API: api}
+}
+
+// Query performs an instant query, recording its result
+func (a *recordingPromAPI) Query(ctx context.Context, query string, ts time.Time, opts ...promv1.Option) (model.Value, promv1.Warnings, error) {
+	val, warn, err := a.API.Query(ctx, query, ts, opts...)
+	record(ctx, query, false, val, err)
+	return val, warn, err
+}
+
+// QueryRange performs a range query, recording its result
+func (a *recordingPromAPI) QueryRange(ctx context.Context, query string, r promv1.Range, opts ...promv1.Option) (model.Value, promv1.Warnings, error) {
+	val, warn, err := a.API.QueryRange(ctx, query, r, opts...)
+	record(ctx, query, true, val, err)
+	return val, warn, err
+}
+
+func record(ctx context.Context, query string, isRange bool, val model.Value, err error) {
+	if cycle := CycleFrom(ctx); cycle != nil {
+		if recordErr := cycle.AddQuery(query, isRange, val, err); recordErr != nil {
+			logger.Log.Warn("failed to record query - ", "query: ", query, ", error: ", recordErr)
+		}
+	}
+}
+
+// replayPromAPI is a Prometheus API answering queries with the results recorded in a cycle. A query made several
+// times gets the recorded results in order, the last one repeated; a query not recorded fails. Only instant and
+// range queries are supported.
+type replayPromAPI struct {
+	promv1.API
+	queries map[replayKey][]Query
+	next    map[replayKey]int
+}
+
+type replayKey struct {
+	query   string
+	isRange bool
+}
+
+// ReplayPromAPI creates a Prometheus API replaying the queries of a cycle
+func ReplayPromAPI(c *Cycle) promv1.API {
+	a := &replayPromAPI{
+		queries: make(map[replayKey][]Query),
+		next:    make(map[replayKey]int),
+	}
+	for _, q := range c.Queries {
+		key := replayKey{query: q.Query, isRange: q.Range}
+		a.queries[key] = append(a.queries[key], q)
+	}
+	return a
+}
+
+// Query replays an instant query
+func (a *replayPromAPI) Query(ctx context.Context, query string, ts time.Time, opts ...promv1.Option) (model.Value, promv1.Warnings, error) {
+	return a.replay(query, false)
+}
+
+// QueryRange replays a range query
+func (a *replayPromAPI) QueryRange(ctx context.Context, query string, r promv1.Range, opts ...promv1.Option) (model.Value, promv1.Warnings, error) {
+	return a.replay(query, true)
+}
+
+func (a *replayPromAPI) replay(query string, isRange bool) (model.Value, promv1.Warnings, error) {
+	key := replayKey{query: query, isRange: isRange}
+	recorded := a.queries[key]
+	if len(recorded) == 0 {
+		return nil, nil, fmt.Errorf("query not recorded: %s", query)
+	}
+	i := min(a.next[key], len(recorded)-1)
+	a.next[key] = i + 1
+	val, err := recorded[i].Value()
+	return val, nil, err
+}
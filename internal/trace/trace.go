@@ -0,0 +1,267 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Cycle holds the inputs of an optimization cycle: the Kubernetes objects read (variants, ConfigMaps, Deployments,
+// pods) and the results of the Prometheus queries made, in order
+type Cycle struct {
+	Time            time.Time         `json:"time"`
+	ConfigNamespace string            `json:"configNamespace"`
+	Objects         []json.RawMessage `json:"objects"`
+	Queries         []Query           `json:"queries"`
+
+	mu      sync.Mutex
+	objects map[objectKey]int // index of recorded objects, the last read of an object replacing earlier ones
+}
+
+// Query is a recorded Prometheus query and its result
+type Query struct {
+	Query  string          `json:"query"`
+	Range  bool            `json:"range,omitempty"` // range query, otherwise instant
+	Type   model.ValueType `json:"type,omitempty"`  // type of the result
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type objectKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+// NewCycle creates an empty cycle
+func NewCycle(t time.Time, configNamespace string) *Cycle {
+	return &Cycle{
+		Time:            t,
+		ConfigNamespace: configNamespace,
+		Objects:         make([]json.RawMessage, 0),
+		Queries:         make([]Query, 0),
+		objects:         make(map[objectKey]int),
+	}
+}
+
+type cycleKey struct{}
+
+// WithCycle returns a context in which the reads of the recording client and Prometheus API are added to a cycle
+func WithCycle(ctx context.Context, c *Cycle) context.Context {
+	return context.WithValue(ctx, cycleKey{}, c)
+}
+
+// CycleFrom returns the cycle recorded in a context; nil if none
+func CycleFrom(ctx context.Context) *Cycle {
+	c, _ := ctx.Value(cycleKey{}).(*Cycle)
+	return c
+}
+
+// AddObject records a Kubernetes object read, with only the fields the controller reads
+func (c *Cycle) AddObject(obj client.Object, scheme *runtime.Scheme) error {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return err
+	}
+	obj = compact(obj)
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.objects == nil {
+		c.objects = make(map[objectKey]int)
+	}
+	key := objectKey{gvk: gvk, namespace: obj.GetNamespace(), name: obj.GetName()}
+	if i, ok := c.objects[key]; ok {
+		c.Objects[i] = raw
+		return nil
+	}
+	c.objects[key] = len(c.Objects)
+	c.Objects = append(c.Objects, raw)
+	return nil
+}
+
+// AddQuery records the result or error of a Prometheus query
+func (c *Cycle) AddQuery(query string, isRange bool, val model.Value, queryErr error) error {
+	q := Query{Query: query, Range: isRange}
+	if queryErr != nil {
+		q.Error = queryErr.Error()
+	} else if val != nil {
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		q.Type = val.Type()
+		q.Result = raw
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Queries = append(c.Queries, q)
+	return nil
+}
+
+// DecodeObjects returns the recorded objects as typed objects of the scheme, without resource versions
+func (c *Cycle) DecodeObjects(scheme *runtime.Scheme) ([]client.Object, error) {
+	objs := make([]client.Object, 0, len(c.Objects))
+	for _, raw := range c.Objects {
+		var typeMeta metav1.TypeMeta
+		if err := json.Unmarshal(raw, &typeMeta); err != nil {
+			return nil, err
+		}
+		gvk := typeMeta.GroupVersionKind()
+		runtimeObj, err := scheme.New(gvk)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := runtimeObj.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("%s is not an object", gvk)
+		}
+		if err := json.Unmarshal(raw, obj); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", gvk, err)
+		}
+		obj.SetResourceVersion("")
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// Value returns the result of a recorded query
+func (q *Query) Value() (model.Value, error) {
+	if q.Error != "" {
+		return nil, errors.New(q.Error)
+	}
+	var val model.Value
+	switch q.Type {
+	case model.ValNone:
+		return nil, nil
+	case model.ValScalar:
+		val = &model.Scalar{}
+	case model.ValVector:
+		val = &model.Vector{}
+	case model.ValMatrix:
+		val = &model.Matrix{}
+	case model.ValString:
+		val = &model.String{}
+	default:
+		return nil, fmt.Errorf("unknown result type %s of query %s", q.Type, q.Query)
+	}
+	if err := json.Unmarshal(q.Result, val); err != nil {
+		return nil, fmt.Errorf("invalid result of query %s: %w", q.Query, err)
+	}
+	// vectors and matrices are returned by value, as by the Prometheus API
+	switch v := val.(type) {
+	case *model.Vector:
+		return *v, nil
+	case *model.Matrix:
+		return *v, nil
+	}
+	return val, nil
+}
+
+// Writer appends cycles to a trace file, one JSON line per cycle
+type Writer struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64 // size in bytes beyond which the file is rotated; unlimited if not positive
+	size    int64
+	file    *os.File
+}
+
+// NewWriter opens a trace file for appending, creating it if missing. Once a cycle would grow the file beyond
+// maxSize bytes, the file is moved to a backup with the .1 suffix, replacing the previous backup, and a new file is
+// started, so that at most twice maxSize bytes are kept; the size is unlimited if maxSize is not positive.
+func NewWriter(path string, maxSize int64) (*Writer, error) {
+	w := &Writer{path: path, maxSize: maxSize}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// open opens the trace file for appending
+func (w *Writer) open() error {
+	file, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close() //nolint:errcheck
+		return err
+	}
+	w.file, w.size = file, info.Size()
+	return nil
+}
+
+// rotate moves the trace file to its backup and starts a new file; the file is reopened as is if it cannot be moved
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	renameErr := os.Rename(w.path, w.path+".1")
+	if err := w.open(); err != nil {
+		return err
+	}
+	return renameErr
+}
+
+// Write appends a cycle to the trace file
+func (w *Writer) Write(c *Cycle) error {
+	c.mu.Lock()
+	line, err := json.Marshal(c)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(line)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate trace file: %w", err)
+		}
+	}
+	n, err := w.file.Write(line)
+	w.size += int64(n)
+	return err
+}
+
+// Close closes the trace file
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+// ReadCycles reads the cycles of a trace, in order
+func ReadCycles(r io.Reader) ([]*Cycle, error) {
+	decoder := json.NewDecoder(r)
+	cycles := make([]*Cycle, 0)
+	for {
+		c := &Cycle{}
+		if err := decoder.Decode(c); err == io.EOF {
+			return cycles, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid cycle %d of trace: %w", len(cycles)+1, err)
+		}
+		cycles = append(cycles, c)
+	}
+}
@@ -0,0 +1,226 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/internal/logger"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	logger.Log = zap.NewNop().Sugar()
+}
+
+func testScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	return scheme
+}
+
+func TestRecordingClient(t *testing.T) {
+	scheme := testScheme(t)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "ns", ResourceVersion: "7",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}},
+		Data: map[string]string{"key": "value"},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", Labels: map[string]string{"app": "chat"},
+			Annotations: map[string]string{lastAppliedAnnotation: "{}"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "vllm", Image: "vllm"}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+	}
+	k8sClient := RecordingClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm, pod).Build())
+
+	// reads outside a cycle are not recorded
+	if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	cycle := NewCycle(time.Now(), "ns")
+	ctx := WithCycle(context.Background(), cycle)
+	for range 2 {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+	if err := k8sClient.List(ctx, &corev1.PodList{}); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: "missing", Namespace: "ns"}, &corev1.ConfigMap{}); err == nil {
+		t.Fatal("Expected error for missing object")
+	}
+	if len(cycle.Objects) != 2 {
+		t.Fatalf("Expected the ConfigMap and the pod recorded once each, got %d objects", len(cycle.Objects))
+	}
+
+	objs, err := cycle.DecodeObjects(scheme)
+	if err != nil {
+		t.Fatalf("DecodeObjects() error = %v", err)
+	}
+	decoded, ok := objs[0].(*corev1.ConfigMap)
+	if !ok {
+		t.Fatalf("Expected a ConfigMap, got %T", objs[0])
+	}
+	if decoded.Data["key"] != "value" || decoded.ResourceVersion != "" || decoded.ManagedFields != nil {
+		t.Errorf("Expected the ConfigMap data without resource version and managed fields, got %v", decoded)
+	}
+	decodedPod, ok := objs[1].(*corev1.Pod)
+	if !ok {
+		t.Fatalf("Expected a pod, got %T", objs[1])
+	}
+	if decodedPod.Labels["app"] != "chat" || len(decodedPod.Status.Conditions) != 1 {
+		t.Errorf("Expected the pod labels and conditions, got %v", decodedPod)
+	}
+	if decodedPod.Annotations != nil || decodedPod.Spec.Containers != nil || decodedPod.Status.Phase != "" {
+		t.Errorf("Expected the pod without annotations, spec and phase, got %v", decodedPod)
+	}
+}
+
+func TestWriterRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	line, err := json.Marshal(NewCycle(time.Unix(0, 0), "ns"))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	// room for two cycles per file
+	writer, err := NewWriter(path, int64(2*(len(line)+1)))
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for range 5 {
+		if err := writer.Write(NewCycle(time.Unix(0, 0), "ns")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	for file, want := range map[string]int{path: 1, path + ".1": 2} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		cycles, err := ReadCycles(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("ReadCycles() error = %v", err)
+		}
+		if len(cycles) != want {
+			t.Errorf("Expected %d cycles in %s, got %d", want, filepath.Base(file), len(cycles))
+		}
+	}
+}
+
+// promAPI answers queries with a sequence of values
+type promAPI struct {
+	promv1.API
+	values []model.Value
+	err    error
+}
+
+func (a *promAPI) Query(ctx context.Context, query string, ts time.Time, opts ...promv1.Option) (model.Value, promv1.Warnings, error) {
+	if a.err != nil {
+		return nil, nil, a.err
+	}
+	val := a.values[0]
+	a.values = a.values[1:]
+	return val, nil, nil
+}
+
+func (a *promAPI) QueryRange(ctx context.Context, query string, r promv1.Range, opts ...promv1.Option) (model.Value, promv1.Warnings, error) {
+	return model.Matrix{&model.SampleStream{Values: []model.SamplePair{{Timestamp: 1000, Value: 3}}}}, nil, nil
+}
+
+func TestRecordAndReplayQueries(t *testing.T) {
+	api := &promAPI{values: []model.Value{
+		model.Vector{&model.Sample{Value: 1}},
+		model.Vector{&model.Sample{Value: 2}},
+		&model.Scalar{Value: 5},
+	}}
+	recording := RecordingPromAPI(api)
+	cycle := NewCycle(time.Now(), "ns")
+	ctx := WithCycle(context.Background(), cycle)
+	for _, query := range []string{"up", "up", "scalar(up)"} {
+		if _, _, err := recording.Query(ctx, query, time.Now()); err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+	}
+	if _, _, err := recording.QueryRange(ctx, "up", promv1.Range{}); err != nil {
+		t.Fatalf("QueryRange() error = %v", err)
+	}
+	api.err = errors.New("timeout")
+	if _, _, err := recording.Query(ctx, "down", time.Now()); err == nil {
+		t.Fatal("Expected query error")
+	}
+
+	// the cycle survives a roundtrip through a trace
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	writer, err := NewWriter(path, 0)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	if err := writer.Write(cycle); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := writer.Write(NewCycle(time.Now(), "ns")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	cycles, err := ReadCycles(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadCycles() error = %v", err)
+	}
+	if len(cycles) != 2 {
+		t.Fatalf("Expected 2 cycles, got %d", len(cycles))
+	}
+
+	replay := ReplayPromAPI(cycles[0])
+	wantUp := []model.SampleValue{1, 2, 2} // in order, the last one repeated
+	for i, want := range wantUp {
+		val, _, err := replay.Query(ctx, "up", time.Now())
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		vector, ok := val.(model.Vector)
+		if !ok || len(vector) != 1 || vector[0].Value != want {
+			t.Errorf("Query %d: expected %v, got %v", i, want, val)
+		}
+	}
+	if val, _, err := replay.Query(ctx, "scalar(up)", time.Now()); err != nil || val.(*model.Scalar).Value != 5 {
+		t.Errorf("Expected scalar 5, got %v, %v", val, err)
+	}
+	if val, _, err := replay.QueryRange(ctx, "up", promv1.Range{}); err != nil || val.(model.Matrix)[0].Values[0].Value != 3 {
+		t.Errorf("Expected matrix, got %v, %v", val, err)
+	}
+	if _, _, err := replay.Query(ctx, "down", time.Now()); err == nil || err.Error() != "timeout" {
+		t.Errorf("Expected recorded error, got %v", err)
+	}
+	if _, _, err := replay.Query(ctx, "missing", time.Now()); err == nil {
+		t.Error("Expected error for query not recorded")
+	}
+}
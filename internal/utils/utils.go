@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"math"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -104,7 +106,8 @@ func UpdateStatusWithBackoff[T client.Object](ctx context.Context, c client.Clie
 }
 
 // Adapter to create wva system data types from config maps.
-// Note: WVA operates in unlimited mode, so capacity data is not used.
+// Note: the capacity data holds the accelerator types with a count, and is only used if the optimizer spec is set
+// to limited mode.
 func CreateSystemData(
 	acceleratorCm map[string]map[string]string,
 	serviceClassCm map[string]string) *infernoConfig.SystemData {
@@ -120,26 +123,47 @@ func CreateSystemData(
 		},
 	}
 
-	// get accelerator data
+	// get accelerator data, and the capacity of the accelerator types with a count
 	acceleratorData := []infernoConfig.AcceleratorSpec{}
+	capacity := make(map[string]int)
 	for key, val := range acceleratorCm {
 		cost, err := strconv.ParseFloat(val["cost"], 32)
 		if err != nil {
 			logger.Log.Warn("failed to parse accelerator cost in configmap, skipping accelerator", "name", key)
 			continue
 		}
+		// memory size (GB) is optional, used to scale KV cache limits across accelerators
+		memSize := 0
+		if val["memSize"] != "" {
+			if memSize, err = strconv.Atoi(val["memSize"]); err != nil || memSize < 0 {
+				logger.Log.Warn("failed to parse accelerator memory size in configmap, ignoring it", "name", key)
+				memSize = 0
+			}
+		}
+		// number of units available in the cluster is optional, only used in limited mode
+		if val["count"] != "" {
+			if count, err := strconv.Atoi(val["count"]); err == nil && count >= 0 {
+				capacity[val["device"]] += count
+			} else {
+				logger.Log.Warn("failed to parse accelerator count in configmap, ignoring it", "name", key)
+			}
+		}
 		acceleratorData = append(acceleratorData, infernoConfig.AcceleratorSpec{
 			Name:         key,
 			Type:         val["device"],
-			Multiplicity: 1,                         // TODO: multiplicity should be in the configured accelerator spec
+			Multiplicity: 1, // TODO: multiplicity should be in the configured accelerator spec
+			MemSize:      memSize,
 			Power:        infernoConfig.PowerSpec{}, // Not currently used
 			Cost:         float32(cost),
 		})
 	}
 	systemData.Spec.Accelerators.Spec = acceleratorData
 
-	// Capacity data is not used in unlimited mode - initialize empty for future limited mode work
 	systemData.Spec.Capacity.Count = []infernoConfig.AcceleratorCount{}
+	for _, accType := range slices.Sorted(maps.Keys(capacity)) {
+		systemData.Spec.Capacity.Count = append(systemData.Spec.Capacity.Count,
+			infernoConfig.AcceleratorCount{Type: accType, Count: capacity[accType]})
+	}
 
 	// get service class data
 	serviceClassData := []infernoConfig.ServiceClassSpec{}
@@ -165,11 +189,10 @@ func CreateSystemData(
 	}
 	systemData.Spec.ServiceClasses.Spec = serviceClassData
 
-	// set optimizer configuration
-	// TODO: make it configurable
+	// set optimizer configuration, replaced by the solver mode of the optimization ConfigMap
 	systemData.Spec.Optimizer.Spec = infernoConfig.OptimizerSpec{
 		Unlimited: true,
-		// SaturationPolicy omitted - defaults to "None" (not relevant in unlimited mode)
+		// SaturationPolicy omitted - defaults to "None"
 	}
 
 	// initialize model data
@@ -187,50 +210,58 @@ func AddModelAcceleratorProfileToSystemData(
 	modelName string,
 	modelAcceleratorProfile *llmdVariantAutoscalingV1alpha1.AcceleratorProfile) (err error) {
 
+	decodeParms, prefillParms, err := ParsePerfParms(modelAcceleratorProfile)
+	if err != nil {
+		return err
+	}
+
+	sd.Spec.Models.PerfData = append(sd.Spec.Models.PerfData,
+		infernoConfig.ModelAcceleratorPerfData{
+			Name:         modelName,
+			Acc:          modelAcceleratorProfile.Acc,
+			AccCount:     modelAcceleratorProfile.AccCount,
+			MaxBatchSize: modelAcceleratorProfile.MaxBatchSize,
+			DecodeParms:  decodeParms,
+			PrefillParms: prefillParms,
+		})
+	return nil
+}
+
+// Parse the decode (itl) and prefill (ttft) model parameters of a model accelerator profile
+func ParsePerfParms(modelAcceleratorProfile *llmdVariantAutoscalingV1alpha1.AcceleratorProfile) (
+	decode infernoConfig.DecodeParms, prefill infernoConfig.PrefillParms, err error) {
+
 	// extract decode model (itl) parameters
 	decodeParms := modelAcceleratorProfile.PerfParms.DecodeParms
 	if len(decodeParms) < 2 {
-		return fmt.Errorf("length of decodeParms should be 2")
+		return decode, prefill, fmt.Errorf("length of decodeParms should be 2")
 	}
 
 	var alpha, beta float64
 	if alpha, err = strconv.ParseFloat(decodeParms["alpha"], 32); err != nil {
-		return err
+		return decode, prefill, err
 	}
 	if beta, err = strconv.ParseFloat(decodeParms["beta"], 32); err != nil {
-		return err
+		return decode, prefill, err
 	}
 
 	// extract prefill model (ttft) parameters
 	prefillParms := modelAcceleratorProfile.PerfParms.PrefillParms
 	if len(prefillParms) < 2 {
-		return fmt.Errorf("length of prefillParms should be 2")
+		return decode, prefill, fmt.Errorf("length of prefillParms should be 2")
 	}
 
 	var gamma, delta float64
 	if gamma, err = strconv.ParseFloat(prefillParms["gamma"], 32); err != nil {
-		return err
+		return decode, prefill, err
 	}
 	if delta, err = strconv.ParseFloat(prefillParms["delta"], 32); err != nil {
-		return err
+		return decode, prefill, err
 	}
 
-	sd.Spec.Models.PerfData = append(sd.Spec.Models.PerfData,
-		infernoConfig.ModelAcceleratorPerfData{
-			Name:         modelName,
-			Acc:          modelAcceleratorProfile.Acc,
-			AccCount:     modelAcceleratorProfile.AccCount,
-			MaxBatchSize: modelAcceleratorProfile.MaxBatchSize,
-			DecodeParms: infernoConfig.DecodeParms{
-				Alpha: float32(alpha),
-				Beta:  float32(beta),
-			},
-			PrefillParms: infernoConfig.PrefillParms{
-				Gamma: float32(gamma),
-				Delta: float32(delta),
-			},
-		})
-	return nil
+	decode = infernoConfig.DecodeParms{Alpha: float32(alpha), Beta: float32(beta)}
+	prefill = infernoConfig.PrefillParms{Gamma: float32(gamma), Delta: float32(delta)}
+	return decode, prefill, nil
 }
 
 // Add server specs to inferno system data
@@ -285,6 +316,7 @@ func AddServerInfoToSystemData(
 	}
 	serverSpec := &infernoConfig.ServerSpec{
 		Name:            FullName(va.Name, va.Namespace),
+		Namespace:       va.Namespace,
 		Class:           className,
 		Model:           va.Spec.ModelID,
 		KeepAccelerator: true,
@@ -310,6 +342,47 @@ func AddServerInfoToSystemData(
 	return nil
 }
 
+// Set the arrival rate (req/min) in the current load of a server in inferno system data
+func SetServerArrivalRate(sd *infernoConfig.SystemData, serverName string, arrivalRate float32) error {
+	for i := range sd.Spec.Servers.Spec {
+		if sd.Spec.Servers.Spec[i].Name == serverName {
+			sd.Spec.Servers.Spec[i].CurrentAlloc.Load.ArrivalRate = arrivalRate
+			return nil
+		}
+	}
+	return fmt.Errorf("server %s not found", serverName)
+}
+
+// Set the max batch size of a server on an accelerator in inferno system data
+func SetServerAcceleratorMaxBatchSize(sd *infernoConfig.SystemData, serverName, accName string, maxBatchSize int) error {
+	for i := range sd.Spec.Servers.Spec {
+		server := &sd.Spec.Servers.Spec[i]
+		if server.Name == serverName {
+			if server.AcceleratorMaxBatchSize == nil {
+				server.AcceleratorMaxBatchSize = make(map[string]int)
+			}
+			server.AcceleratorMaxBatchSize[accName] = maxBatchSize
+			return nil
+		}
+	}
+	return fmt.Errorf("server %s not found", serverName)
+}
+
+// Get the overriding max batch size of a server on an accelerator in inferno system data, as used by the optimizer;
+// zero if not set
+func ServerMaxBatchSize(sd *infernoConfig.SystemData, serverName, accName string) int {
+	for i := range sd.Spec.Servers.Spec {
+		server := &sd.Spec.Servers.Spec[i]
+		if server.Name == serverName {
+			if n := server.AcceleratorMaxBatchSize[accName]; n > 0 {
+				return n
+			}
+			return server.MaxBatchSize
+		}
+	}
+	return 0
+}
+
 // Adapter from inferno alloc solution to optimized alloc
 func CreateOptimizedAlloc(name string,
 	namespace string,
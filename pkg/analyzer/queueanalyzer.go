@@ -63,6 +63,7 @@ type AnalysisMetrics struct {
 	AvgRespTime    float32 // average request response time (aka latency) (msec)
 	AvgWaitTime    float32 // average request queueing time (msec)
 	AvgNumInServ   float32 // average number of requests in service
+	AvgQueueLength float32 // average number of requests waiting for service
 	AvgPrefillTime float32 // average request prefill time (msec)
 	AvgTokenTime   float32 // average token decode time (msec)
 	MaxRate        float32 // maximum throughput (requests/sec)
@@ -165,6 +166,7 @@ func (qa *QueueAnalyzer) Analyze(requestRate float32) (metrics *AnalysisMetrics,
 		AvgRespTime:    model.GetAvgRespTime(),
 		AvgWaitTime:    model.GetAvgWaitTime(),
 		AvgNumInServ:   avgNumInServ,
+		AvgQueueLength: model.GetAvgQueueLength(),
 		AvgPrefillTime: prefillTime,
 		AvgTokenTime:   tokenTime,
 		MaxRate:        rateRange.Max,
@@ -364,8 +366,8 @@ func (rr *RateRange) String() string {
 }
 
 func (am *AnalysisMetrics) String() string {
-	return fmt.Sprintf("{tput=%.3f, lat=%.3f, wait=%.3f, conc=%.3f, prefill=%.3f, itl=%.3f, maxRate=%.3f, rho=%0.3f}",
-		am.Throughput, am.AvgRespTime, am.AvgWaitTime, am.AvgNumInServ, am.AvgPrefillTime, am.AvgTokenTime, am.MaxRate, am.Rho)
+	return fmt.Sprintf("{tput=%.3f, lat=%.3f, wait=%.3f, conc=%.3f, queue=%.3f, prefill=%.3f, itl=%.3f, maxRate=%.3f, rho=%0.3f}",
+		am.Throughput, am.AvgRespTime, am.AvgWaitTime, am.AvgNumInServ, am.AvgQueueLength, am.AvgPrefillTime, am.AvgTokenTime, am.MaxRate, am.Rho)
 }
 
 func (tp *TargetPerf) String() string {
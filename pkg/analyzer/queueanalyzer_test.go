@@ -429,6 +429,10 @@ func TestQueueAnalyzer_Analyze(t *testing.T) {
 					t.Errorf("AvgNumInServ (%v) should be non-negative", metrics.AvgNumInServ)
 				}
 
+				if metrics.AvgQueueLength < 0 {
+					t.Errorf("AvgQueueLength (%v) should be non-negative", metrics.AvgQueueLength)
+				}
+
 				if metrics.Rho < 0 || metrics.Rho > 1 {
 					t.Errorf("Rho (%v) should be between 0 and 1", metrics.Rho)
 				}
@@ -8,6 +8,7 @@ const (
 	PriorityExhaustive                                  // 1 : allocating exhaustively to servers in priority ordering
 	PriorityRoundRobin                                  // 2 : allocating in round-robin fashion within priority groups
 	RoundRobin                                          // 3 : allocating in round-robin fashion across all servers
+	SLODeficit                                          // 4 : allocating to minimize SLO violation weighted by priority
 )
 
 func (p SaturatedAllocationPolicy) String() string {
@@ -20,6 +21,8 @@ func (p SaturatedAllocationPolicy) String() string {
 		return "PriorityRoundRobin"
 	case RoundRobin:
 		return "RoundRobin"
+	case SLODeficit:
+		return "SLODeficit"
 	default:
 		return "Unknown"
 	}
@@ -35,6 +38,8 @@ func SaturatedAllocationPolicyEnum(s string) SaturatedAllocationPolicy {
 		return PriorityRoundRobin
 	case "RoundRobin":
 		return RoundRobin
+	case "SLODeficit":
+		return SLODeficit
 	default:
 		return DefaultSaturatedAllocationPolicy
 	}
@@ -30,6 +30,11 @@ func TestSaturatedAllocationPolicy_String(t *testing.T) {
 			policy: RoundRobin,
 			want:   "RoundRobin",
 		},
+		{
+			name:   "SLODeficit policy",
+			policy: SLODeficit,
+			want:   "SLODeficit",
+		},
 		{
 			name:   "Unknown policy",
 			policy: SaturatedAllocationPolicy(999),
@@ -72,6 +77,11 @@ func TestSaturatedAllocationPolicyEnum(t *testing.T) {
 			input: "RoundRobin",
 			want:  RoundRobin,
 		},
+		{
+			name:  "SLODeficit string",
+			input: "SLODeficit",
+			want:  SLODeficit,
+		},
 		{
 			name:  "Unknown policy string returns default",
 			input: "InvalidPolicy",
@@ -104,6 +114,7 @@ func TestSaturatedAllocationPolicy_RoundTrip(t *testing.T) {
 		PriorityExhaustive,
 		PriorityRoundRobin,
 		RoundRobin,
+		SLODeficit,
 	}
 
 	for _, policy := range policies {
@@ -32,5 +32,8 @@ const DefaultHighPriority int = 1
 // default priority of a service class (lowest)
 const DefaultServiceClassPriority int = DefaultLowPriority
 
+// default time budget of the MIP solver (msec)
+const DefaultMIPTimeLimitMsec int = 1000
+
 // default option for allocation under saturated condition
 var DefaultSaturatedAllocationPolicy SaturatedAllocationPolicy = None
@@ -15,6 +15,8 @@ type SystemSpec struct {
 	ServiceClasses ServiceClassData `json:"serviceClassData"` // service class data
 	Servers        ServerData       `json:"serverData"`       // server data
 	Optimizer      OptimizerData    `json:"optimizerData"`    // optimizer data
+	Budget         BudgetData       `json:"budgetData"`       // cost budget data
+	Quotas         QuotaData        `json:"quotaData"`        // accelerator quotas of namespaces
 
 	// dynamic data
 	Capacity CapacityData `json:"capacityData"` // data about accelerator type availability
@@ -55,6 +57,30 @@ type AcceleratorCount struct {
 	Count int    `json:"count"` // number of available units
 }
 
+// Data about accelerator quotas of namespaces
+type QuotaData struct {
+	Spec []NamespaceQuota `json:"quotas"` // quotas of namespaces
+}
+
+// Accelerator quota of the servers in a namespace, on top of the capacity of the system;
+// accelerator types not listed are only limited by capacity
+type NamespaceQuota struct {
+	Namespace string             `json:"namespace"` // namespace of servers
+	Count     []AcceleratorCount `json:"count"`     // maximum number of units of accelerator types
+}
+
+// Data about ceilings on the total cost of allocations
+type BudgetData struct {
+	Total      float32           `json:"total"`      // cluster-wide cost ceiling (cents/hr); unlimited if zero
+	Namespaces []NamespaceBudget `json:"namespaces"` // cost ceilings of namespaces
+}
+
+// Cost ceiling of the servers in a namespace
+type NamespaceBudget struct {
+	Namespace string  `json:"namespace"` // namespace of servers
+	Limit     float32 `json:"limit"`     // cost ceiling (cents/hr); unlimited if zero
+}
+
 // Data related to a Model
 type ModelData struct {
 	PerfData []ModelAcceleratorPerfData `json:"models"` // performance data for model on accelerators
@@ -110,14 +136,16 @@ type ServerData struct {
 
 // Specifications of a server
 type ServerSpec struct {
-	Name            string         `json:"name"`            // server name
-	Class           string         `json:"class"`           // service class name
-	Model           string         `json:"model"`           // model name
-	KeepAccelerator bool           `json:"keepAccelerator"` // option to not change accelerator
-	MinNumReplicas  int            `json:"minNumReplicas"`  // minimum number of replicas
-	MaxBatchSize    int            `json:"maxBatchSize"`    // overriding value for the maximum batch size
-	CurrentAlloc    AllocationData `json:"currentAlloc"`    // current allocation
-	DesiredAlloc    AllocationData `json:"desiredAlloc"`    // desired allocation
+	Name                    string         `json:"name"`                              // server name
+	Namespace               string         `json:"namespace"`                         // namespace of server, for namespace cost budgets
+	Class                   string         `json:"class"`                             // service class name
+	Model                   string         `json:"model"`                             // model name
+	KeepAccelerator         bool           `json:"keepAccelerator"`                   // option to not change accelerator
+	MinNumReplicas          int            `json:"minNumReplicas"`                    // minimum number of replicas
+	MaxBatchSize            int            `json:"maxBatchSize"`                      // overriding value for the maximum batch size
+	AcceleratorMaxBatchSize map[string]int `json:"acceleratorMaxBatchSize,omitempty"` // overriding value for the maximum batch size on given accelerators
+	CurrentAlloc            AllocationData `json:"currentAlloc"`                      // current allocation
+	DesiredAlloc            AllocationData `json:"desiredAlloc"`                      // desired allocation
 }
 
 // Data about a server allocation
@@ -152,4 +180,6 @@ type OptimizerSpec struct {
 	Unlimited         bool   `json:"unlimited"`         // unlimited number of accelerator types (for capacity planning and/or cloud)
 	DelayedBestEffort bool   `json:"delayedBestEffort"` // delay best effort allocation after attempting allocation to all priority groups
 	SaturationPolicy  string `json:"saturationPolicy"`  // allocation policy under saturated condition
+	MIP               bool   `json:"mip"`               // solve limited capacity allocation exactly as integer program
+	MIPTimeLimitMsec  int    `json:"mipTimeLimitMsec"`  // time budget of integer program solution, falling back to greedy
 }
@@ -23,55 +23,61 @@ type Allocation struct {
 	maxArrvRatePerReplica float32 // maximum arrival rate per replica (req/msec)
 }
 
-// Create an allocation of an accelerator to a server; nil if not feasible
-func CreateAllocation(serverName string, gName string) *Allocation {
-	var (
-		acc *Accelerator
-
-		server *Server
-		load   *config.ServerLoadSpec
-
-		model *Model
-		perf  *config.ModelAcceleratorPerfData
+// Queueing model of a replica of a server on an accelerator, under the current load of the server
+type replicaQueue struct {
+	server *Server
+	model  *Model
+	acc    *Accelerator
+	perf   *config.ModelAcceleratorPerfData
+	target *Target
+	load   *config.ServerLoadSpec
+
+	batchSize     int                     // max batch size
+	totalRate     float32                 // total request rate to the server (req/sec)
+	queueAnalyzer *analyzer.QueueAnalyzer // nil in case of zero load
+}
 
-		svc    *ServiceClass
-		target *Target
-	)
+// Create the queueing model of a replica of a server on an accelerator; nil if not feasible
+func newReplicaQueue(serverName string, gName string) *replicaQueue {
+	q := &replicaQueue{}
 
 	// get accelerator info
-	if acc = GetAccelerator(gName); acc == nil {
+	if q.acc = GetAccelerator(gName); q.acc == nil {
 		return nil
 	}
 
 	// get server info
-	if server = GetServer(serverName); server == nil {
+	if q.server = GetServer(serverName); q.server == nil {
 		return nil
 	}
-	if load = server.Load(); load == nil || load.ArrivalRate < 0 ||
+	load := q.server.Load()
+	if load == nil || load.ArrivalRate < 0 ||
 		load.AvgInTokens < 0 || load.AvgOutTokens < 0 {
 		return nil
 	}
+	q.load = load
 
 	// get model info
-	modelName := server.ModelName()
-	if model = GetModel(modelName); model == nil {
+	modelName := q.server.ModelName()
+	if q.model = GetModel(modelName); q.model == nil {
 		return nil
 	}
-	if perf = model.PerfData(gName); perf == nil {
+	if q.perf = q.model.PerfData(gName); q.perf == nil {
 		return nil
 	}
 
 	// get service class info
-	if svc = GetServiceClass(server.ServiceClassName()); svc == nil {
+	svc := GetServiceClass(q.server.ServiceClassName())
+	if svc == nil {
 		return nil
 	}
-	if target = svc.ModelTarget(modelName); target == nil {
+	if q.target = svc.ModelTarget(modelName); q.target == nil {
 		return nil
 	}
 
 	// handle zero traffic case
 	if load.ArrivalRate == 0 || load.AvgOutTokens == 0 {
-		return zeroLoadAllocation(server, model, acc, perf)
+		return q
 	}
 
 	// calculate max batch size (N) based on average request length (K)
@@ -79,10 +85,10 @@ func CreateAllocation(serverName string, gName string) *Allocation {
 
 	// use maxBatchSize from configured value or scaled performance data
 	var N int
-	if server.maxBatchSize > 0 {
-		N = server.maxBatchSize
+	if n := q.server.MaxBatchSize(gName); n > 0 {
+		N = n
 	} else {
-		N = max(perf.MaxBatchSize*perf.AtTokens/K, 1)
+		N = max(q.perf.MaxBatchSize*q.perf.AtTokens/K, 1)
 	}
 	maxQueue := N * config.MaxQueueToBatchRatio
 
@@ -92,12 +98,12 @@ func CreateAllocation(serverName string, gName string) *Allocation {
 		MaxQueueSize: maxQueue,
 		ServiceParms: &analyzer.ServiceParms{
 			Prefill: &analyzer.PrefillParms{
-				Gamma: perf.PrefillParms.Gamma,
-				Delta: perf.PrefillParms.Delta,
+				Gamma: q.perf.PrefillParms.Gamma,
+				Delta: q.perf.PrefillParms.Delta,
 			},
 			Decode: &analyzer.DecodeParms{
-				Alpha: perf.DecodeParms.Alpha,
-				Beta:  perf.DecodeParms.Beta,
+				Alpha: q.perf.DecodeParms.Alpha,
+				Beta:  q.perf.DecodeParms.Beta,
 			},
 		},
 	}
@@ -112,41 +118,65 @@ func CreateAllocation(serverName string, gName string) *Allocation {
 		fmt.Println(err)
 		return nil
 	}
+	q.queueAnalyzer = queueAnalyzer
+	q.batchSize = N
+
+	// calculate total rate
+	if q.target.TPS == 0 {
+		q.totalRate = load.ArrivalRate / 60
+	} else {
+		q.totalRate = q.target.TPS / float32(K)
+	}
+	return q
+}
 
+// Maximum request rate of a replica (req/sec) meeting the targets of the server
+func (q *replicaQueue) maxRateWithinSLO() (float32, error) {
 	// TODO: do we need this?
 	// waitTimeLimit := target.TTFT / config.SLOMargin // distribution of waiting time assumed exponential
 
 	targetPerf := &analyzer.TargetPerf{
-		TargetTTFT: target.TTFT,
-		TargetITL:  target.ITL,
-		TargetTPS:  target.TPS,
+		TargetTTFT: q.target.TTFT,
+		TargetITL:  q.target.ITL,
+		TargetTPS:  q.target.TPS,
+	}
+	_, metrics, _, err := q.queueAnalyzer.Size(targetPerf)
+	if err != nil {
+		return 0, err
+	}
+	return metrics.Throughput, nil
+}
+
+// Create an allocation of an accelerator to a server; nil if not feasible
+func CreateAllocation(serverName string, gName string) *Allocation {
+	q := newReplicaQueue(serverName, gName)
+	if q == nil {
+		return nil
+	}
+
+	// handle zero traffic case
+	if q.queueAnalyzer == nil {
+		return zeroLoadAllocation(q.server, q.model, q.acc, q.perf)
 	}
 
 	// determine max rates to satisfy targets
-	_, metrics, _, err := queueAnalyzer.Size(targetPerf)
+	rateStar, err := q.maxRateWithinSLO()
 	if err != nil {
 		// fmt.Println(err)
 		return nil
 	}
-	rateStar := metrics.Throughput
 
 	// calculate number of replicas
-	var totalRate float32
-	if target.TPS == 0 {
-		totalRate = load.ArrivalRate / 60
-	} else {
-		totalRate = target.TPS / float32(K)
-	}
-	numReplicas := int(math.Ceil(float64(totalRate) / float64(rateStar)))
-	numReplicas = max(numReplicas, server.minNumReplicas)
+	numReplicas := int(math.Ceil(float64(q.totalRate) / float64(rateStar)))
+	numReplicas = max(numReplicas, q.server.minNumReplicas)
 
 	// calculate cost
-	totalNumInstances := model.NumInstances(gName) * numReplicas
-	cost := acc.Cost() * float32(totalNumInstances)
+	totalNumInstances := q.model.NumInstances(gName) * numReplicas
+	cost := q.acc.Cost() * float32(totalNumInstances)
 
 	// analyze queue of one replica
-	rate := totalRate / float32(numReplicas)
-	metrics, err = queueAnalyzer.Analyze(rate)
+	rate := q.totalRate / float32(numReplicas)
+	metrics, err := q.queueAnalyzer.Analyze(rate)
 	if err != nil {
 		fmt.Println(err)
 		return nil
@@ -156,7 +186,7 @@ func CreateAllocation(serverName string, gName string) *Allocation {
 	ttft := metrics.AvgWaitTime + metrics.AvgPrefillTime
 	// fmt.Printf("numReplicas=%d; batchSize=%d; rate=%v, itl=%v; ttft=%v; \n", numReplicas, N, rate, itl, ttft)
 
-	alloc := &Allocation{accelerator: gName, numReplicas: numReplicas, batchSize: N,
+	alloc := &Allocation{accelerator: gName, numReplicas: numReplicas, batchSize: q.batchSize,
 		cost: cost, itl: itl, ttft: ttft, rho: rho, maxArrvRatePerReplica: rateStar / 1000}
 	alloc.SetValue(alloc.cost)
 	return alloc
@@ -234,6 +264,21 @@ func (a *Allocation) MaxRPM() float32 {
 	return a.maxArrvRatePerReplica * 1000 * 60
 }
 
+// Expected average token decode time (msec)
+func (a *Allocation) ITL() float32 {
+	return a.itl
+}
+
+// Expected average request queueing and prefill times (msec)
+func (a *Allocation) TTFT() float32 {
+	return a.ttft
+}
+
+// Expected average concurrently running requests / max batch size
+func (a *Allocation) Rho() float32 {
+	return a.rho
+}
+
 func (a *Allocation) Cost() float32 {
 	return a.cost
 }
@@ -268,8 +313,8 @@ func zeroLoadAllocation(server *Server, model *Model, acc *Accelerator, perf *co
 	}
 
 	maxBatchSize := perf.MaxBatchSize
-	if server.maxBatchSize > 0 {
-		maxBatchSize = server.maxBatchSize
+	if n := server.MaxBatchSize(gName); n > 0 {
+		maxBatchSize = n
 	}
 	totalNumInstances := model.NumInstances(gName) * numReplicas
 	cost := acc.Cost() * float32(totalNumInstances)
@@ -341,6 +386,9 @@ func (a *Allocation) String() string {
 		a.accelerator, a.numReplicas, a.batchSize, a.cost, a.value, a.itl, a.ttft, a.rho, a.MaxRPM())
 }
 
+// Accelerator of a missing allocation in an allocation difference
+const NoAccelerator = "none"
+
 // Orchestration difference between two allocations
 type AllocationDiff struct {
 	oldAccelerator string
@@ -354,8 +402,8 @@ func CreateAllocationDiff(a *Allocation, b *Allocation) *AllocationDiff {
 	if a == nil && b == nil {
 		return nil
 	}
-	oldAccelerator := "none"
-	newAccelerator := "none"
+	oldAccelerator := NoAccelerator
+	newAccelerator := NoAccelerator
 	oldNumReplicas := 0
 	newNumReplicas := 0
 	oldCost := float32(0)
@@ -379,6 +427,26 @@ func CreateAllocationDiff(a *Allocation, b *Allocation) *AllocationDiff {
 	}
 }
 
+func (d *AllocationDiff) OldAccelerator() string {
+	return d.oldAccelerator
+}
+
+func (d *AllocationDiff) NewAccelerator() string {
+	return d.newAccelerator
+}
+
+func (d *AllocationDiff) OldNumReplicas() int {
+	return d.oldNumReplicas
+}
+
+func (d *AllocationDiff) NewNumReplicas() int {
+	return d.newNumReplicas
+}
+
+func (d *AllocationDiff) CostDiff() float32 {
+	return d.costDiff
+}
+
 func (d *AllocationDiff) String() string {
 	var b bytes.Buffer
 	fmt.Fprintf(&b, "{ %s -> %s, %d -> %d, %v }",
@@ -127,6 +127,21 @@ func TestAllocation_Getters(t *testing.T) {
 			getter:   func() any { return alloc.MaxRPM() },
 			expected: float32(19793.814),
 		},
+		{
+			name:     "ITL",
+			getter:   func() any { return alloc.ITL() },
+			expected: alloc.itl,
+		},
+		{
+			name:     "TTFT",
+			getter:   func() any { return alloc.TTFT() },
+			expected: alloc.ttft,
+		},
+		{
+			name:     "Rho",
+			getter:   func() any { return alloc.Rho() },
+			expected: alloc.rho,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1080,6 +1095,44 @@ func TestZeroLoadAllocation(t *testing.T) {
 			wantBatchSize: 8,     // Override from server
 			wantCost:      100.0, // 50 * 2 instances * 1 replica
 		},
+		{
+			name: "with server max batch size override on the accelerator",
+			server: &Server{
+				minNumReplicas: 1,
+				maxBatchSize:   8,
+				accMaxBatchSize: map[string]int{
+					"test-gpu":  4,
+					"other-gpu": 2, // Should not apply to test-gpu
+				},
+			},
+			model: &Model{
+				name: "test-model",
+				numInstances: map[string]int{
+					"test-gpu": 1,
+				},
+			},
+			acc: &Accelerator{
+				name: "test-gpu",
+				spec: &config.AcceleratorSpec{
+					Cost: 50.0,
+				},
+			},
+			perf: &config.ModelAcceleratorPerfData{
+				MaxBatchSize: 16,
+				DecodeParms: config.DecodeParms{
+					Alpha: 3.0,
+					Beta:  1.0,
+				},
+				PrefillParms: config.PrefillParms{
+					Gamma: 8.0,
+					Delta: 2.0,
+				},
+			},
+			wantAccel:     "test-gpu",
+			wantReplicas:  1,
+			wantBatchSize: 4,    // Override from server on test-gpu
+			wantCost:      50.0, // 50 * 1 instance * 1 replica
+		},
 	}
 
 	for _, tt := range tests {
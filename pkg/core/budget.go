@@ -0,0 +1,106 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+)
+
+// Ceilings on the total cost of allocations; zero means unlimited
+type Budget struct {
+	Total      float32            // cluster-wide cost ceiling
+	Namespaces map[string]float32 // cost ceilings of namespaces
+}
+
+// Create a budget from spec
+func NewBudgetFromSpec(d *config.BudgetData) *Budget {
+	b := &Budget{
+		Total:      d.Total,
+		Namespaces: make(map[string]float32),
+	}
+	for _, nb := range d.Namespaces {
+		if nb.Namespace != "" && nb.Limit > 0 {
+			b.Namespaces[nb.Namespace] = nb.Limit
+		}
+	}
+	return b
+}
+
+// Check whether the budget limits any cost
+func (b *Budget) Limited() bool {
+	return b != nil && (b.Total > 0 || len(b.Namespaces) > 0)
+}
+
+// Cost of allocations against a budget ceiling
+type BudgetUsage struct {
+	Namespace string   // namespace of ceiling; empty for the cluster-wide ceiling
+	Limit     float32  // cost ceiling
+	Cost      float32  // total cost of allocations
+	Degraded  []string // names of servers allocated fewer replicas than needed to meet their SLO
+}
+
+// Remaining cost under the ceiling; negative if the ceiling is exceeded
+func (u *BudgetUsage) Headroom() float32 {
+	return u.Limit - u.Cost
+}
+
+func (u *BudgetUsage) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "namespace=%q, limit=%v, cost=%v, headroom=%v, degraded=%v",
+		u.Namespace, u.Limit, u.Cost, u.Headroom(), u.Degraded)
+	return b.String()
+}
+
+// Set budget from spec
+func (s *System) SetBudgetFromSpec(d *config.BudgetData) {
+	s.budget = NewBudgetFromSpec(d)
+}
+
+// Get cost budget
+func (s *System) Budget() *Budget {
+	return s.budget
+}
+
+// Cost of current allocations against the cluster-wide ceiling, if any, followed by the namespace ceilings in order
+func (s *System) BudgetUsage() []*BudgetUsage {
+	usage := make([]*BudgetUsage, 0)
+	if !s.budget.Limited() {
+		return usage
+	}
+	if s.budget.Total > 0 {
+		usage = append(usage, s.budgetUsage("", s.budget.Total))
+	}
+	namespaces := make([]string, 0, len(s.budget.Namespaces))
+	for ns := range s.budget.Namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	slices.Sort(namespaces)
+	for _, ns := range namespaces {
+		usage = append(usage, s.budgetUsage(ns, s.budget.Namespaces[ns]))
+	}
+	return usage
+}
+
+// Cost of current allocations of servers in a namespace (all servers if empty) against a ceiling
+func (s *System) budgetUsage(namespace string, limit float32) *BudgetUsage {
+	u := &BudgetUsage{
+		Namespace: namespace,
+		Limit:     limit,
+		Degraded:  make([]string, 0),
+	}
+	for serverName, server := range s.servers {
+		if namespace != "" && server.Namespace() != namespace {
+			continue
+		}
+		if alloc := server.Allocation(); alloc != nil {
+			u.Cost += alloc.Cost()
+		}
+		if server.RequiredReplicas() > 0 {
+			u.Degraded = append(u.Degraded, serverName)
+		}
+	}
+	slices.Sort(u.Degraded)
+	return u
+}
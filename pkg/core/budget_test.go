@@ -0,0 +1,88 @@
+package core
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+)
+
+func TestNewBudgetFromSpec(t *testing.T) {
+	budget := NewBudgetFromSpec(&config.BudgetData{
+		Total: 10,
+		Namespaces: []config.NamespaceBudget{
+			{Namespace: "ns1", Limit: 5},
+			{Namespace: "ns2", Limit: 0},
+			{Namespace: "", Limit: 3},
+		},
+	})
+	if budget.Total != 10 {
+		t.Errorf("Expected total 10, got %v", budget.Total)
+	}
+	if len(budget.Namespaces) != 1 || budget.Namespaces["ns1"] != 5 {
+		t.Errorf("Expected only the ns1 ceiling of 5, got %v", budget.Namespaces)
+	}
+	if !budget.Limited() {
+		t.Error("Expected budget to be limited")
+	}
+
+	if NewBudgetFromSpec(&config.BudgetData{}).Limited() {
+		t.Error("Expected empty budget to be unlimited")
+	}
+	var nilBudget *Budget
+	if nilBudget.Limited() {
+		t.Error("Expected nil budget to be unlimited")
+	}
+}
+
+func TestSystem_BudgetUsage(t *testing.T) {
+	system := NewSystem()
+	TheSystem = system
+	if usage := system.BudgetUsage(); len(usage) != 0 {
+		t.Errorf("Expected no usage without budget, got %v", usage)
+	}
+
+	system.SetBudgetFromSpec(&config.BudgetData{
+		Total: 10,
+		Namespaces: []config.NamespaceBudget{
+			{Namespace: "ns2", Limit: 2},
+			{Namespace: "ns1", Limit: 5},
+		},
+	})
+	for _, spec := range []config.ServerSpec{
+		{Name: "s1", Namespace: "ns1", DesiredAlloc: config.AllocationData{NumReplicas: 2, Cost: 4}},
+		{Name: "s2", Namespace: "ns2", DesiredAlloc: config.AllocationData{NumReplicas: 1, Cost: 3}},
+		{Name: "s3", Namespace: "ns2"},
+	} {
+		system.AddServerFromSpec(spec)
+		if spec.DesiredAlloc.NumReplicas > 0 {
+			system.Server(spec.Name).SetAllocation(AllocationFromData(&spec.DesiredAlloc))
+		}
+	}
+	system.Server("s2").SetRequiredReplicas(2)
+
+	usage := system.BudgetUsage()
+	namespaces := make([]string, len(usage))
+	for i, u := range usage {
+		namespaces[i] = u.Namespace
+	}
+	if !slices.Equal(namespaces, []string{"", "ns1", "ns2"}) {
+		t.Fatalf("Expected cluster usage followed by ns1 and ns2, got %v", namespaces)
+	}
+
+	tests := []struct {
+		cost     float32
+		headroom float32
+		degraded []string
+	}{
+		{cost: 7, headroom: 3, degraded: []string{"s2"}},
+		{cost: 4, headroom: 1, degraded: []string{}},
+		{cost: 3, headroom: -1, degraded: []string{"s2"}},
+	}
+	for i, tt := range tests {
+		if usage[i].Cost != tt.cost || usage[i].Headroom() != tt.headroom || !slices.Equal(usage[i].Degraded, tt.degraded) {
+			t.Errorf("usage %q: got %v, expected cost %v, headroom %v, degraded %v",
+				usage[i].Namespace, usage[i], tt.cost, tt.headroom, tt.degraded)
+		}
+	}
+}
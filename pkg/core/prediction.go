@@ -0,0 +1,81 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Performance predicted for a number of replicas of a server on an accelerator, under the current load of the server
+type Prediction struct {
+	NumReplicas int
+	ITL         float32 // average token decode time (msec)
+	TTFT        float32 // average request queueing and prefill times (msec)
+	Rho         float32 // utilization of a replica
+	Overload    float32 // fraction of the load exceeding the maximum rate the replicas can accept, in [0, 1]
+
+	// fraction of the load exceeding the maximum rate the replicas can serve within the SLO, in [0, 1]
+	SLODeficit float32
+	// relative excess of the predicted ITL or TTFT, whichever is larger, over its SLO target, in [0, 1]
+	LatencyViolation float32
+}
+
+// Squared violation of the SLO, adding the squares of the fraction of the load served beyond the SLO and of the
+// relative excess of latencies over their targets
+func (p *Prediction) SquaredViolation() float32 {
+	return p.SLODeficit*p.SLODeficit + p.LatencyViolation*p.LatencyViolation
+}
+
+func (p *Prediction) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "numReplicas=%d, itl=%v, ttft=%v, rho=%v, overload=%v, sloDeficit=%v, latencyViolation=%v",
+		p.NumReplicas, p.ITL, p.TTFT, p.Rho, p.Overload, p.SLODeficit, p.LatencyViolation)
+	return b.String()
+}
+
+// Predict the performance of a number of replicas of a server on an accelerator; nil if not feasible.
+// If the load exceeds the maximum rate the replicas can accept, latencies are predicted at the maximum rate and the
+// excess load is counted as overload; with no replicas, all the load is.
+func PredictPerformance(serverName string, gName string, numReplicas int) *Prediction {
+	q := newReplicaQueue(serverName, gName)
+	if q == nil {
+		return nil
+	}
+	p := &Prediction{NumReplicas: numReplicas}
+
+	// no violation in case of zero traffic
+	if q.queueAnalyzer == nil {
+		return p
+	}
+	rateStar, err := q.maxRateWithinSLO()
+	if err != nil {
+		return nil
+	}
+
+	maxRate := q.queueAnalyzer.RateRange.Max
+	rate := maxRate
+	p.Overload = 1
+	p.SLODeficit = 1
+	if numReplicas > 0 {
+		rate = q.totalRate / float32(numReplicas)
+		p.Overload = max(1-maxRate/rate, 0)
+		p.SLODeficit = max(1-rateStar/rate, 0)
+		rate = min(rate, maxRate)
+	}
+	metrics, err := q.queueAnalyzer.Analyze(rate)
+	if err != nil {
+		return nil
+	}
+	p.ITL = metrics.AvgTokenTime
+	p.TTFT = metrics.AvgWaitTime + metrics.AvgPrefillTime
+	p.Rho = metrics.Rho
+	p.LatencyViolation = max(excess(p.ITL, q.target.ITL), excess(p.TTFT, q.target.TTFT))
+	return p
+}
+
+// Relative excess of a latency over its target, capped at 1; zero if within the target or there is no target
+func excess(latency, target float32) float32 {
+	if target <= 0 || latency <= target {
+		return 0
+	}
+	return min(latency/target-1, 1)
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+)
+
+func TestPredictPerformance(t *testing.T) {
+	setupCompleteTestSystem()
+
+	if p := PredictPerformance("test-server", "test-gpu", 1); p == nil || p.SLODeficit != 0 || p.Overload != 0 {
+		t.Errorf("Expected no deficit at zero load, got %v", p)
+	}
+	if p := PredictPerformance("test-server", "unknown-gpu", 1); p != nil {
+		t.Errorf("Expected nil prediction for unknown accelerator, got %v", p)
+	}
+
+	target := GetServiceClass("default").ModelTarget("test-model")
+	target.ITL, target.TTFT = 100, 2000
+	GetServer("test-server").SetLoad(&config.ServerLoadSpec{ArrivalRate: 600, AvgInTokens: 100, AvgOutTokens: 200})
+	alloc := CreateAllocation("test-server", "test-gpu")
+	if alloc == nil {
+		t.Fatal("CreateAllocation returned nil, setup may be incorrect")
+	}
+	needed := alloc.NumReplicas()
+	if needed < 2 {
+		t.Fatalf("Expected load to need several replicas, got %d", needed)
+	}
+
+	previous := PredictPerformance("test-server", "test-gpu", 0)
+	if previous == nil || previous.SLODeficit != 1 || previous.Overload != 1 {
+		t.Fatalf("Expected full deficit and overload without replicas, got %v", previous)
+	}
+	for n := 1; n <= needed; n++ {
+		p := PredictPerformance("test-server", "test-gpu", n)
+		if p == nil {
+			t.Fatalf("Expected prediction for %d replicas", n)
+		}
+		if p.SLODeficit > previous.SLODeficit || p.TTFT > previous.TTFT || p.LatencyViolation > previous.LatencyViolation {
+			t.Errorf("Expected deficit, TTFT and latency violation not to increase from %v to %v", previous, p)
+		}
+		if p.Overload > p.SLODeficit {
+			t.Errorf("Expected overload not to exceed deficit, got %v", p)
+		}
+		previous = p
+	}
+	if previous.SLODeficit != 0 || previous.LatencyViolation != 0 {
+		t.Errorf("Expected no deficit at the %d replicas needed to meet the SLO, got %v", needed, previous)
+	}
+	if p := PredictPerformance("test-server", "test-gpu", 1); p.LatencyViolation <= 0 {
+		t.Errorf("Expected latencies above their targets with one replica, got %v", p)
+	}
+	if previous.ITL != alloc.ITL() || previous.TTFT != alloc.TTFT() || previous.Rho != alloc.Rho() {
+		t.Errorf("Expected prediction %v to match allocation %v", previous, alloc)
+	}
+}
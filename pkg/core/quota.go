@@ -0,0 +1,45 @@
+package core
+
+import (
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+)
+
+// Reasons a server was not allocated its preferred allocation under limited capacity
+const (
+	CappedByCapacity = "capacity" // the accelerator type has too few units left in the system
+	CappedByQuota    = "quota"    // the namespace of the server has too few units of the accelerator type left in its quota
+)
+
+// Set accelerator quotas of namespaces from spec, replacing the quotas of the listed namespaces
+func (s *System) SetQuotasFromSpec(d *config.QuotaData) {
+	for _, q := range d.Spec {
+		if q.Namespace == "" {
+			continue
+		}
+		quota := make(map[string]int)
+		for _, count := range q.Count {
+			quota[count.Type] = max(count.Count, 0)
+		}
+		s.quotas[q.Namespace] = quota
+	}
+}
+
+// Get accelerator quotas, by namespace and accelerator type
+func (s *System) Quotas() map[string]map[string]int {
+	return s.quotas
+}
+
+// Get accelerator quota of a namespace for an accelerator type, and whether the type is limited by a quota
+func (s *System) Quota(namespace, accType string) (int, bool) {
+	count, exists := s.quotas[namespace][accType]
+	return count, exists
+}
+
+// Remove accelerator quota of a namespace
+func (s *System) RemoveQuota(namespace string) bool {
+	if _, exists := s.quotas[namespace]; !exists {
+		return false
+	}
+	delete(s.quotas, namespace)
+	return true
+}
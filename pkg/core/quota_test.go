@@ -0,0 +1,35 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+)
+
+func TestSystem_SetQuotasFromSpec(t *testing.T) {
+	system := NewSystem()
+	system.SetQuotasFromSpec(&config.QuotaData{Spec: []config.NamespaceQuota{
+		{Namespace: "ns1", Count: []config.AcceleratorCount{{Type: "A100", Count: 4}, {Type: "H100", Count: -1}}},
+		{Namespace: "", Count: []config.AcceleratorCount{{Type: "A100", Count: 2}}},
+	}})
+
+	if count, limited := system.Quota("ns1", "A100"); !limited || count != 4 {
+		t.Errorf("Quota(ns1, A100) = %d, %v, want 4, true", count, limited)
+	}
+	if count, limited := system.Quota("ns1", "H100"); !limited || count != 0 {
+		t.Errorf("Quota(ns1, H100) = %d, %v, want 0, true", count, limited)
+	}
+	if _, limited := system.Quota("ns1", "L40"); limited {
+		t.Error("Expected type without quota to be unlimited")
+	}
+	if _, limited := system.Quota("ns2", "A100"); limited {
+		t.Error("Expected namespace without quota to be unlimited")
+	}
+	if len(system.Quotas()) != 1 {
+		t.Errorf("Expected only the ns1 quota, got %v", system.Quotas())
+	}
+
+	if !system.RemoveQuota("ns1") || system.RemoveQuota("ns1") {
+		t.Error("Expected ns1 quota to be removed once")
+	}
+}
@@ -9,12 +9,16 @@ import (
 // A server for a service class and model
 type Server struct {
 	name             string
+	namespace        string
 	serviceClassName string
 	modelName        string
 	keepAccelerator  bool
 	minNumReplicas   int
 	maxBatchSize     int
 
+	// max batch size on given accelerators, overriding maxBatchSize
+	accMaxBatchSize map[string]int
+
 	// server load statistics
 	load *config.ServerLoadSpec
 
@@ -27,6 +31,12 @@ type Server struct {
 	// current allocation
 	curAllocation *Allocation
 
+	// number of replicas needed to meet the SLO, if the allocated solution was reduced to fit a cost budget
+	requiredReplicas int
+
+	// reason the preferred allocation was not allocated under limited capacity (CappedByCapacity, CappedByQuota)
+	cappedBy string
+
 	spec *config.ServerSpec
 }
 
@@ -38,12 +48,14 @@ func NewServerFromSpec(spec *config.ServerSpec) *Server {
 	}
 	return &Server{
 		name:             spec.Name,
+		namespace:        spec.Namespace,
 		serviceClassName: svcName,
 		modelName:        spec.Model,
 		load:             &ld,
 		keepAccelerator:  spec.KeepAccelerator,
 		minNumReplicas:   spec.MinNumReplicas,
 		maxBatchSize:     spec.MaxBatchSize,
+		accMaxBatchSize:  spec.AcceleratorMaxBatchSize,
 
 		allAllocations: map[string]*Allocation{},
 		curAllocation:  AllocationFromData(&spec.CurrentAlloc),
@@ -85,6 +97,40 @@ func (s *Server) Name() string {
 	return s.name
 }
 
+func (s *Server) Namespace() string {
+	return s.namespace
+}
+
+func (s *Server) MinNumReplicas() int {
+	return s.minNumReplicas
+}
+
+// Overriding value for the max batch size of the server on an accelerator; zero if not set
+func (s *Server) MaxBatchSize(accName string) int {
+	if n := s.accMaxBatchSize[accName]; n > 0 {
+		return n
+	}
+	return s.maxBatchSize
+}
+
+// Number of replicas needed to meet the SLO if the allocated solution was reduced to fit a cost budget; zero otherwise
+func (s *Server) RequiredReplicas() int {
+	return s.requiredReplicas
+}
+
+func (s *Server) SetRequiredReplicas(n int) {
+	s.requiredReplicas = n
+}
+
+// Reason the preferred allocation was not allocated under limited capacity; empty if it was
+func (s *Server) CappedBy() string {
+	return s.cappedBy
+}
+
+func (s *Server) SetCappedBy(reason string) {
+	s.cappedBy = reason
+}
+
 func (s *Server) ServiceClassName() string {
 	return s.serviceClassName
 }
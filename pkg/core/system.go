@@ -44,6 +44,14 @@ func GetCapacities() map[string]int {
 	return TheSystem.capacity
 }
 
+func GetBudget() *Budget {
+	return TheSystem.budget
+}
+
+func GetQuotas() map[string]map[string]int {
+	return TheSystem.quotas
+}
+
 // System comprising all accelerators, models, service classes, and servers
 type System struct {
 	accelerators   map[string]*Accelerator
@@ -52,6 +60,8 @@ type System struct {
 	servers        map[string]*Server
 
 	capacity           map[string]int               // available count of accelerator types
+	quotas             map[string]map[string]int    // maximum count of accelerator types by namespace
+	budget             *Budget                      // ceilings on total cost of allocations
 	allocationByType   map[string]*AllocationByType // number of allocated accelerator types
 	allocationSolution *config.AllocationSolution
 }
@@ -73,6 +83,8 @@ func NewSystem() *System {
 		servers:        make(map[string]*Server),
 
 		capacity:           make(map[string]int),
+		quotas:             make(map[string]map[string]int),
+		budget:             NewBudgetFromSpec(&config.BudgetData{}),
 		allocationByType:   make(map[string]*AllocationByType),
 		allocationSolution: nil,
 	}
@@ -85,6 +97,8 @@ func (s *System) SetFromSpec(d *config.SystemSpec) *config.OptimizerSpec {
 	s.SetServiceClassesFromSpec(&d.ServiceClasses)
 	s.SetServersFromSpec(&d.Servers)
 	s.SetCapacityFromSpec(&d.Capacity)
+	s.SetBudgetFromSpec(&d.Budget)
+	s.SetQuotasFromSpec(&d.Quotas)
 	return &d.Optimizer.Spec
 }
 
@@ -318,6 +332,11 @@ func (s *System) GenerateSolution() *config.AllocationSolution {
 	return &allocationSolution
 }
 
+// Get the allocation solution last generated; nil if none
+func (s *System) AllocationSolution() *config.AllocationSolution {
+	return s.allocationSolution
+}
+
 func (a *AllocationByType) String() string {
 	var b bytes.Buffer
 	fmt.Fprintf(&b, "name=%s, count=%d, limit=%d, cost=%v", a.name, a.count, a.limit, a.cost)
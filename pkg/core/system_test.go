@@ -1495,7 +1495,7 @@ func TestSystem_GenerateSolution(t *testing.T) {
 		t.Fatal("GenerateSolution should return a solution")
 	}
 
-	if system.allocationSolution != solution {
+	if system.allocationSolution != solution || system.AllocationSolution() != solution {
 		t.Error("System should store the generated solution")
 	}
 
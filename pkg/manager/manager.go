@@ -25,3 +25,8 @@ func (m *Manager) Optimize() error {
 	m.system.AllocateByType()
 	return nil
 }
+
+// Ordered steps moving servers from their current to their optimized allocations
+func (m *Manager) Plan() *solver.Plan {
+	return m.optimizer.Plan()
+}
@@ -0,0 +1,61 @@
+package planner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/manager"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/solver"
+)
+
+// Result of optimizing the allocations of a system offline
+type Result struct {
+	Solution         *config.AllocationSolution `json:"solution"`         // optimized allocations by server name
+	Plan             *solver.Plan               `json:"plan"`             // steps from the current allocations
+	SolutionTimeMsec int64                      `json:"solutionTimeMsec"` // time taken by the solver
+}
+
+// Read system data in JSON, rejecting unknown fields
+func ReadSystemData(r io.Reader) (*config.SystemData, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	data := &config.SystemData{}
+	if err := decoder.Decode(data); err != nil {
+		return nil, fmt.Errorf("invalid system data: %w", err)
+	}
+	return data, nil
+}
+
+// Optimize the allocations of the servers of the system data, as the controller does in an optimization cycle
+func Optimize(data *config.SystemData) (*Result, error) {
+	system := core.NewSystem()
+	optimizerSpec := system.SetFromSpec(&data.Spec)
+	optimizer := solver.NewOptimizerFromSpec(optimizerSpec)
+	m := manager.NewManager(system, optimizer)
+
+	system.Calculate()
+	if err := m.Optimize(); err != nil {
+		return nil, err
+	}
+	return &Result{
+		Solution:         system.GenerateSolution(),
+		Plan:             m.Plan(),
+		SolutionTimeMsec: optimizer.SolutionTimeMsec(),
+	}, nil
+}
+
+// Deep copy of system data
+func Clone(data *config.SystemData) (*config.SystemData, error) {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	clone := &config.SystemData{}
+	if err := json.Unmarshal(bytes, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
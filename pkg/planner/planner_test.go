@@ -0,0 +1,171 @@
+package planner
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/solver"
+)
+
+const exampleSystemPath = "../../deploy/examples/offline-planning/system.json"
+
+func readExample(t *testing.T) *config.SystemData {
+	t.Helper()
+	file, err := os.Open(exampleSystemPath)
+	if err != nil {
+		t.Fatalf("failed to open example system: %v", err)
+	}
+	defer file.Close() //nolint:errcheck
+	data, err := ReadSystemData(file)
+	if err != nil {
+		t.Fatalf("ReadSystemData() error = %v", err)
+	}
+	return data
+}
+
+func TestReadSystemData(t *testing.T) {
+	if _, err := ReadSystemData(strings.NewReader(`{"system": {"serverData": {"servers": []}}}`)); err != nil {
+		t.Errorf("ReadSystemData() error = %v", err)
+	}
+	if _, err := ReadSystemData(strings.NewReader(`{"system": {"severData": {}}}`)); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if _, err := ReadSystemData(strings.NewReader(`{"system": `)); err == nil {
+		t.Error("Expected error for truncated data")
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	data := readExample(t)
+	result, err := Optimize(data)
+	if err != nil {
+		t.Fatalf("Optimize() error = %v", err)
+	}
+	if len(result.Solution.Spec) != len(data.Spec.Servers.Spec) {
+		t.Errorf("Expected an allocation for each of the %d servers, got %v", len(data.Spec.Servers.Spec), result.Solution.Spec)
+	}
+	if result.Plan == nil {
+		t.Fatal("Expected a plan")
+	}
+	for _, step := range result.Plan.Steps {
+		if step.Action == solver.ActionScaleUp && step.ToReplicas != result.Solution.Spec[step.ServerName].NumReplicas {
+			t.Errorf("Expected scale up to the allocated replicas, got %v", &step)
+		}
+	}
+
+	// the input is not modified, so optimizing again gives the same solution
+	again, err := Optimize(data)
+	if err != nil {
+		t.Fatalf("Optimize() error = %v", err)
+	}
+	for name, alloc := range result.Solution.Spec {
+		if other := again.Solution.Spec[name]; other.Accelerator != alloc.Accelerator || other.NumReplicas != alloc.NumReplicas {
+			t.Errorf("Expected same allocation of %s, got %v and %v", name, alloc, other)
+		}
+	}
+}
+
+func TestScaleArrivalRate(t *testing.T) {
+	data := readExample(t)
+	rates := make(map[string]float32)
+	for _, server := range data.Spec.Servers.Spec {
+		rates[server.Name] = server.CurrentAlloc.Load.ArrivalRate
+	}
+
+	scaled, err := ScaleArrivalRate(data, 2, "")
+	if err != nil {
+		t.Fatalf("ScaleArrivalRate() error = %v", err)
+	}
+	for i, server := range scaled.Spec.Servers.Spec {
+		if server.CurrentAlloc.Load.ArrivalRate != 2*rates[server.Name] {
+			t.Errorf("Expected rate of %s doubled, got %v", server.Name, server.CurrentAlloc.Load.ArrivalRate)
+		}
+		if data.Spec.Servers.Spec[i].CurrentAlloc.Load.ArrivalRate != rates[server.Name] {
+			t.Errorf("Expected input rate of %s unchanged", server.Name)
+		}
+	}
+
+	one := data.Spec.Servers.Spec[0].Name
+	scaled, err = ScaleArrivalRate(data, 0.5, one)
+	if err != nil {
+		t.Fatalf("ScaleArrivalRate() error = %v", err)
+	}
+	for _, server := range scaled.Spec.Servers.Spec {
+		want := rates[server.Name]
+		if server.Name == one {
+			want /= 2
+		}
+		if server.CurrentAlloc.Load.ArrivalRate != want {
+			t.Errorf("Expected rate of %s to be %v, got %v", server.Name, want, server.CurrentAlloc.Load.ArrivalRate)
+		}
+	}
+
+	if _, err := ScaleArrivalRate(data, 2, "unknown"); err == nil {
+		t.Error("Expected error for unknown server")
+	}
+	if _, err := ScaleArrivalRate(data, -1, ""); err == nil {
+		t.Error("Expected error for negative factor")
+	}
+}
+
+func TestSwapAccelerator(t *testing.T) {
+	data := &config.SystemData{Spec: config.SystemSpec{
+		Accelerators: config.AcceleratorData{Spec: []config.AcceleratorSpec{
+			{Name: "A100", Type: "A100", Multiplicity: 1},
+			{Name: "2xA100", Type: "A100", Multiplicity: 2},
+			{Name: "H100", Type: "H100", Multiplicity: 1},
+		}},
+		Models: config.ModelData{PerfData: []config.ModelAcceleratorPerfData{
+			{Name: "m", Acc: "A100"},
+			{Name: "m", Acc: "2xA100"},
+			{Name: "m", Acc: "H100"},
+		}},
+		Servers: config.ServerData{Spec: []config.ServerSpec{
+			{Name: "s", Model: "m", CurrentAlloc: config.AllocationData{Accelerator: "2xA100", NumReplicas: 1}},
+		}},
+		Capacity: config.CapacityData{Count: []config.AcceleratorCount{{Type: "A100", Count: 8}, {Type: "H100", Count: 2}}},
+		Quotas: config.QuotaData{Spec: []config.NamespaceQuota{
+			{Namespace: "ns1", Count: []config.AcceleratorCount{{Type: "A100", Count: 4}, {Type: "H100", Count: 1}}},
+			{Namespace: "ns2", Count: []config.AcceleratorCount{{Type: "A100", Count: 2}}},
+		}},
+	}}
+
+	// another accelerator of the type remains, so the capacity stays
+	swapped, err := SwapAccelerator(data, "2xA100", "H100")
+	if err != nil {
+		t.Fatalf("SwapAccelerator() error = %v", err)
+	}
+	if len(swapped.Spec.Accelerators.Spec) != 2 || len(swapped.Spec.Models.PerfData) != 2 {
+		t.Errorf("Expected 2xA100 and its performance data removed, got %v", swapped.Spec)
+	}
+	if swapped.Spec.Servers.Spec[0].CurrentAlloc.Accelerator != "H100" {
+		t.Errorf("Expected current allocation moved to H100, got %v", swapped.Spec.Servers.Spec[0].CurrentAlloc)
+	}
+	if got := swapped.Spec.Capacity.Count; len(got) != 2 || got[0].Count != 8 || got[1].Count != 2 {
+		t.Errorf("Expected capacity unchanged, got %v", got)
+	}
+	if data.Spec.Servers.Spec[0].CurrentAlloc.Accelerator != "2xA100" || len(data.Spec.Accelerators.Spec) != 3 {
+		t.Error("Expected input unchanged")
+	}
+
+	// the last accelerator of the type moves its capacity and the quotas limiting the new type
+	swapped, err = SwapAccelerator(swapped, "A100", "H100")
+	if err != nil {
+		t.Fatalf("SwapAccelerator() error = %v", err)
+	}
+	if got := swapped.Spec.Capacity.Count; len(got) != 1 || got[0] != (config.AcceleratorCount{Type: "H100", Count: 10}) {
+		t.Errorf("Expected A100 capacity moved to H100, got %v", got)
+	}
+	if got := swapped.Spec.Quotas.Spec[0].Count; len(got) != 1 || got[0] != (config.AcceleratorCount{Type: "H100", Count: 5}) {
+		t.Errorf("Expected ns1 A100 quota moved to H100, got %v", got)
+	}
+	if got := swapped.Spec.Quotas.Spec[1].Count; len(got) != 0 {
+		t.Errorf("Expected ns2 A100 quota removed, leaving H100 unlimited, got %v", got)
+	}
+
+	if _, err := SwapAccelerator(data, "A100", "unknown"); err == nil {
+		t.Error("Expected error for unknown accelerator")
+	}
+}
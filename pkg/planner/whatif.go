@@ -0,0 +1,99 @@
+package planner
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+)
+
+// Copy of system data with the arrival rates of servers scaled by a factor; all servers if serverName is empty
+func ScaleArrivalRate(data *config.SystemData, factor float32, serverName string) (*config.SystemData, error) {
+	if factor < 0 {
+		return nil, fmt.Errorf("negative arrival rate factor %v", factor)
+	}
+	scaled, err := Clone(data)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for i := range scaled.Spec.Servers.Spec {
+		server := &scaled.Spec.Servers.Spec[i]
+		if serverName != "" && server.Name != serverName {
+			continue
+		}
+		server.CurrentAlloc.Load.ArrivalRate *= factor
+		found = true
+	}
+	if serverName != "" && !found {
+		return nil, fmt.Errorf("server %s not found", serverName)
+	}
+	return scaled, nil
+}
+
+// Copy of system data with an accelerator replaced by another: the accelerator and the performance data of models
+// on it are removed, current and desired allocations on it are moved to the other accelerator, and, unless other
+// accelerators are of the same type, the capacity of its type is moved to the type of the other, as are quotas of
+// namespaces also limiting the type of the other (those not limiting it leave it unlimited)
+func SwapAccelerator(data *config.SystemData, from, to string) (*config.SystemData, error) {
+	swapped, err := Clone(data)
+	if err != nil {
+		return nil, err
+	}
+	accelerators := swapped.Spec.Accelerators.Spec
+	fromIndex := slices.IndexFunc(accelerators, func(a config.AcceleratorSpec) bool { return a.Name == from })
+	toIndex := slices.IndexFunc(accelerators, func(a config.AcceleratorSpec) bool { return a.Name == to })
+	if fromIndex < 0 {
+		return nil, fmt.Errorf("accelerator %s not found", from)
+	}
+	if toIndex < 0 {
+		return nil, fmt.Errorf("accelerator %s not found", to)
+	}
+	if from == to {
+		return swapped, nil
+	}
+	fromType, toType := accelerators[fromIndex].Type, accelerators[toIndex].Type
+
+	swapped.Spec.Accelerators.Spec = slices.Delete(accelerators, fromIndex, fromIndex+1)
+	swapped.Spec.Models.PerfData = slices.DeleteFunc(swapped.Spec.Models.PerfData, func(p config.ModelAcceleratorPerfData) bool {
+		return p.Acc == from
+	})
+	for i := range swapped.Spec.Servers.Spec {
+		server := &swapped.Spec.Servers.Spec[i]
+		if server.CurrentAlloc.Accelerator == from {
+			server.CurrentAlloc.Accelerator = to
+		}
+		if server.DesiredAlloc.Accelerator == from {
+			server.DesiredAlloc.Accelerator = to
+		}
+	}
+
+	typeInUse := slices.ContainsFunc(swapped.Spec.Accelerators.Spec, func(a config.AcceleratorSpec) bool {
+		return a.Type == fromType
+	})
+	if fromType != toType && !typeInUse {
+		swapped.Spec.Capacity.Count = moveCount(swapped.Spec.Capacity.Count, fromType, toType, true)
+		for i := range swapped.Spec.Quotas.Spec {
+			quota := &swapped.Spec.Quotas.Spec[i]
+			quota.Count = moveCount(quota.Count, fromType, toType, false)
+		}
+	}
+	return swapped, nil
+}
+
+// Add the count of an accelerator type to that of another, removing the former; a missing count of the other type
+// is added only if requested
+func moveCount(counts []config.AcceleratorCount, fromType, toType string, addMissing bool) []config.AcceleratorCount {
+	fromIndex := slices.IndexFunc(counts, func(c config.AcceleratorCount) bool { return c.Type == fromType })
+	if fromIndex < 0 {
+		return counts
+	}
+	count := counts[fromIndex].Count
+	counts = slices.Delete(counts, fromIndex, fromIndex+1)
+	if toIndex := slices.IndexFunc(counts, func(c config.AcceleratorCount) bool { return c.Type == toType }); toIndex >= 0 {
+		counts[toIndex].Count += count
+	} else if addMissing {
+		counts = append(counts, config.AcceleratorCount{Type: toType, Count: count})
+	}
+	return counts
+}
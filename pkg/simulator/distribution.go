@@ -0,0 +1,112 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Distribution of a non-negative random quantity: request inter-arrival times, or numbers of tokens
+type Distribution interface {
+	// draw a value
+	Sample(rng *rand.Rand) float64
+	// average value
+	Mean() float64
+}
+
+// Constant always takes the same value (deterministic arrivals, fixed request sizes)
+type Constant struct {
+	Value float64
+}
+
+func (d Constant) Sample(rng *rand.Rand) float64 {
+	return d.Value
+}
+
+func (d Constant) Mean() float64 {
+	return d.Value
+}
+
+func (d Constant) String() string {
+	return fmt.Sprintf("Constant(%v)", d.Value)
+}
+
+// Exponential has a coefficient of variation of one (Poisson arrivals)
+type Exponential struct {
+	Average float64
+}
+
+func (d Exponential) Sample(rng *rand.Rand) float64 {
+	return rng.ExpFloat64() * d.Average
+}
+
+func (d Exponential) Mean() float64 {
+	return d.Average
+}
+
+func (d Exponential) String() string {
+	return fmt.Sprintf("Exponential(%v)", d.Average)
+}
+
+// Uniform takes values evenly spread between Min and Max
+type Uniform struct {
+	Min float64
+	Max float64
+}
+
+func (d Uniform) Sample(rng *rand.Rand) float64 {
+	return d.Min + rng.Float64()*(d.Max-d.Min)
+}
+
+func (d Uniform) Mean() float64 {
+	return (d.Min + d.Max) / 2
+}
+
+func (d Uniform) String() string {
+	return fmt.Sprintf("Uniform(%v, %v)", d.Min, d.Max)
+}
+
+// Gamma has a given average and coefficient of variation (standard deviation over average):
+// below one is more regular than Poisson (Erlang), above one is bursty
+type Gamma struct {
+	Average float64
+	CV      float64
+}
+
+func (d Gamma) Sample(rng *rand.Rand) float64 {
+	if d.CV <= 0 {
+		return d.Average
+	}
+	shape := 1 / (d.CV * d.CV)
+	return sampleGamma(rng, shape) * d.Average / shape
+}
+
+func (d Gamma) Mean() float64 {
+	return d.Average
+}
+
+func (d Gamma) String() string {
+	return fmt.Sprintf("Gamma(%v, cv=%v)", d.Average, d.CV)
+}
+
+// draw from a gamma distribution with unit scale (Marsaglia and Tsang)
+func sampleGamma(rng *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		// boost the shape above one, then scale back down
+		return sampleGamma(rng, shape+1) * math.Pow(rng.Float64(), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,395 @@
+package simulator
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/analyzer"
+)
+
+// Scheduling of prefill and decode in the batch
+type Scheduling int
+
+const (
+	// Every request in the batch progresses on its own: its prefill takes gamma + delta * inputTokens * batchSize
+	// and each of its decode steps alpha + beta * batchSize, for the batch size when the step starts.
+	// These are the assumptions of the queueing model, without its exponential service times.
+	Overlapped Scheduling = iota
+	// The server runs iterations. If some requests joined the batch, the iteration prefills them together,
+	// delaying the decoding of the others, in gamma + delta * inputTokens; otherwise it decodes one token for
+	// every request in the batch, in alpha + beta * batchSize. Requests join the batch between iterations.
+	// This is how vLLM batches without chunked prefill.
+	PrefillFirst
+)
+
+func (s Scheduling) String() string {
+	switch s {
+	case Overlapped:
+		return "Overlapped"
+	case PrefillFirst:
+		return "PrefillFirst"
+	}
+	return fmt.Sprintf("Scheduling(%d)", int(s))
+}
+
+// Workload offered to the server
+type Workload struct {
+	InterArrival Distribution // time between consecutive request arrivals (msec)
+	InputTokens  Distribution // number of input tokens per request (rounded, at least zero)
+	OutputTokens Distribution // number of output tokens per request (rounded, at least one)
+}
+
+// Options of a simulation run
+type Options struct {
+	NumRequests int   // number of requests arriving during measurement
+	Warmup      int   // number of requests arriving before measurement starts
+	Seed        int64 // seed of the random number generator, runs with the same seed are identical
+}
+
+// Summary of the distribution of a latency over requests (msec)
+type Summary struct {
+	Mean float64
+	P50  float64
+	P90  float64
+	P99  float64
+	Max  float64
+}
+
+// Result of a simulation run, over the requests arriving during measurement
+type Result struct {
+	NumCompleted   int     // number of requests served
+	NumRejected    int     // number of requests rejected as the queue was full
+	Throughput     float64 // completed requests per second
+	AvgNumInServ   float64 // time average number of requests in the batch
+	AvgQueueLength float64 // time average number of requests waiting to join the batch
+	TTFT           Summary // time to first token: waiting, then prefill
+	ITL            Summary // inter-token latency, averaged over the output tokens of each request
+	WaitTime       Summary // time waiting to join the batch
+	RespTime       Summary // time from arrival to the last token
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("{completed=%d, rejected=%d, throughput=%.3f, numInServ=%.3f, queueLength=%.3f, "+
+		"ttft=%.3f, itl=%.3f, wait=%.3f, resp=%.3f}",
+		r.NumCompleted, r.NumRejected, r.Throughput, r.AvgNumInServ, r.AvgQueueLength,
+		r.TTFT.Mean, r.ITL.Mean, r.WaitTime.Mean, r.RespTime.Mean)
+}
+
+// Poisson returns exponential inter-arrival times for a request rate (requests/sec)
+func Poisson(requestRate float64) Distribution {
+	return Exponential{Average: 1000 / requestRate}
+}
+
+// Simulator of a continuous batching server, with the configuration and service parameters of the
+// queueing model of the analyzer. Waiting requests join the batch first come, first served, while
+// there is room; prefill produces the first output token and each decode step one more.
+// Requests arriving while the batch and the queue are full are rejected.
+type Simulator struct {
+	maxBatchSize int
+	maxQueueSize int
+	serviceParms *analyzer.ServiceParms
+	scheduling   Scheduling
+}
+
+// NewSimulator creates a simulator of a server with the same configuration as the queueing model
+func NewSimulator(config *analyzer.Configuration, scheduling Scheduling) (*Simulator, error) {
+	if config == nil || config.MaxBatchSize <= 0 || config.MaxQueueSize < 0 || config.ServiceParms == nil ||
+		config.ServiceParms.Prefill == nil || config.ServiceParms.Decode == nil {
+		return nil, fmt.Errorf("invalid configuration %v", config)
+	}
+	if decode := config.ServiceParms.Decode; decode.Alpha < 0 || decode.Beta < 0 || decode.Alpha+decode.Beta <= 0 {
+		return nil, fmt.Errorf("invalid decode parameters %v", decode)
+	}
+	if prefill := config.ServiceParms.Prefill; prefill.Gamma < 0 || prefill.Delta < 0 {
+		return nil, fmt.Errorf("invalid prefill parameters %v", prefill)
+	}
+	if scheduling != Overlapped && scheduling != PrefillFirst {
+		return nil, fmt.Errorf("invalid scheduling %v", scheduling)
+	}
+	return &Simulator{
+		maxBatchSize: config.MaxBatchSize,
+		maxQueueSize: config.MaxQueueSize,
+		serviceParms: config.ServiceParms,
+		scheduling:   scheduling,
+	}, nil
+}
+
+type request struct {
+	arrival    float64
+	admission  float64
+	firstToken float64
+	stepEnd    float64 // end of the current prefill or decode step (overlapped scheduling)
+	inTokens   int
+	outTokens  int
+	generated  int
+	measured   bool
+}
+
+// run state
+type state struct {
+	sim         *Simulator
+	workload    *Workload
+	rng         *rand.Rand
+	warmup      int
+	lastArrival int // index of the first request arriving after measurement
+
+	now         float64
+	nextArrival float64
+	arrived     int
+	queue       []*request
+	numInServ   int
+
+	// measurement window, from the arrival of the first measured request to that of the first request after
+	measuring   bool
+	windowStart float64
+	windowEnd   float64
+	areaInServ  float64
+	areaQueue   float64
+	pending     int // measured requests not yet completed
+
+	// latencies of completed measured requests
+	ttft     []float64
+	itl      []float64
+	wait     []float64
+	resp     []float64
+	rejected int
+}
+
+// Run simulates the server under a workload
+func (sim *Simulator) Run(workload *Workload, options *Options) (*Result, error) {
+	if workload == nil || workload.InterArrival == nil || workload.InputTokens == nil || workload.OutputTokens == nil {
+		return nil, fmt.Errorf("invalid workload %v", workload)
+	}
+	if workload.InterArrival.Mean() <= 0 {
+		return nil, fmt.Errorf("invalid inter-arrival time %v", workload.InterArrival)
+	}
+	if options == nil || options.NumRequests <= 0 || options.Warmup < 0 {
+		return nil, fmt.Errorf("invalid options %v", options)
+	}
+	s := &state{
+		sim:         sim,
+		workload:    workload,
+		rng:         rand.New(rand.NewSource(options.Seed)),
+		warmup:      options.Warmup,
+		lastArrival: options.Warmup + options.NumRequests,
+	}
+	s.nextArrival = workload.InterArrival.Sample(s.rng)
+	switch sim.scheduling {
+	case PrefillFirst:
+		s.runPrefillFirst()
+	default:
+		s.runOverlapped()
+	}
+	return s.result(), nil
+}
+
+// done is true once measured requests completed, and the first request after them arrived, so that they
+// all saw the load of the workload and not that of a draining server
+func (s *state) done() bool {
+	return s.arrived > s.lastArrival && s.pending == 0
+}
+
+// advance moves the clock, accumulating time averages
+func (s *state) advance(t float64) {
+	if s.measuring {
+		s.areaInServ += float64(s.numInServ) * (t - s.now)
+		s.areaQueue += float64(len(s.queue)) * (t - s.now)
+	}
+	s.now = t
+}
+
+// arrive queues the next arriving request, or rejects it if the server is full
+func (s *state) arrive() {
+	s.advance(s.nextArrival)
+	index := s.arrived
+	s.arrived++
+	switch index {
+	case s.warmup:
+		s.measuring = true
+		s.windowStart = s.now
+	case s.lastArrival:
+		s.measuring = false
+		s.windowEnd = s.now
+	}
+	req := &request{
+		arrival:   s.now,
+		inTokens:  max(int(math.Round(s.workload.InputTokens.Sample(s.rng))), 0),
+		outTokens: max(int(math.Round(s.workload.OutputTokens.Sample(s.rng))), 1),
+		measured:  index >= s.warmup && index < s.lastArrival,
+	}
+	if s.numInServ+len(s.queue) >= s.sim.maxBatchSize+s.sim.maxQueueSize {
+		if req.measured {
+			s.rejected++
+		}
+	} else {
+		s.queue = append(s.queue, req)
+		if req.measured {
+			s.pending++
+		}
+	}
+	s.nextArrival = s.now + s.workload.InterArrival.Sample(s.rng)
+}
+
+// join moves waiting requests to the batch while there is room
+func (s *state) join() []*request {
+	numJoining := min(s.sim.maxBatchSize-s.numInServ, len(s.queue))
+	joining := slices.Clone(s.queue[:numJoining])
+	for _, req := range joining {
+		req.admission = s.now
+	}
+	s.queue = s.queue[numJoining:]
+	s.numInServ += numJoining
+	return joining
+}
+
+// generate produces a token of a request, returning true if it was the last one
+func (s *state) generate(req *request) bool {
+	req.generated++
+	if req.generated == 1 {
+		req.firstToken = s.now
+	}
+	if req.generated < req.outTokens {
+		return false
+	}
+	s.numInServ--
+	if req.measured {
+		s.ttft = append(s.ttft, req.firstToken-req.arrival)
+		if req.outTokens > 1 {
+			s.itl = append(s.itl, (s.now-req.firstToken)/float64(req.outTokens-1))
+		}
+		s.wait = append(s.wait, req.admission-req.arrival)
+		s.resp = append(s.resp, s.now-req.arrival)
+		s.pending--
+	}
+	return true
+}
+
+func (s *state) runOverlapped() {
+	steps := &stepHeap{}
+	start := func(req *request) {
+		batchSize := float32(s.numInServ)
+		if req.generated == 0 {
+			req.stepEnd = s.now + float64(s.sim.serviceParms.Prefill.PrefillTime(req.inTokens, batchSize))
+		} else {
+			req.stepEnd = s.now + float64(s.sim.serviceParms.Decode.DecodeTime(batchSize))
+		}
+		heap.Push(steps, req)
+	}
+	for !s.done() {
+		if steps.Len() == 0 || s.nextArrival < (*steps)[0].stepEnd {
+			s.arrive()
+		} else {
+			req := heap.Pop(steps).(*request)
+			s.advance(req.stepEnd)
+			if !s.generate(req) {
+				start(req)
+			}
+		}
+		for _, req := range s.join() {
+			start(req)
+		}
+	}
+}
+
+func (s *state) runPrefillFirst() {
+	batch := make([]*request, 0, s.sim.maxBatchSize)
+	for !s.done() {
+		if s.numInServ == 0 && len(s.queue) == 0 {
+			s.arrive()
+			continue
+		}
+		batch = append(batch, s.join()...)
+
+		// prefill requests that joined the batch, or else decode
+		iteration := make([]*request, 0)
+		numTokens := 0
+		for _, req := range batch {
+			if req.generated == 0 {
+				iteration = append(iteration, req)
+				numTokens += req.inTokens
+			}
+		}
+		var duration float32
+		if len(iteration) > 0 {
+			duration = s.sim.serviceParms.Prefill.PrefillTime(numTokens, 1)
+		} else {
+			iteration = slices.Clone(batch)
+			duration = s.sim.serviceParms.Decode.DecodeTime(float32(len(batch)))
+		}
+		end := s.now + float64(duration)
+		for s.nextArrival <= end {
+			s.arrive()
+		}
+		s.advance(end)
+
+		finished := make(map[*request]bool)
+		for _, req := range iteration {
+			if s.generate(req) {
+				finished[req] = true
+			}
+		}
+		batch = slices.DeleteFunc(batch, func(req *request) bool { return finished[req] })
+	}
+}
+
+// result of a run
+func (s *state) result() *Result {
+	window := s.windowEnd - s.windowStart
+	result := &Result{
+		NumCompleted: len(s.resp),
+		NumRejected:  s.rejected,
+		TTFT:         summarize(s.ttft),
+		ITL:          summarize(s.itl),
+		WaitTime:     summarize(s.wait),
+		RespTime:     summarize(s.resp),
+	}
+	if window > 0 {
+		result.Throughput = float64(len(s.resp)) / window * 1000
+		result.AvgNumInServ = s.areaInServ / window
+		result.AvgQueueLength = s.areaQueue / window
+	}
+	return result
+}
+
+// summarize returns the mean and percentiles of samples
+func summarize(samples []float64) Summary {
+	if len(samples) == 0 {
+		return Summary{}
+	}
+	slices.Sort(samples)
+	var sum float64
+	for _, v := range samples {
+		sum += v
+	}
+	percentile := func(p float64) float64 {
+		// nearest rank
+		rank := int(math.Ceil(p / 100 * float64(len(samples))))
+		return samples[max(rank-1, 0)]
+	}
+	return Summary{
+		Mean: sum / float64(len(samples)),
+		P50:  percentile(50),
+		P90:  percentile(90),
+		P99:  percentile(99),
+		Max:  samples[len(samples)-1],
+	}
+}
+
+// requests in the batch by end of their current step
+type stepHeap []*request
+
+func (h stepHeap) Len() int           { return len(h) }
+func (h stepHeap) Less(i, j int) bool { return h[i].stepEnd < h[j].stepEnd }
+func (h stepHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *stepHeap) Push(x any)        { *h = append(*h, x.(*request)) }
+func (h *stepHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,295 @@
+package simulator_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/analyzer"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/simulator"
+)
+
+// parameters of a llama 8B model on A100, as in the sample variant
+var testConfig = &analyzer.Configuration{
+	MaxBatchSize: 64,
+	MaxQueueSize: 128,
+	ServiceParms: &analyzer.ServiceParms{
+		Prefill: &analyzer.PrefillParms{Gamma: 200, Delta: 0.041},
+		Decode:  &analyzer.DecodeParms{Alpha: 20.58, Beta: 0.41},
+	},
+}
+
+var testRequestSize = &analyzer.RequestSize{AvgInputTokens: 512, AvgOutputTokens: 256}
+
+var testOptions = &simulator.Options{NumRequests: 5000, Warmup: 500, Seed: 1}
+
+func fixedSizeWorkload(requestRate float64, requestSize *analyzer.RequestSize) *simulator.Workload {
+	return &simulator.Workload{
+		InterArrival: simulator.Poisson(requestRate),
+		InputTokens:  simulator.Constant{Value: float64(requestSize.AvgInputTokens)},
+		OutputTokens: simulator.Constant{Value: float64(requestSize.AvgOutputTokens)},
+	}
+}
+
+func relativeError(got, want float64) float64 {
+	return math.Abs(got-want) / want
+}
+
+func TestNewSimulator(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     *analyzer.Configuration
+		scheduling simulator.Scheduling
+		wantErr    bool
+	}{
+		{
+			name:       "valid",
+			config:     testConfig,
+			scheduling: simulator.PrefillFirst,
+		},
+		{
+			name:    "nil configuration",
+			wantErr: true,
+		},
+		{
+			name:    "no batch",
+			config:  &analyzer.Configuration{MaxBatchSize: 0, ServiceParms: testConfig.ServiceParms},
+			wantErr: true,
+		},
+		{
+			name: "zero decode time",
+			config: &analyzer.Configuration{MaxBatchSize: 8, ServiceParms: &analyzer.ServiceParms{
+				Prefill: testConfig.ServiceParms.Prefill,
+				Decode:  &analyzer.DecodeParms{},
+			}},
+			wantErr: true,
+		},
+		{
+			name:       "unknown scheduling",
+			config:     testConfig,
+			scheduling: simulator.Scheduling(7),
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := simulator.NewSimulator(tt.config, tt.scheduling)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewSimulator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// With overlapped scheduling, the simulated server follows the assumptions of the queueing model, except
+// for its exponential service times, so that the predictions of the analyzer should match the simulation
+func TestOverlappedMatchesAnalyzer(t *testing.T) {
+	configs := []struct {
+		name        string
+		config      *analyzer.Configuration
+		requestSize *analyzer.RequestSize
+	}{
+		{
+			name:        "large batch",
+			config:      testConfig,
+			requestSize: testRequestSize,
+		},
+		{
+			name: "small batch",
+			config: &analyzer.Configuration{
+				MaxBatchSize: 8,
+				MaxQueueSize: 16,
+				ServiceParms: &analyzer.ServiceParms{
+					Prefill: &analyzer.PrefillParms{Gamma: 10, Delta: 0.001},
+					Decode:  &analyzer.DecodeParms{Alpha: 1, Beta: 0.01},
+				},
+			},
+			requestSize: &analyzer.RequestSize{AvgInputTokens: 200, AvgOutputTokens: 20},
+		},
+	}
+	const tolerance = 0.05
+	for _, c := range configs {
+		qa, err := analyzer.NewQueueAnalyzer(c.config, c.requestSize)
+		if err != nil {
+			t.Fatalf("NewQueueAnalyzer() error = %v", err)
+		}
+		sim, err := simulator.NewSimulator(c.config, simulator.Overlapped)
+		if err != nil {
+			t.Fatalf("NewSimulator() error = %v", err)
+		}
+		for _, load := range []float32{0.1, 0.3, 0.5, 0.7} {
+			requestRate := qa.RateRange.Max * load
+			metrics, err := qa.Analyze(requestRate)
+			if err != nil {
+				t.Fatalf("%s at load %v: Analyze() error = %v", c.name, load, err)
+			}
+			result, err := sim.Run(fixedSizeWorkload(float64(requestRate), c.requestSize), testOptions)
+			if err != nil {
+				t.Fatalf("%s at load %v: Run() error = %v", c.name, load, err)
+			}
+			t.Logf("%s at load %v: analyzer %v, simulator %v", c.name, load, metrics, result)
+
+			ttft := float64(metrics.AvgWaitTime + metrics.AvgPrefillTime)
+			checks := []struct {
+				name      string
+				got, want float64
+			}{
+				{"throughput", result.Throughput, float64(metrics.Throughput)},
+				{"ITL", result.ITL.Mean, float64(metrics.AvgTokenTime)},
+				{"number in service", result.AvgNumInServ, float64(metrics.AvgNumInServ)},
+			}
+			if load <= 0.5 {
+				checks = append(checks, struct {
+					name      string
+					got, want float64
+				}{"TTFT", result.TTFT.Mean, ttft})
+			} else if result.TTFT.Mean > ttft*(1+tolerance) {
+				// as queueing builds up, exponential service times overestimate it: the model is conservative
+				t.Errorf("%s at load %v: simulated TTFT %v above predicted %v", c.name, load, result.TTFT.Mean, ttft)
+			}
+			for _, check := range checks {
+				if relativeError(check.got, check.want) > tolerance {
+					t.Errorf("%s at load %v: simulated %s %v, predicted %v", c.name, load, check.name, check.got, check.want)
+				}
+			}
+		}
+	}
+}
+
+// Without chunked prefill, prefills stall decoding, making tokens slower than predicted by the queueing model
+func TestPrefillFirstStallsDecode(t *testing.T) {
+	qa, err := analyzer.NewQueueAnalyzer(testConfig, testRequestSize)
+	if err != nil {
+		t.Fatalf("NewQueueAnalyzer() error = %v", err)
+	}
+	sim, err := simulator.NewSimulator(testConfig, simulator.PrefillFirst)
+	if err != nil {
+		t.Fatalf("NewSimulator() error = %v", err)
+	}
+	var prevITL float64
+	for _, load := range []float32{0.1, 0.3, 0.5} {
+		requestRate := qa.RateRange.Max * load
+		metrics, err := qa.Analyze(requestRate)
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		result, err := sim.Run(fixedSizeWorkload(float64(requestRate), testRequestSize), testOptions)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		t.Logf("load %v: analyzer %v, simulator %v", load, metrics, result)
+		if result.NumRejected > 0 || relativeError(result.Throughput, float64(requestRate)) > 0.05 {
+			t.Errorf("load %v: expected all requests served, got %v", load, result)
+		}
+		if result.ITL.Mean < float64(metrics.AvgTokenTime) || result.ITL.Mean < prevITL {
+			t.Errorf("load %v: expected ITL above predicted %v and increasing with load, got %v",
+				load, metrics.AvgTokenTime, result.ITL.Mean)
+		}
+		prevITL = result.ITL.Mean
+	}
+}
+
+func TestRun(t *testing.T) {
+	sim, err := simulator.NewSimulator(testConfig, simulator.PrefillFirst)
+	if err != nil {
+		t.Fatalf("NewSimulator() error = %v", err)
+	}
+	workload := &simulator.Workload{
+		InterArrival: simulator.Gamma{Average: 500, CV: 2},
+		InputTokens:  simulator.Uniform{Min: 100, Max: 900},
+		OutputTokens: simulator.Exponential{Average: 200},
+	}
+	result, err := sim.Run(workload, testOptions)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.NumCompleted+result.NumRejected != testOptions.NumRequests {
+		t.Errorf("Expected %d measured requests, got %v", testOptions.NumRequests, result)
+	}
+	for name, summary := range map[string]simulator.Summary{
+		"TTFT": result.TTFT, "ITL": result.ITL, "wait": result.WaitTime, "response": result.RespTime,
+	} {
+		if summary.Mean <= 0 && name != "wait" || summary.P50 > summary.P90 || summary.P90 > summary.P99 ||
+			summary.P99 > summary.Max {
+			t.Errorf("Invalid %s summary %v", name, summary)
+		}
+	}
+	if result.TTFT.Mean < result.WaitTime.Mean || result.RespTime.Mean < result.TTFT.Mean {
+		t.Errorf("Expected wait <= TTFT <= response time, got %v", result)
+	}
+	// Little's law: number in service = throughput * time in service
+	inServ := result.Throughput * (result.RespTime.Mean - result.WaitTime.Mean) / 1000
+	if relativeError(result.AvgNumInServ, inServ) > 0.05 {
+		t.Errorf("Expected %v requests in service from Little's law, got %v", inServ, result.AvgNumInServ)
+	}
+
+	again, err := sim.Run(workload, testOptions)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if *again != *result {
+		t.Errorf("Expected the same result with the same seed, got %v and %v", result, again)
+	}
+}
+
+func TestRunOverloaded(t *testing.T) {
+	for _, scheduling := range []simulator.Scheduling{simulator.Overlapped, simulator.PrefillFirst} {
+		sim, err := simulator.NewSimulator(testConfig, scheduling)
+		if err != nil {
+			t.Fatalf("NewSimulator() error = %v", err)
+		}
+		result, err := sim.Run(fixedSizeWorkload(20, testRequestSize), testOptions)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		// time averages, up to rounding
+		if result.NumRejected == 0 || result.AvgNumInServ > float64(testConfig.MaxBatchSize)+1e-6 ||
+			result.AvgQueueLength > float64(testConfig.MaxQueueSize)+1e-6 {
+			t.Errorf("%v: expected rejections with a full batch and queue, got %v", scheduling, result)
+		}
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	sim, err := simulator.NewSimulator(testConfig, simulator.Overlapped)
+	if err != nil {
+		t.Fatalf("NewSimulator() error = %v", err)
+	}
+	if _, err := sim.Run(&simulator.Workload{InterArrival: simulator.Poisson(1)}, testOptions); err == nil {
+		t.Error("Expected error for workload without request sizes")
+	}
+	if _, err := sim.Run(fixedSizeWorkload(1, testRequestSize), &simulator.Options{}); err == nil {
+		t.Error("Expected error for no measured requests")
+	}
+}
+
+func TestDistributions(t *testing.T) {
+	tests := []struct {
+		dist   simulator.Distribution
+		wantCV float64
+	}{
+		{simulator.Constant{Value: 3}, 0},
+		{simulator.Exponential{Average: 3}, 1},
+		{simulator.Uniform{Min: 1, Max: 5}, (5 - 1) / math.Sqrt(12) / 3},
+		{simulator.Gamma{Average: 3, CV: 0.5}, 0.5},
+		{simulator.Gamma{Average: 3, CV: 2}, 2},
+	}
+	rng := rand.New(rand.NewSource(1))
+	const n = 200000
+	for _, tt := range tests {
+		var sum, sumSq float64
+		for range n {
+			v := tt.dist.Sample(rng)
+			if v < 0 {
+				t.Fatalf("%v: negative sample %v", tt.dist, v)
+			}
+			sum += v
+			sumSq += v * v
+		}
+		mean := sum / n
+		cv := math.Sqrt(max(sumSq/n-mean*mean, 0)) / mean
+		if relativeError(mean, tt.dist.Mean()) > 0.02 || math.Abs(cv-tt.wantCV) > 0.05*max(tt.wantCV, 1) {
+			t.Errorf("%v: expected mean %v and cv %v, got %v and %v", tt.dist, tt.dist.Mean(), tt.wantCV, mean, cv)
+		}
+	}
+}
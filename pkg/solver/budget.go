@@ -0,0 +1,118 @@
+package solver
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
+)
+
+// relative tolerance of cost comparisons against budget ceilings
+const budgetTolerance = 1e-6
+
+// Reduce allocations to fit the cost budget, namespace ceilings first, then the cluster-wide ceiling.
+// Replicas are removed one at a time from servers of the lowest priority service class, spreading the reduction
+// evenly among servers of the same priority, down to their minimum number of replicas. Servers allocated fewer
+// replicas than needed to meet their SLO record the number needed.
+func (s *Solver) EnforceBudget() {
+	for _, server := range core.GetServers() {
+		server.SetRequiredReplicas(0)
+	}
+	budget := core.GetBudget()
+	if !budget.Limited() {
+		return
+	}
+
+	namespaces := make([]string, 0, len(budget.Namespaces))
+	for ns := range budget.Namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	slices.Sort(namespaces)
+	for _, ns := range namespaces {
+		servers := make([]*core.Server, 0)
+		for _, server := range core.GetServers() {
+			if server.Namespace() == ns {
+				servers = append(servers, server)
+			}
+		}
+		fitBudget(servers, budget.Namespaces[ns])
+	}
+
+	if budget.Total > 0 {
+		servers := make([]*core.Server, 0, len(core.GetServers()))
+		for _, server := range core.GetServers() {
+			servers = append(servers, server)
+		}
+		fitBudget(servers, budget.Total)
+	}
+}
+
+// Remove replicas from a set of servers until the total cost of their allocations fits the limit, or all of them
+// are at their minimum number of replicas
+func fitBudget(servers []*core.Server, limit float32) {
+	cost := float32(0)
+	for _, server := range servers {
+		if alloc := server.Allocation(); alloc != nil {
+			cost += alloc.Cost()
+		}
+	}
+	for cost > limit*(1+budgetTolerance) {
+		server := nextToDegrade(servers)
+		if server == nil {
+			return
+		}
+		alloc := server.Allocation()
+		numReplicas := alloc.NumReplicas()
+		if server.RequiredReplicas() == 0 {
+			server.SetRequiredReplicas(numReplicas)
+		}
+
+		// adjust cost and value
+		reduced := alloc.Clone()
+		factor := float32(numReplicas-1) / float32(numReplicas)
+		reduced.SetCost(alloc.Cost() * factor)
+		reduced.SetValue(alloc.Value() * factor)
+		reduced.SetNumReplicas(numReplicas - 1)
+		server.SetAllocation(reduced)
+		cost -= alloc.Cost() - reduced.Cost()
+	}
+}
+
+// Select the server to remove a replica from: among servers above their minimum number of replicas, the one of
+// lowest priority (highest number) keeping the largest fraction of its required replicas after the removal, then the
+// largest cost per replica, then by name
+func nextToDegrade(servers []*core.Server) *core.Server {
+	candidates := make([]*core.Server, 0)
+	for _, server := range servers {
+		if alloc := server.Allocation(); alloc != nil && alloc.NumReplicas() > max(server.MinNumReplicas(), 0) {
+			candidates = append(candidates, server)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	fraction := func(server *core.Server) float64 {
+		numReplicas := server.Allocation().NumReplicas()
+		required := server.RequiredReplicas()
+		if required == 0 {
+			required = numReplicas
+		}
+		return float64(numReplicas-1) / float64(required)
+	}
+	costPerReplica := func(server *core.Server) float32 {
+		alloc := server.Allocation()
+		return alloc.Cost() / float32(alloc.NumReplicas())
+	}
+	return slices.MinFunc(candidates, func(a, b *core.Server) int {
+		if c := cmp.Compare(b.Priority(), a.Priority()); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(fraction(b), fraction(a)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(costPerReplica(b), costPerReplica(a)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name(), b.Name())
+	})
+}
@@ -0,0 +1,181 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
+)
+
+// Helper function to create a system of servers with the given allocations, costing 1 per replica
+func setupTestSystemForBudget(budget config.BudgetData, servers ...config.ServerSpec) {
+	setupTestSystemForGreedy()
+	for _, name := range []string{"server1", "server2", "server3"} {
+		_ = core.TheSystem.RemoveServer(name)
+	}
+	core.TheSystem.SetBudgetFromSpec(&budget)
+	for _, spec := range servers {
+		core.TheSystem.AddServerFromSpec(spec)
+		alloc := core.AllocationFromData(&config.AllocationData{
+			Accelerator: "A100",
+			NumReplicas: spec.DesiredAlloc.NumReplicas,
+			Cost:        float32(spec.DesiredAlloc.NumReplicas),
+		})
+		alloc.SetValue(alloc.Cost())
+		core.GetServer(spec.Name).SetAllocation(alloc)
+	}
+}
+
+func budgetServer(name, namespace, class string, numReplicas, minNumReplicas int) config.ServerSpec {
+	return config.ServerSpec{
+		Name:           name,
+		Namespace:      namespace,
+		Class:          class,
+		Model:          "llama-7b",
+		MinNumReplicas: minNumReplicas,
+		DesiredAlloc:   config.AllocationData{NumReplicas: numReplicas},
+	}
+}
+
+func allocatedReplicas(t *testing.T, name string) int {
+	t.Helper()
+	alloc := core.GetServer(name).Allocation()
+	if alloc == nil {
+		t.Fatalf("server %s has no allocation", name)
+	}
+	return alloc.NumReplicas()
+}
+
+func TestSolver_EnforceBudget(t *testing.T) {
+	tests := []struct {
+		name         string
+		budget       config.BudgetData
+		servers      []config.ServerSpec
+		wantReplicas map[string]int
+		wantRequired map[string]int
+	}{
+		{
+			name:   "no budget",
+			budget: config.BudgetData{},
+			servers: []config.ServerSpec{
+				budgetServer("high", "ns1", "high-priority", 4, 1),
+				budgetServer("low", "ns1", "low-priority", 4, 1),
+			},
+			wantReplicas: map[string]int{"high": 4, "low": 4},
+			wantRequired: map[string]int{"high": 0, "low": 0},
+		},
+		{
+			name:   "within budget",
+			budget: config.BudgetData{Total: 8},
+			servers: []config.ServerSpec{
+				budgetServer("high", "ns1", "high-priority", 4, 1),
+				budgetServer("low", "ns1", "low-priority", 4, 1),
+			},
+			wantReplicas: map[string]int{"high": 4, "low": 4},
+			wantRequired: map[string]int{"high": 0, "low": 0},
+		},
+		{
+			name:   "lowest priority degraded first",
+			budget: config.BudgetData{Total: 6},
+			servers: []config.ServerSpec{
+				budgetServer("high", "ns1", "high-priority", 4, 1),
+				budgetServer("low", "ns1", "low-priority", 4, 1),
+			},
+			wantReplicas: map[string]int{"high": 4, "low": 2},
+			wantRequired: map[string]int{"high": 0, "low": 4},
+		},
+		{
+			name:   "higher priority degraded once lower at minimum",
+			budget: config.BudgetData{Total: 4},
+			servers: []config.ServerSpec{
+				budgetServer("high", "ns1", "high-priority", 4, 1),
+				budgetServer("low", "ns1", "low-priority", 4, 1),
+			},
+			wantReplicas: map[string]int{"high": 3, "low": 1},
+			wantRequired: map[string]int{"high": 4, "low": 4},
+		},
+		{
+			name:   "even reduction within priority",
+			budget: config.BudgetData{Total: 8},
+			servers: []config.ServerSpec{
+				budgetServer("a", "ns1", "low-priority", 4, 0),
+				budgetServer("b", "ns1", "low-priority", 8, 0),
+			},
+			wantReplicas: map[string]int{"a": 3, "b": 5},
+			wantRequired: map[string]int{"a": 4, "b": 8},
+		},
+		{
+			name:   "budget infeasible at minimum replicas",
+			budget: config.BudgetData{Total: 1},
+			servers: []config.ServerSpec{
+				budgetServer("high", "ns1", "high-priority", 2, 1),
+				budgetServer("low", "ns1", "low-priority", 2, 1),
+			},
+			wantReplicas: map[string]int{"high": 1, "low": 1},
+			wantRequired: map[string]int{"high": 2, "low": 2},
+		},
+		{
+			name: "namespace budget",
+			budget: config.BudgetData{Namespaces: []config.NamespaceBudget{
+				{Namespace: "ns2", Limit: 2},
+			}},
+			servers: []config.ServerSpec{
+				budgetServer("high", "ns1", "high-priority", 4, 1),
+				budgetServer("low", "ns1", "low-priority", 4, 1),
+				budgetServer("other", "ns2", "high-priority", 4, 1),
+			},
+			wantReplicas: map[string]int{"high": 4, "low": 4, "other": 2},
+			wantRequired: map[string]int{"high": 0, "low": 0, "other": 4},
+		},
+		{
+			name: "namespace and cluster budgets",
+			budget: config.BudgetData{Total: 8, Namespaces: []config.NamespaceBudget{
+				{Namespace: "ns2", Limit: 2},
+			}},
+			servers: []config.ServerSpec{
+				budgetServer("high", "ns1", "high-priority", 4, 1),
+				budgetServer("low", "ns1", "low-priority", 4, 1),
+				budgetServer("other", "ns2", "high-priority", 4, 1),
+			},
+			wantReplicas: map[string]int{"high": 4, "low": 2, "other": 2},
+			wantRequired: map[string]int{"high": 0, "low": 4, "other": 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestSystemForBudget(tt.budget, tt.servers...)
+			NewSolver(&config.OptimizerSpec{}).EnforceBudget()
+			for name, want := range tt.wantReplicas {
+				if got := allocatedReplicas(t, name); got != want {
+					t.Errorf("server %s: replicas = %d, want %d", name, got, want)
+				}
+			}
+			for name, want := range tt.wantRequired {
+				if got := core.GetServer(name).RequiredReplicas(); got != want {
+					t.Errorf("server %s: required replicas = %d, want %d", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSolver_EnforceBudget_ScalesCost(t *testing.T) {
+	setupTestSystemForBudget(config.BudgetData{Total: 3}, budgetServer("low", "ns1", "low-priority", 4, 0))
+	NewSolver(&config.OptimizerSpec{}).EnforceBudget()
+
+	alloc := core.GetServer("low").Allocation()
+	if alloc.NumReplicas() != 3 || alloc.Cost() != 3 || alloc.Value() != 3 {
+		t.Errorf("allocation = %v, want 3 replicas of cost and value 3", alloc)
+	}
+	for _, candidate := range core.GetServer("low").AllAllocations() {
+		if candidate == alloc {
+			t.Error("reduced allocation should not alias a candidate allocation")
+		}
+	}
+
+	usage := core.TheSystem.BudgetUsage()
+	if len(usage) != 1 || usage[0].Cost != 3 || usage[0].Headroom() != 0 || len(usage[0].Degraded) != 1 {
+		t.Errorf("budget usage = %v, want cost 3, no headroom and one degraded server", usage)
+	}
+}
@@ -0,0 +1,166 @@
+package solver
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
+)
+
+// Weight of the SLO deficit of a server of a priority, decreasing linearly from the highest to the lowest priority
+func priorityWeight(priority int) float32 {
+	return float32(config.DefaultLowPriority - priority + 1)
+}
+
+type deficitTicket struct {
+	entry  *serverEntry
+	server *core.Server
+
+	alloc           *core.Allocation // candidate allocation, giving the number of replicas needed to meet the SLO
+	accType         string
+	unitsPerReplica int
+	weight          float32
+
+	numReplicas int
+	deficit     float32 // squared SLO violation at numReplicas
+	nextDeficit float32 // squared SLO violation at numReplicas+1
+}
+
+// Reduction of the weighted squared SLO violation per accelerator unit of adding a replica; squaring the violation
+// favors servers further from their SLO, spreading degradation in inverse proportion to weight. The violation
+// covers both the fraction of the load served beyond the SLO and the relative excess of the predicted ITL or TTFT
+// over its target, which keeps decreasing with replicas once the replicas accept all the load.
+func (t *deficitTicket) gain() float32 {
+	return t.weight * (t.deficit - t.nextDeficit) / float32(t.unitsPerReplica)
+}
+
+// Allocate remaining accelerators among unallocated servers
+//   - one replica at a time to the server whose weighted SLO violation, as predicted by the queueing model of its
+//     candidate allocation, decreases the most per accelerator unit, up to the replicas needed to meet its SLO
+func allocateByDeficit(serverEntries []*serverEntry, pool *capacityPool) {
+
+	// create tickets for servers with a candidate allocation on an available accelerator type
+	tickets := make([]*deficitTicket, 0, len(serverEntries))
+	for _, entry := range serverEntries {
+		server := core.GetServer(entry.serverName)
+		if server == nil {
+			continue
+		}
+		model := core.GetModel(server.ModelName())
+		if model == nil {
+			continue
+		}
+		for _, alloc := range entry.allocations {
+			accName := alloc.Accelerator()
+			acc := core.GetAccelerator(accName)
+			if acc == nil {
+				continue
+			}
+			unitsPerReplica := model.NumInstances(accName) * acc.Spec().Multiplicity
+			if unitsPerReplica <= 0 || pool.units(server.Namespace(), acc.Type()) < unitsPerReplica {
+				continue
+			}
+			current := core.PredictPerformance(entry.serverName, accName, 0)
+			next := core.PredictPerformance(entry.serverName, accName, 1)
+			if current == nil || next == nil {
+				continue
+			}
+			tickets = append(tickets, &deficitTicket{
+				entry:           entry,
+				server:          server,
+				alloc:           alloc,
+				accType:         acc.Type(),
+				unitsPerReplica: unitsPerReplica,
+				weight:          priorityWeight(entry.priority),
+				deficit:         current.SquaredViolation(),
+				nextDeficit:     next.SquaredViolation(),
+			})
+			break
+		}
+	}
+
+	// add one replica at a time to the server of largest gain
+	for {
+		var top *deficitTicket
+		for _, ticket := range tickets {
+			if ticket.numReplicas >= ticket.alloc.NumReplicas() ||
+				pool.units(ticket.server.Namespace(), ticket.accType) < ticket.unitsPerReplica {
+				continue
+			}
+			if top == nil || compareDeficitTickets(ticket, top) < 0 {
+				top = ticket
+			}
+		}
+		if top == nil {
+			break
+		}
+		pool.take(top.server.Namespace(), top.accType, top.unitsPerReplica)
+		top.numReplicas++
+		top.deficit = top.nextDeficit
+		if next := core.PredictPerformance(top.entry.serverName, top.alloc.Accelerator(), top.numReplicas+1); next != nil {
+			top.nextDeficit = next.SquaredViolation()
+		} else {
+			top.nextDeficit = top.deficit
+		}
+	}
+
+	// update allocated servers
+	for _, ticket := range tickets {
+		if ticket.numReplicas == 0 {
+			continue
+		}
+		alloc := ticket.alloc
+		// adjust cost and value
+		factor := float32(ticket.numReplicas) / float32(alloc.NumReplicas())
+		alloc.SetCost(alloc.Cost() * factor)
+		alloc.SetValue(alloc.Value() * factor)
+		alloc.SetNumReplicas(ticket.numReplicas)
+		ticket.server.SetAllocation(alloc)
+	}
+}
+
+// Allocate the capacity left to the unallocated servers by SLO deficit. Allocated servers of a lower priority than
+// an unallocated server only met their SLO because the capacity it needed did not fit, so their allocations are
+// released and they are weighed against it by SLO deficit, rather than starving a higher priority server; allocated
+// servers of the same or a higher priority keep their allocations.
+func allocateRemainingByDeficit(entries, unallocated []*serverEntry, pool *capacityPool) {
+	if len(unallocated) == 0 {
+		return
+	}
+	highest := slices.MinFunc(unallocated, func(a, b *serverEntry) int {
+		return cmp.Compare(a.priority, b.priority)
+	}).priority
+	remaining := slices.Clone(unallocated)
+	for _, entry := range entries {
+		if entry.priority <= highest || slices.Contains(unallocated, entry) {
+			continue
+		}
+		server := core.GetServer(entry.serverName)
+		if server == nil || server.Allocation() == nil {
+			continue
+		}
+		alloc := server.Allocation()
+		acc := core.GetAccelerator(alloc.Accelerator())
+		model := core.GetModel(server.ModelName())
+		if acc == nil || model == nil {
+			continue
+		}
+		pool.release(server.Namespace(), acc.Type(),
+			alloc.NumReplicas()*model.NumInstances(alloc.Accelerator())*acc.Spec().Multiplicity)
+		server.RemoveAllocation()
+		remaining = append(remaining, entry)
+	}
+	allocateByDeficit(remaining, pool)
+}
+
+// Order tickets by decreasing gain, then by priority and server name
+func compareDeficitTickets(a, b *deficitTicket) int {
+	if c := cmp.Compare(b.gain(), a.gain()); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.entry.priority, b.entry.priority); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.entry.serverName, b.entry.serverName)
+}
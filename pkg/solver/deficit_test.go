@@ -0,0 +1,126 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
+)
+
+// Helper function to create a system of servers of the given service classes and arrival rates (req/min), each
+// needing 16 replicas of A100 at 3000 req/min, under the given capacity
+func setupTestSystemForDeficit(capacity int, servers map[string]float32) {
+	setupTestSystemForGreedy()
+	for _, name := range []string{"server1", "server2", "server3"} {
+		_ = core.TheSystem.RemoveServer(name)
+	}
+	core.TheSystem.AddServiceClass("gold", 1)
+	core.TheSystem.AddServiceClass("bronze", 50)
+	for _, class := range []string{"gold", "bronze"} {
+		core.TheSystem.ServiceClass(class).AddModelTarget(&config.ModelTarget{
+			Model:    "llama-7b",
+			SLO_ITL:  40,
+			SLO_TTFT: 500,
+		})
+	}
+	for class, rate := range servers {
+		core.TheSystem.AddServerFromSpec(config.ServerSpec{
+			Name:            class,
+			Class:           class,
+			Model:           "llama-7b",
+			KeepAccelerator: true,
+			CurrentAlloc: config.AllocationData{
+				Accelerator: "A100",
+				NumReplicas: 1,
+				Load: config.ServerLoadSpec{
+					ArrivalRate:  rate,
+					AvgInTokens:  100,
+					AvgOutTokens: 100,
+				},
+			},
+		})
+	}
+	core.TheSystem.SetCountFromSpec(config.AcceleratorCount{Type: "GPU_A100", Count: capacity})
+	core.TheSystem.Calculate()
+}
+
+func TestSolver_SLODeficit(t *testing.T) {
+	tests := []struct {
+		name         string
+		capacity     int
+		servers      map[string]float32
+		policy       string
+		wantReplicas map[string]int
+	}{
+		{
+			name:         "priority exhaustive starves lower priority",
+			capacity:     12,
+			servers:      map[string]float32{"gold": 3000, "bronze": 3000},
+			policy:       "PriorityExhaustive",
+			wantReplicas: map[string]int{"gold": 12, "bronze": 0},
+		},
+		{
+			name:         "degradation spread by priority",
+			capacity:     12,
+			servers:      map[string]float32{"gold": 3000, "bronze": 3000},
+			policy:       "SLODeficit",
+			wantReplicas: map[string]int{"gold": 9, "bronze": 3},
+		},
+		{
+			name:         "higher priority meeting its SLO keeps its allocation",
+			capacity:     20,
+			servers:      map[string]float32{"gold": 3000, "bronze": 3000},
+			policy:       "SLODeficit",
+			wantReplicas: map[string]int{"gold": 16, "bronze": 4},
+		},
+		{
+			name:         "lower load needs fewer replicas",
+			capacity:     12,
+			servers:      map[string]float32{"gold": 3000, "bronze": 1500},
+			policy:       "SLODeficit",
+			wantReplicas: map[string]int{"gold": 8, "bronze": 4},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, mip := range []bool{false, true} {
+			name := tt.name
+			if mip {
+				name += " (MIP)"
+			}
+			t.Run(name, func(t *testing.T) {
+				setupTestSystemForDeficit(tt.capacity, tt.servers)
+				if err := NewSolver(&config.OptimizerSpec{SaturationPolicy: tt.policy, MIP: mip}).Solve(); err != nil {
+					t.Fatalf("Solve() error = %v", err)
+				}
+				for name, want := range tt.wantReplicas {
+					got := 0
+					if alloc := core.GetServer(name).Allocation(); alloc != nil {
+						got = alloc.NumReplicas()
+					}
+					if got != want {
+						t.Errorf("server %s: replicas = %d, want %d", name, got, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestAllocateByDeficit_NeededReplicas(t *testing.T) {
+	setupTestSystemForDeficit(20, map[string]float32{"gold": 3000})
+	server := core.GetServer("gold")
+	alloc := server.AllAllocations()["A100"]
+	needed := alloc.NumReplicas()
+
+	available := map[string]int{"GPU_A100": 20}
+	entries := []*serverEntry{{serverName: "gold", priority: 1, allocations: []*core.Allocation{alloc}}}
+	allocateByDeficit(entries, newCapacityPool(available, nil))
+
+	if got := server.Allocation(); got == nil || got.NumReplicas() != needed {
+		t.Errorf("allocation = %v, want the %d replicas needed to meet the SLO", got, needed)
+	}
+	if available["GPU_A100"] != 20-needed {
+		t.Errorf("available = %d, want %d", available["GPU_A100"], 20-needed)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"cmp"
 	"fmt"
-	"maps"
 	"math"
 	"slices"
 
@@ -34,9 +33,8 @@ type ServerEntriesOrder func(a, b *serverEntry) int
 // Find optimal allocations using greedy algorithm, assuming limited accelerator capacity
 func (s *Solver) SolveGreedy() {
 
-	// make a copy of count of available accelerator types
-	available := make(map[string]int)
-	maps.Copy(available, core.GetCapacities())
+	// make a copy of count of available accelerator types and quotas of namespaces
+	pool := newSystemCapacityPool()
 
 	// create entries for all servers, sorting candidate allocations per server
 	entries := make([]*serverEntry, 0)
@@ -87,25 +85,29 @@ func (s *Solver) SolveGreedy() {
 	slices.SortFunc(entries, orderFunc)
 
 	// allocate
-	if s.optimizerSpec.DelayedBestEffort {
+	if config.SaturatedAllocationPolicyEnum(s.optimizerSpec.SaturationPolicy) == config.SLODeficit {
+		// allocate to all servers, then the capacity left to the remaining ones, across priority groups
+		unallocated := allocate(slices.Clone(entries), pool, orderFunc)
+		allocateRemainingByDeficit(entries, unallocated, pool)
+	} else if s.optimizerSpec.DelayedBestEffort {
 		// allocate to all servers
-		unallocated := allocate(entries, available, orderFunc)
+		unallocated := allocate(entries, pool, orderFunc)
 		// best effort allocation to all remaining servers
-		bestEffort(unallocated, available, s.optimizerSpec.SaturationPolicy)
+		bestEffort(unallocated, pool, s.optimizerSpec.SaturationPolicy)
 	} else {
 		groupEntries := makePriorityGroups(entries)
 		for _, group := range groupEntries {
 			// allocate to servers in priority group
-			unallocated := allocate(group, available, orderFunc)
+			unallocated := allocate(group, pool, orderFunc)
 			// best effort allocation to servers in priority group
-			bestEffort(unallocated, available, s.optimizerSpec.SaturationPolicy)
+			bestEffort(unallocated, pool, s.optimizerSpec.SaturationPolicy)
 		}
 	}
 }
 
 // allocate, satisfying SLO requirements, returning servers that did not receive any allocation
 func allocate(entries []*serverEntry,
-	available map[string]int,
+	pool *capacityPool,
 	orderFunc ServerEntriesOrder) (unallocatedEntries []*serverEntry) {
 
 	unallocatedEntries = make([]*serverEntry, 0)
@@ -139,9 +141,9 @@ func allocate(entries []*serverEntry,
 		unitsPerReplica := model.NumInstances(gName) * acc.Spec().Multiplicity
 		count := alloc.NumReplicas() * unitsPerReplica
 
-		// check if accelerator type of current allocation is available, allocate
-		if available[tName] >= count {
-			available[tName] -= count
+		// check if accelerator type of current allocation is available within the quota of the namespace, allocate
+		if pool.limit(server.Namespace(), tName, count) == "" {
+			pool.take(server.Namespace(), tName, count)
 			server.SetAllocation(alloc)
 		} else {
 			// otherwise, move to next candidate allocation
@@ -166,23 +168,27 @@ func allocate(entries []*serverEntry,
 }
 
 // give best effort allocation to unallocated servers according to saturation policy
-func bestEffort(unallocatedServers []*serverEntry, available map[string]int, policy string) {
+func bestEffort(unallocatedServers []*serverEntry, pool *capacityPool, policy string) {
 	switch config.SaturatedAllocationPolicyEnum(policy) {
 
 	// allocate exhaustively to servers in priority ordering
 	case config.PriorityExhaustive:
-		allocateMaximally(unallocatedServers, available)
+		allocateMaximally(unallocatedServers, pool)
 
 	// allocate in round-robin fashion within priority groups
 	case config.PriorityRoundRobin:
 		priorityGroups := makePriorityGroups(unallocatedServers)
 		for _, group := range priorityGroups {
-			allocateEqually(group, available)
+			allocateEqually(group, pool)
 		}
 
 	// allocate in round-robin fashion across all servers
 	case config.RoundRobin:
-		allocateEqually(unallocatedServers, available)
+		allocateEqually(unallocatedServers, pool)
+
+	// allocate to minimize SLO violation weighted by priority
+	case config.SLODeficit:
+		allocateByDeficit(unallocatedServers, pool)
 
 	// do not allocate beyond satisfying SLOs
 	case config.None:
@@ -191,7 +197,7 @@ func bestEffort(unallocatedServers []*serverEntry, available map[string]int, pol
 
 // Allocate remaining accelerators among unallocated servers
 //   - priority ordering: one server at a time exhaustively, until no resources to satisfy requirements
-func allocateMaximally(serverEntries []*serverEntry, available map[string]int) {
+func allocateMaximally(serverEntries []*serverEntry, pool *capacityPool) {
 	// fmt.Println("Unallocated server entries: ", serverEntries)
 	for _, entry := range serverEntries {
 		for _, alloc := range entry.allocations {
@@ -201,7 +207,7 @@ func allocateMaximally(serverEntries []*serverEntry, available map[string]int) {
 			model := core.GetModel(server.ModelName())
 			if acc := core.GetAccelerator(accName); acc != nil && model != nil && server != nil {
 				if unitsPerReplica := model.NumInstances(accName) * acc.Spec().Multiplicity; unitsPerReplica > 0 {
-					maxReplicas := available[acc.Type()] / unitsPerReplica
+					maxReplicas := pool.units(server.Namespace(), acc.Type()) / unitsPerReplica
 					if maxReplicas = min(maxReplicas, alloc.NumReplicas()); maxReplicas > 0 {
 						curNumReplicas := alloc.NumReplicas()
 						// adjust cost and value
@@ -211,7 +217,7 @@ func allocateMaximally(serverEntries []*serverEntry, available map[string]int) {
 						alloc.SetNumReplicas(maxReplicas)
 						server.SetAllocation(alloc)
 						count := maxReplicas * unitsPerReplica
-						available[acc.Type()] -= count
+						pool.take(server.Namespace(), acc.Type(), count)
 						// fmt.Printf("updated allocation: server=%s, acc=%s, maxReplicas=%d, type=%s, count=%d \n",
 						// 	serverName, accName, maxReplicas, acc.Type(), count)
 						break
@@ -236,7 +242,7 @@ type serverAllocationTicket struct {
 
 // Allocate remaining accelerators among a group of unallocated servers
 //   - round-robin allocation to members in group until no resources to satisfy requirements
-func allocateEqually(serverEntries []*serverEntry, available map[string]int) {
+func allocateEqually(serverEntries []*serverEntry, pool *capacityPool) {
 	// fmt.Println("Unallocated server entries: ", serverEntries)
 
 	// create allocation tickets for all valid members in group
@@ -271,7 +277,7 @@ func allocateEqually(serverEntries []*serverEntry, available map[string]int) {
 					accName := alloc.Accelerator()
 					if acc := core.GetAccelerator(accName); acc != nil {
 						unitsPerReplica := ticket.model.NumInstances(accName) * acc.Spec().Multiplicity
-						if unitsPerReplica > 0 && available[acc.Type()] >= unitsPerReplica {
+						if unitsPerReplica > 0 && pool.units(ticket.server.Namespace(), acc.Type()) >= unitsPerReplica {
 							ticket.active = true
 							ticket.accType = acc.Type()
 							ticket.unitsPerReplica = unitsPerReplica
@@ -287,10 +293,10 @@ func allocateEqually(serverEntries []*serverEntry, available map[string]int) {
 				}
 			}
 			// make one allocation (replica) to member
-			replicasAvailable := available[ticket.accType] / ticket.unitsPerReplica
+			replicasAvailable := pool.units(ticket.server.Namespace(), ticket.accType) / ticket.unitsPerReplica
 			if replicasAllocatable := min(replicasAvailable, ticket.finalAlloc.NumReplicas()); replicasAllocatable > 0 {
 				ticket.numReplicas++
-				available[ticket.accType] -= ticket.unitsPerReplica
+				pool.take(ticket.server.Namespace(), ticket.accType, ticket.unitsPerReplica)
 				allocatedTickets[serverName] = ticket
 			} else {
 				// remove ticket if can no longer allocate
@@ -309,7 +309,7 @@ func TestBestEffort_None(t *testing.T) {
 	entries := []*serverEntry{}
 	available := map[string]int{"GPU_A100": 4}
 
-	bestEffort(entries, available, "None")
+	bestEffort(entries, newCapacityPool(available, nil), "None")
 
 	// With "None" policy, available should remain unchanged
 	if available["GPU_A100"] != 4 {
@@ -321,7 +321,7 @@ func TestAllocateEqually_EmptyEntries(t *testing.T) {
 	entries := []*serverEntry{}
 	available := map[string]int{"GPU_A100": 4}
 
-	allocateEqually(entries, available)
+	allocateEqually(entries, newCapacityPool(available, nil))
 
 	if available["GPU_A100"] != 4 {
 		t.Error("Available resources should remain unchanged with empty entries")
@@ -986,7 +986,7 @@ func TestAllocateMaximally_EdgeCases(t *testing.T) {
 			"GPU_H100": 2,
 		}
 
-		allocateMaximally([]*serverEntry{}, available)
+		allocateMaximally([]*serverEntry{}, newCapacityPool(available, nil))
 
 		// Available resources should remain unchanged
 		if available["GPU_A100"] != 4 || available["GPU_H100"] != 2 {
@@ -1010,7 +1010,7 @@ func TestAllocateMaximally_EdgeCases(t *testing.T) {
 			},
 		}
 
-		allocateMaximally(entries, available)
+		allocateMaximally(entries, newCapacityPool(available, nil))
 
 		// available resources should remain unchanged
 		if available["GPU_A100"] != 4 || available["GPU_H100"] != 2 {
@@ -1046,7 +1046,7 @@ func TestAllocateMaximally_EdgeCases(t *testing.T) {
 		}
 
 		originalAllocation := server.Allocation()
-		allocateMaximally(entries, available)
+		allocateMaximally(entries, newCapacityPool(available, nil))
 
 		// Server allocation should not change when no resources available
 		newAllocation := server.Allocation()
@@ -1091,7 +1091,7 @@ func TestAllocateMaximally_EdgeCases(t *testing.T) {
 			initialAvailable[k] = v
 		}
 
-		allocateMaximally(entries, available)
+		allocateMaximally(entries, newCapacityPool(available, nil))
 
 		// Should have allocated some resources if possible
 		allocation := server.Allocation()
@@ -1122,7 +1122,7 @@ func TestAllocateEqually_EdgeCases(t *testing.T) {
 			"GPU_H100": 2,
 		}
 
-		allocateEqually([]*serverEntry{}, available)
+		allocateEqually([]*serverEntry{}, newCapacityPool(available, nil))
 
 		// Available resources should remain unchanged
 		if available["GPU_A100"] != 4 || available["GPU_H100"] != 2 {
@@ -1146,7 +1146,7 @@ func TestAllocateEqually_EdgeCases(t *testing.T) {
 			},
 		}
 
-		allocateEqually(entries, available)
+		allocateEqually(entries, newCapacityPool(available, nil))
 
 		// Available resources should remain unchanged since no allocations
 		if available["GPU_A100"] != 4 || available["GPU_H100"] != 2 {
@@ -1202,7 +1202,7 @@ func TestAllocateEqually_EdgeCases(t *testing.T) {
 		initialA100 := available["GPU_A100"]
 		initialH100 := available["GPU_H100"]
 
-		allocateEqually(entries, available)
+		allocateEqually(entries, newCapacityPool(available, nil))
 
 		// Verify that allocations were made
 		alloc1 := server1.Allocation()
@@ -1286,7 +1286,7 @@ func TestAllocateEqually_EdgeCases(t *testing.T) {
 			},
 		}
 
-		allocateEqually(entries, available)
+		allocateEqually(entries, newCapacityPool(available, nil))
 
 		// Both servers should get some allocation through multiple round-robin rounds
 		alloc1 := server1.Allocation()
@@ -1344,7 +1344,7 @@ func TestAllocateEqually_TicketManagement(t *testing.T) {
 		initialH100 := available["GPU_H100"]
 
 		// This tests the ticket creation, activation, and allocation process
-		allocateEqually(entries, available)
+		allocateEqually(entries, newCapacityPool(available, nil))
 
 		// Verify server received an allocation
 		allocation := server1.Allocation()
@@ -1396,7 +1396,7 @@ func TestAllocateEqually_TicketManagement(t *testing.T) {
 		}
 
 		// This tests that tickets are properly removed when no resources are available
-		allocateEqually(entries, available)
+		allocateEqually(entries, newCapacityPool(available, nil))
 
 		// Should complete without panic even with no resources
 		if server1.Allocation() != nil {
@@ -1450,7 +1450,7 @@ func TestBestEffort(t *testing.T) {
 		}
 
 		// Test the bestEffort function which contains the branching logic for saturation policies
-		bestEffort(allEntries, available, "PriorityExhaustive")
+		bestEffort(allEntries, newCapacityPool(available, nil), "PriorityExhaustive")
 
 		// At least some servers should get allocations
 		allocatedCount := 0
@@ -1500,7 +1500,7 @@ func TestBestEffort(t *testing.T) {
 				}
 
 				// Should not panic regardless of policy
-				bestEffort(entries, available, policy)
+				bestEffort(entries, newCapacityPool(available, nil), policy)
 
 				// For None policy, server should not get allocation
 				if policy == "None" {
@@ -1537,7 +1537,7 @@ func TestAllocate_ComprehensiveCoverage(t *testing.T) {
 			"GPU_H100": 2,
 		}
 
-		unallocated := allocate([]*serverEntry{}, available, simpleOrder)
+		unallocated := allocate([]*serverEntry{}, newCapacityPool(available, nil), simpleOrder)
 		if len(unallocated) != 0 {
 			t.Errorf("Expected no unallocated entries with empty input, got %d", len(unallocated))
 		}
@@ -1560,7 +1560,7 @@ func TestAllocate_ComprehensiveCoverage(t *testing.T) {
 			},
 		}
 
-		unallocated := allocate(entries, available, simpleOrder)
+		unallocated := allocate(entries, newCapacityPool(available, nil), simpleOrder)
 		// Server with no allocations should be skipped (continue statement)
 		if len(unallocated) != 0 {
 			t.Errorf("Expected no unallocated entries when entries have no allocations")
@@ -1585,7 +1585,7 @@ func TestAllocate_ComprehensiveCoverage(t *testing.T) {
 			},
 		}
 
-		unallocated := allocate(entries, available, simpleOrder)
+		unallocated := allocate(entries, newCapacityPool(available, nil), simpleOrder)
 
 		// The nonexistent server entry should be skipped (continue statement)
 		// so no unallocated entries should be returned
@@ -1612,7 +1612,7 @@ func TestAllocate_ComprehensiveCoverage(t *testing.T) {
 
 		// Test with empty entries (should not modify available resources)
 		entries := []*serverEntry{}
-		unallocated := allocate(entries, available, simpleOrder)
+		unallocated := allocate(entries, newCapacityPool(available, nil), simpleOrder)
 
 		if len(unallocated) != 0 {
 			t.Errorf("Expected no unallocated entries with empty input, got %d", len(unallocated))
@@ -1677,7 +1677,7 @@ func TestAllocate_ComprehensiveCoverage(t *testing.T) {
 			},
 		}
 
-		unallocated := allocate(entries, available, simpleOrder)
+		unallocated := allocate(entries, newCapacityPool(available, nil), simpleOrder)
 
 		// With no resources, this should:
 		// 1. Fail first allocation (curIndex=0), increment to curIndex=1
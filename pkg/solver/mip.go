@@ -0,0 +1,366 @@
+package solver
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
+)
+
+// number of branch-and-bound nodes explored between checks of the time budget
+const mipDeadlineCheckNodes = 1024
+
+// Statistics of a MIP solution, comparing it to the greedy solution
+type MIPStats struct {
+	Optimal          bool    // search completed within the time budget, proving optimality
+	Applied          bool    // MIP solution replaced the greedy one
+	Nodes            int     // number of branch-and-bound nodes explored
+	GreedyObjective  float64 // objective value of the greedy solution
+	Objective        float64 // objective value of the best solution found
+	SolutionTimeMsec int64   // time spent in branch-and-bound
+}
+
+// Relative optimality gap of the greedy solution with respect to the best solution found
+func (m *MIPStats) Gap() float64 {
+	if m.GreedyObjective == 0 {
+		return 0
+	}
+	return (m.GreedyObjective - m.Objective) / m.GreedyObjective
+}
+
+func (m *MIPStats) String() string {
+	return fmt.Sprintf("MIP: optimal=%v, applied=%v, nodes=%d, greedyObjective=%v, objective=%v, gap=%.4f, time=%d msec",
+		m.Optimal, m.Applied, m.Nodes, m.GreedyObjective, m.Objective, m.Gap(), m.SolutionTimeMsec)
+}
+
+// Candidate allocation of a server in the integer program
+type mipOption struct {
+	alloc   *core.Allocation // copy of candidate allocation
+	value   float64          // value of allocation
+	accType string           // accelerator type used by allocation
+	count   int              // number of accelerator units of type used by allocation
+}
+
+// Server in the integer program: choose at most one of its options, or pay the penalty of leaving it unallocated
+type mipServer struct {
+	name        string
+	namespace   string
+	priority    int
+	penalty     float64            // cost of not allocating the server
+	options     []mipOption        // options fitting the capacity and quota, ordered by increasing value
+	allocations []*core.Allocation // copies of all candidate allocations, ordered by increasing value, for best effort
+}
+
+// Integer program selecting one allocation per server under accelerator type capacity and namespace quotas:
+//
+//	minimize   sum_ij value_ij * x_ij + sum_i penalty_i * (1 - sum_j x_ij)
+//	subject to sum_j x_ij <= 1                               for all servers i
+//	           sum_ij count_ijt * x_ij <= capacity_t         for all accelerator types t
+//	           sum_(i in n),j count_ijt * x_ij <= quota_nt   for all namespaces n with a quota of type t
+//	           x_ij in {0, 1}
+//
+// Penalties are weighted by priority such that allocating a server is preferred over allocating any number of
+// servers of lower priority, and over any saving in value.
+type mipProblem struct {
+	servers  []*mipServer // servers ordered by decreasing penalty
+	capacity map[string]int
+	quotas   map[string]map[string]int // quotas by namespace and accelerator type
+}
+
+// Create the integer program from the candidate allocations of all servers, the available capacity and the
+// quotas of namespaces
+func newMIPProblem(capacity map[string]int, quotas map[string]map[string]int) *mipProblem {
+	p := &mipProblem{
+		servers:  make([]*mipServer, 0),
+		capacity: make(map[string]int),
+		quotas:   make(map[string]map[string]int),
+	}
+	maps.Copy(p.capacity, capacity)
+	for namespace, quota := range quotas {
+		p.quotas[namespace] = maps.Clone(quota)
+	}
+	pool := p.pool()
+
+	for serverName, server := range core.GetServers() {
+		model := core.GetModel(server.ModelName())
+		if model == nil {
+			continue
+		}
+		s := &mipServer{
+			name:        serverName,
+			namespace:   server.Namespace(),
+			priority:    server.Priority(),
+			options:     make([]mipOption, 0),
+			allocations: make([]*core.Allocation, 0),
+		}
+		for _, alloc := range server.AllAllocations() {
+			s.allocations = append(s.allocations, alloc.Clone())
+		}
+		if len(s.allocations) == 0 {
+			continue
+		}
+		slices.SortFunc(s.allocations, func(a, b *core.Allocation) int {
+			return cmp.Compare(a.Value(), b.Value())
+		})
+		for _, alloc := range s.allocations {
+			accName := alloc.Accelerator()
+			acc := core.GetAccelerator(accName)
+			if acc == nil {
+				continue
+			}
+			count := alloc.NumReplicas() * model.NumInstances(accName) * acc.Spec().Multiplicity
+			// skip allocations which never fit
+			if count > pool.units(s.namespace, acc.Type()) {
+				continue
+			}
+			s.options = append(s.options, mipOption{
+				alloc:   alloc,
+				value:   float64(alloc.Value()),
+				accType: acc.Type(),
+				count:   count,
+			})
+		}
+		p.servers = append(p.servers, s)
+	}
+	p.setPenalties()
+	slices.SortFunc(p.servers, func(a, b *mipServer) int {
+		if c := cmp.Compare(b.penalty, a.penalty); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.name, b.name)
+	})
+	return p
+}
+
+// Set the penalties of servers, from the lowest priority (highest number) up:
+// the penalty of a priority level exceeds the sum of penalties of all lower levels and the spread of values
+func (p *mipProblem) setPenalties() {
+	valueSpread := 1.0
+	for _, s := range p.servers {
+		if n := len(s.options); n > 0 {
+			valueSpread += math.Abs(s.options[n-1].value) + math.Abs(s.options[0].value)
+		}
+	}
+	byPriority := make(map[int][]*mipServer)
+	for _, s := range p.servers {
+		byPriority[s.priority] = append(byPriority[s.priority], s)
+	}
+	priorities := slices.Sorted(maps.Keys(byPriority))
+	slices.Reverse(priorities)
+
+	lowerPenalties := 0.0
+	for _, priority := range priorities {
+		penalty := valueSpread + lowerPenalties
+		for _, s := range byPriority[priority] {
+			s.penalty = penalty
+		}
+		lowerPenalties += penalty * float64(len(byPriority[priority]))
+	}
+}
+
+// Objective value of a choice of option per server (-1 for none)
+func (p *mipProblem) objective(choice []int) float64 {
+	obj := 0.0
+	for i, s := range p.servers {
+		if choice[i] < 0 {
+			obj += s.penalty
+		} else {
+			obj += s.options[choice[i]].value
+		}
+	}
+	return obj
+}
+
+// Pool of the capacity and quotas of the integer program
+func (p *mipProblem) pool() *capacityPool {
+	return newCapacityPool(p.capacity, p.quotas).clone()
+}
+
+// Check whether a choice of option per server fits the capacity and quotas
+func (p *mipProblem) feasible(choice []int) bool {
+	pool := p.pool()
+	for i, s := range p.servers {
+		if choice[i] >= 0 {
+			opt := s.options[choice[i]]
+			if pool.limit(s.namespace, opt.accType, opt.count) != "" {
+				return false
+			}
+			pool.take(s.namespace, opt.accType, opt.count)
+		}
+	}
+	return true
+}
+
+// branch-and-bound search state
+type mipSearch struct {
+	problem  *mipProblem
+	pool     *capacityPool
+	choice   []int
+	best     []int
+	bestObj  float64
+	nodes    int
+	deadline time.Time
+	timedOut bool
+}
+
+// Solve the integer program by depth-first branch-and-bound, starting from a feasible incumbent, until the search
+// completes or the deadline passes; returns the best choice found and whether it is proven optimal
+func (p *mipProblem) solve(incumbent []int, deadline time.Time) (best []int, bestObj float64, nodes int, optimal bool) {
+	search := &mipSearch{
+		problem:  p,
+		pool:     p.pool(),
+		choice:   make([]int, len(p.servers)),
+		best:     slices.Clone(incumbent),
+		bestObj:  p.objective(incumbent),
+		deadline: deadline,
+	}
+	search.branch(0, 0)
+	return search.best, search.bestObj, search.nodes, !search.timedOut
+}
+
+// Branch on the options of server i, given the objective value of the choices of servers before it
+func (m *mipSearch) branch(i int, partial float64) {
+	if m.timedOut {
+		return
+	}
+	m.nodes++
+	if m.nodes%mipDeadlineCheckNodes == 0 && time.Now().After(m.deadline) {
+		m.timedOut = true
+		return
+	}
+	servers := m.problem.servers
+	if i == len(servers) {
+		if partial < m.bestObj {
+			m.bestObj = partial
+			m.best = slices.Clone(m.choice)
+		}
+		return
+	}
+	if partial+m.bound(i) >= m.bestObj {
+		return
+	}
+
+	// options in increasing value, then leaving the server unallocated
+	s := servers[i]
+	for j, opt := range s.options {
+		if m.pool.limit(s.namespace, opt.accType, opt.count) != "" {
+			continue
+		}
+		m.pool.take(s.namespace, opt.accType, opt.count)
+		m.choice[i] = j
+		m.branch(i+1, partial+opt.value)
+		m.pool.release(s.namespace, opt.accType, opt.count)
+	}
+	m.choice[i] = -1
+	m.branch(i+1, partial+s.penalty)
+}
+
+// Lower bound on the objective value of servers i onward: each takes its best option fitting the remaining
+// capacity and quota on its own, or its penalty
+func (m *mipSearch) bound(i int) float64 {
+	bound := 0.0
+	for _, s := range m.problem.servers[i:] {
+		best := s.penalty
+		for _, opt := range s.options {
+			if opt.count <= m.pool.units(s.namespace, opt.accType) {
+				best = min(best, opt.value)
+				break
+			}
+		}
+		bound += best
+	}
+	return bound
+}
+
+// Find optimal allocations under limited accelerator capacity by solving an integer program, within the time
+// budget of the optimizer spec; if the search does not complete in time, the best solution found so far is
+// applied, and the greedy solution is kept if no solution improves on it. Servers left unallocated get best effort
+// allocations according to the saturation policy.
+func (s *Solver) SolveMIP() {
+	problem := newMIPProblem(core.GetCapacities(), core.GetQuotas())
+
+	// greedy solution as fallback and initial incumbent
+	s.SolveGreedy()
+	incumbent := make([]int, len(problem.servers))
+	for i, ms := range problem.servers {
+		incumbent[i] = -1
+		alloc := core.GetServer(ms.name).Allocation()
+		if alloc == nil {
+			continue
+		}
+		for j, opt := range ms.options {
+			if opt.alloc.Accelerator() == alloc.Accelerator() && opt.alloc.NumReplicas() == alloc.NumReplicas() {
+				incumbent[i] = j
+				break
+			}
+		}
+	}
+	if !problem.feasible(incumbent) {
+		for i := range incumbent {
+			incumbent[i] = -1
+		}
+	}
+
+	timeLimit := s.optimizerSpec.MIPTimeLimitMsec
+	if timeLimit <= 0 {
+		timeLimit = config.DefaultMIPTimeLimitMsec
+	}
+	startTime := time.Now()
+	best, bestObj, nodes, optimal := problem.solve(incumbent, startTime.Add(time.Duration(timeLimit)*time.Millisecond))
+	greedyObj := problem.objective(incumbent)
+	s.mipStats = &MIPStats{
+		Optimal:          optimal,
+		Nodes:            nodes,
+		GreedyObjective:  greedyObj,
+		Objective:        bestObj,
+		SolutionTimeMsec: time.Since(startTime).Milliseconds(),
+	}
+	if bestObj >= greedyObj {
+		return
+	}
+	s.mipStats.Applied = true
+
+	// apply solution
+	pool := problem.pool()
+	for _, server := range core.GetServers() {
+		server.RemoveAllocation()
+	}
+	unallocated := make([]*serverEntry, 0)
+	for i, ms := range problem.servers {
+		if best[i] >= 0 {
+			opt := ms.options[best[i]]
+			pool.take(ms.namespace, opt.accType, opt.count)
+			core.GetServer(ms.name).SetAllocation(opt.alloc)
+			continue
+		}
+		unallocated = append(unallocated, &serverEntry{
+			serverName:  ms.name,
+			priority:    ms.priority,
+			allocations: ms.allocations,
+		})
+	}
+	slices.SortStableFunc(unallocated, func(a, b *serverEntry) int {
+		return cmp.Compare(a.priority, b.priority)
+	})
+	if config.SaturatedAllocationPolicyEnum(s.optimizerSpec.SaturationPolicy) == config.SLODeficit {
+		entries := make([]*serverEntry, len(problem.servers))
+		for i, ms := range problem.servers {
+			entries[i] = &serverEntry{serverName: ms.name, priority: ms.priority, allocations: ms.allocations}
+		}
+		allocateRemainingByDeficit(entries, unallocated, pool)
+		return
+	}
+	for _, group := range makePriorityGroups(unallocated) {
+		bestEffort(group, pool, s.optimizerSpec.SaturationPolicy)
+	}
+}
+
+// Statistics of the last MIP solution, nil if the MIP solver was not used
+func (s *Solver) MIPStats() *MIPStats {
+	return s.mipStats
+}
@@ -0,0 +1,204 @@
+package solver
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
+)
+
+// Helper function to create a random integer program with the given number of servers
+func randomMIPProblem(rng *rand.Rand, numServers int) *mipProblem {
+	types := []string{"A", "B", "C"}
+	p := &mipProblem{
+		servers:  make([]*mipServer, numServers),
+		capacity: map[string]int{"A": 1 + rng.Intn(6), "B": 1 + rng.Intn(6), "C": 1 + rng.Intn(6)},
+	}
+	for i := range p.servers {
+		s := &mipServer{priority: 1 + rng.Intn(2)}
+		for _, accType := range types[:1+rng.Intn(len(types))] {
+			s.options = append(s.options, mipOption{
+				value:   float64(1 + rng.Intn(20)),
+				accType: accType,
+				count:   1 + rng.Intn(3),
+			})
+		}
+		// options are ordered by increasing value
+		slices.SortFunc(s.options, func(a, b mipOption) int {
+			return cmp.Compare(a.value, b.value)
+		})
+		p.servers[i] = s
+	}
+	p.setPenalties()
+	return p
+}
+
+// Helper function to find the optimal objective value by enumerating all choices
+func bruteForceMIP(p *mipProblem) float64 {
+	choice := make([]int, len(p.servers))
+	best := math.Inf(1)
+	var enumerate func(i int)
+	enumerate = func(i int) {
+		if i == len(p.servers) {
+			if p.feasible(choice) {
+				best = min(best, p.objective(choice))
+			}
+			return
+		}
+		for j := -1; j < len(p.servers[i].options); j++ {
+			choice[i] = j
+			enumerate(i + 1)
+		}
+	}
+	enumerate(0)
+	return best
+}
+
+func noneChoice(n int) []int {
+	choice := make([]int, n)
+	for i := range choice {
+		choice[i] = -1
+	}
+	return choice
+}
+
+func TestMIPProblem_SolveMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		p := randomMIPProblem(rng, 1+rng.Intn(6))
+		best, bestObj, _, optimal := p.solve(noneChoice(len(p.servers)), time.Now().Add(time.Minute))
+		if !optimal {
+			t.Fatalf("trial %d: search did not complete", trial)
+		}
+		if !p.feasible(best) {
+			t.Fatalf("trial %d: infeasible solution %v", trial, best)
+		}
+		if want := bruteForceMIP(p); bestObj != want || p.objective(best) != want {
+			t.Errorf("trial %d: objective = %v, want %v", trial, bestObj, want)
+		}
+	}
+}
+
+func TestMIPProblem_GreedyGap(t *testing.T) {
+	// greedy takes the cheapest option of the first server, which leaves no room for the second
+	p := &mipProblem{
+		servers: []*mipServer{
+			{name: "s1", priority: 1, options: []mipOption{
+				{value: 1, accType: "A", count: 2},
+				{value: 2, accType: "B", count: 1},
+			}},
+			{name: "s2", priority: 1, options: []mipOption{
+				{value: 1, accType: "A", count: 2},
+			}},
+		},
+		capacity: map[string]int{"A": 2, "B": 1},
+	}
+	p.setPenalties()
+	greedy := []int{0, -1}
+	best, bestObj, _, optimal := p.solve(greedy, time.Now().Add(time.Minute))
+	if !optimal {
+		t.Fatal("search did not complete")
+	}
+	if best[0] != 1 || best[1] != 0 {
+		t.Errorf("solution = %v, want [1 0]", best)
+	}
+	if bestObj != 3 {
+		t.Errorf("objective = %v, want 3", bestObj)
+	}
+	if greedyObj := p.objective(greedy); greedyObj <= bestObj {
+		t.Errorf("greedy objective %v should exceed optimal %v", greedyObj, bestObj)
+	}
+}
+
+func TestMIPProblem_PriorityPenalties(t *testing.T) {
+	p := randomMIPProblem(rand.New(rand.NewSource(2)), 8)
+	lowPenalties := 0.0
+	highPenalty := 0.0
+	for _, s := range p.servers {
+		if s.priority == 2 {
+			lowPenalties += s.penalty
+		} else {
+			highPenalty = s.penalty
+		}
+	}
+	if highPenalty <= lowPenalties {
+		t.Errorf("high priority penalty %v should exceed sum of low priority penalties %v", highPenalty, lowPenalties)
+	}
+}
+
+func TestMIPProblem_TimeBudget(t *testing.T) {
+	p := randomMIPProblem(rand.New(rand.NewSource(3)), 20)
+	for _, s := range p.servers {
+		s.priority = 1
+	}
+	p.capacity = map[string]int{"A": 20, "B": 20, "C": 20}
+	p.setPenalties()
+	incumbent := noneChoice(len(p.servers))
+	best, bestObj, nodes, optimal := p.solve(incumbent, time.Now())
+	if optimal {
+		t.Skipf("search completed in %d nodes before the deadline check", nodes)
+	}
+	if !p.feasible(best) || bestObj > p.objective(incumbent) {
+		t.Errorf("timed out solution %v (objective %v) should be feasible and no worse than the incumbent", best, bestObj)
+	}
+}
+
+func TestSolver_SolveMIP(t *testing.T) {
+	for _, policy := range []string{"None", "PriorityExhaustive", "RoundRobin"} {
+		t.Run(policy, func(t *testing.T) {
+			setupTestSystemForGreedy()
+			core.TheSystem.SetCountFromSpec(config.AcceleratorCount{Type: "GPU_A100", Count: 2})
+			core.TheSystem.SetCountFromSpec(config.AcceleratorCount{Type: "GPU_H100", Count: 1})
+			core.TheSystem.Calculate()
+
+			solver := NewSolver(&config.OptimizerSpec{SaturationPolicy: policy, MIP: true})
+			if err := solver.Solve(); err != nil {
+				t.Fatalf("Solve() error = %v", err)
+			}
+			stats := solver.MIPStats()
+			if stats == nil {
+				t.Fatal("MIPStats() should be set after solving with MIP")
+			}
+			if !stats.Optimal {
+				t.Error("small problem should be solved to optimality")
+			}
+			if stats.Objective > stats.GreedyObjective {
+				t.Errorf("objective %v should not exceed greedy objective %v", stats.Objective, stats.GreedyObjective)
+			}
+			if stats.Gap() < 0 {
+				t.Errorf("gap %v should not be negative", stats.Gap())
+			}
+
+			// allocations fit capacity
+			used := make(map[string]int)
+			for _, server := range core.GetServers() {
+				if alloc := server.Allocation(); alloc != nil {
+					acc := core.GetAccelerator(alloc.Accelerator())
+					model := core.GetModel(server.ModelName())
+					used[acc.Type()] += alloc.NumReplicas() * model.NumInstances(acc.Name()) * acc.Spec().Multiplicity
+				}
+			}
+			for accType, count := range used {
+				if count > core.GetCapacities()[accType] {
+					t.Errorf("type %s: allocated %d units, capacity %d", accType, count, core.GetCapacities()[accType])
+				}
+			}
+		})
+	}
+}
+
+func TestSolver_SolveMIP_StatsOnlyWithMIP(t *testing.T) {
+	setupTestSystemForGreedy()
+	solver := NewSolver(&config.OptimizerSpec{SaturationPolicy: "None"})
+	if err := solver.Solve(); err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if solver.MIPStats() != nil {
+		t.Error("MIPStats() should be nil with the greedy solver")
+	}
+}
@@ -38,6 +38,14 @@ func (o *Optimizer) SolutionTimeMsec() int64 {
 	return o.solutionTimeMsec
 }
 
+// Plan of the last optimization; nil before optimizing
+func (o *Optimizer) Plan() *Plan {
+	if o.solver == nil {
+		return nil
+	}
+	return o.solver.Plan()
+}
+
 func (o *Optimizer) String() string {
 	var b bytes.Buffer
 	if o.solver != nil {
@@ -0,0 +1,230 @@
+package solver
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
+)
+
+// Action of a step of an allocation plan
+type StepAction string
+
+const (
+	ActionScaleDown StepAction = "ScaleDown" // remove replicas of a server, keeping its accelerator
+	ActionRelease   StepAction = "Release"   // remove all replicas of a server from its current accelerator
+	ActionScaleUp   StepAction = "ScaleUp"   // add replicas of a server, on its current or a new accelerator
+)
+
+// Step of an allocation plan, changing the number of replicas of a server on an accelerator
+type PlanStep struct {
+	Index           int        `json:"index"`
+	Action          StepAction `json:"action"`
+	ServerName      string     `json:"serverName"`
+	Accelerator     string     `json:"accelerator"`
+	AcceleratorType string     `json:"acceleratorType,omitempty"`
+	FromReplicas    int        `json:"fromReplicas"`
+	ToReplicas      int        `json:"toReplicas"`
+	Units           int        `json:"units"`               // accelerator units released or acquired
+	DependsOn       []int      `json:"dependsOn,omitempty"` // indices of earlier steps releasing units acquired
+}
+
+// Releases units of an accelerator
+func (s *PlanStep) Releases() bool {
+	return s.Action != ActionScaleUp
+}
+
+func (s *PlanStep) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%d: %s sName=%s, acc=%s, numRep=%d -> %d, units=%d",
+		s.Index, s.Action, s.ServerName, s.Accelerator, s.FromReplicas, s.ToReplicas, s.Units)
+	if len(s.DependsOn) > 0 {
+		fmt.Fprintf(&b, ", after=%v", s.DependsOn)
+	}
+	return b.String()
+}
+
+// Ordered steps moving servers from their current to their desired allocations: steps releasing accelerator units
+// first, then steps acquiring them, each depending on the earlier steps releasing the units it needs beyond those
+// free in the system capacity and the quota of its namespace
+type Plan struct {
+	Steps []PlanStep `json:"steps"`
+}
+
+// Create the plan of allocation differences of servers, keyed by server name; dependencies are only set if the
+// accelerator capacity is limited
+func CreatePlan(diffs map[string]*core.AllocationDiff, limited bool) *Plan {
+	releases := make([]PlanStep, 0, len(diffs))
+	acquires := make([]PlanStep, 0, len(diffs))
+	for serverName, diff := range diffs {
+		if diff == nil {
+			continue
+		}
+		oldAcc, newAcc := diff.OldAccelerator(), diff.NewAccelerator()
+		oldNum, newNum := diff.OldNumReplicas(), diff.NewNumReplicas()
+		if oldAcc == newAcc {
+			switch {
+			case oldAcc == core.NoAccelerator || oldNum == newNum:
+			case newNum < oldNum:
+				releases = append(releases, newPlanStep(ActionScaleDown, serverName, oldAcc, oldNum, newNum))
+			default:
+				acquires = append(acquires, newPlanStep(ActionScaleUp, serverName, newAcc, oldNum, newNum))
+			}
+			continue
+		}
+		if oldAcc != core.NoAccelerator && oldNum > 0 {
+			releases = append(releases, newPlanStep(ActionRelease, serverName, oldAcc, oldNum, 0))
+		}
+		if newAcc != core.NoAccelerator && newNum > 0 {
+			acquires = append(acquires, newPlanStep(ActionScaleUp, serverName, newAcc, 0, newNum))
+		}
+	}
+
+	// release from lower priority servers first, acquire for higher priority servers first
+	slices.SortFunc(releases, func(a, b PlanStep) int {
+		return compareSteps(a, b, -1)
+	})
+	slices.SortFunc(acquires, func(a, b PlanStep) int {
+		return compareSteps(a, b, 1)
+	})
+
+	plan := &Plan{Steps: append(releases, acquires...)}
+	for i := range plan.Steps {
+		plan.Steps[i].Index = i
+	}
+	if limited {
+		setDependencies(plan, diffs)
+	}
+	return plan
+}
+
+func newPlanStep(action StepAction, serverName, accName string, from, to int) PlanStep {
+	step := PlanStep{
+		Action:       action,
+		ServerName:   serverName,
+		Accelerator:  accName,
+		FromReplicas: from,
+		ToReplicas:   to,
+	}
+	if server := core.GetServer(serverName); server != nil {
+		step.AcceleratorType, step.Units = replicaUnits(server, accName, max(from, to)-min(from, to))
+	}
+	return step
+}
+
+// Order steps by priority of their servers, higher priority first if direction is positive, lower priority first
+// otherwise, then by server name
+func compareSteps(a, b PlanStep, direction int) int {
+	if c := cmp.Compare(stepPriority(a), stepPriority(b)); c != 0 {
+		return c * direction
+	}
+	return cmp.Compare(a.ServerName, b.ServerName)
+}
+
+func stepPriority(step PlanStep) int {
+	if server := core.GetServer(step.ServerName); server != nil {
+		return server.Priority()
+	}
+	return 0
+}
+
+// Units released by a step, left to be acquired by later steps, against the capacity and the namespace quota
+type releasedUnits struct {
+	step      int
+	namespace string
+	accType   string
+	capacity  int
+	quota     int
+}
+
+// Set the dependencies of steps acquiring units on the steps releasing them, given the units free in the system
+// capacity and quotas with the current allocations of all servers
+func setDependencies(plan *Plan, diffs map[string]*core.AllocationDiff) {
+	pool := newSystemCapacityPool()
+	for serverName, diff := range diffs {
+		server := core.GetServer(serverName)
+		if server == nil || diff == nil || diff.OldAccelerator() == core.NoAccelerator {
+			continue
+		}
+		if accType, count := replicaUnits(server, diff.OldAccelerator(), diff.OldNumReplicas()); count > 0 {
+			pool.take(server.Namespace(), accType, count)
+		}
+	}
+
+	released := make([]*releasedUnits, 0)
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+		if step.Units == 0 {
+			continue
+		}
+		namespace := ""
+		if server := core.GetServer(step.ServerName); server != nil {
+			namespace = server.Namespace()
+		}
+		if step.Releases() {
+			released = append(released, &releasedUnits{
+				step:      step.Index,
+				namespace: namespace,
+				accType:   step.AcceleratorType,
+				capacity:  step.Units,
+				quota:     step.Units,
+			})
+			continue
+		}
+
+		// units free in the capacity, then released by earlier steps
+		needed := step.Units - drawFree(pool.available, step.AcceleratorType, step.Units)
+		for _, r := range released {
+			if needed == 0 {
+				break
+			}
+			if r.accType != step.AcceleratorType || r.capacity == 0 {
+				continue
+			}
+			count := min(r.capacity, needed)
+			r.capacity -= count
+			needed -= count
+			step.DependsOn = append(step.DependsOn, r.step)
+		}
+
+		// units free in the quota of the namespace, then released by earlier steps in the namespace
+		if quota, limited := pool.quotas[namespace]; limited {
+			if _, limited := quota[step.AcceleratorType]; limited {
+				needed = step.Units - drawFree(quota, step.AcceleratorType, step.Units)
+				for _, r := range released {
+					if needed == 0 {
+						break
+					}
+					if r.accType != step.AcceleratorType || r.namespace != namespace || r.quota == 0 {
+						continue
+					}
+					count := min(r.quota, needed)
+					r.quota -= count
+					needed -= count
+					if !slices.Contains(step.DependsOn, r.step) {
+						step.DependsOn = append(step.DependsOn, r.step)
+					}
+				}
+			}
+		}
+		slices.Sort(step.DependsOn)
+	}
+}
+
+// Take up to a number of free units of an accelerator type, returning the number taken
+func drawFree(free map[string]int, accType string, count int) int {
+	taken := min(max(free[accType], 0), count)
+	free[accType] -= taken
+	return taken
+}
+
+func (p *Plan) String() string {
+	var b bytes.Buffer
+	b.WriteString("Plan: \n")
+	for i := range p.Steps {
+		fmt.Fprintf(&b, "%v \n", &p.Steps[i])
+	}
+	return b.String()
+}
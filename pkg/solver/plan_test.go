@@ -0,0 +1,192 @@
+package solver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
+)
+
+// Helper function to create a system of servers of the given service class and namespace, under the given capacity
+// and quotas
+func setupTestSystemForPlan(capacity map[string]int, quotas []config.NamespaceQuota, servers ...config.ServerSpec) {
+	setupTestSystemForQuota(capacity, quotas)
+	for _, spec := range servers {
+		spec.Model = "llama-7b"
+		core.TheSystem.AddServerFromSpec(spec)
+	}
+}
+
+func planDiff(oldAcc string, oldNum int, newAcc string, newNum int) *core.AllocationDiff {
+	var oldAlloc, newAlloc *core.Allocation
+	if oldAcc != "" {
+		oldAlloc = core.AllocationFromData(&config.AllocationData{Accelerator: oldAcc, NumReplicas: oldNum})
+	}
+	if newAcc != "" {
+		newAlloc = core.AllocationFromData(&config.AllocationData{Accelerator: newAcc, NumReplicas: newNum})
+	}
+	return core.CreateAllocationDiff(oldAlloc, newAlloc)
+}
+
+func TestCreatePlan(t *testing.T) {
+	servers := []config.ServerSpec{
+		{Name: "high", Namespace: "ns1", Class: "high-priority"},
+		{Name: "medium", Namespace: "ns1", Class: "medium-priority"},
+		{Name: "low", Namespace: "ns2", Class: "low-priority"},
+	}
+
+	tests := []struct {
+		name     string
+		capacity map[string]int
+		quotas   []config.NamespaceQuota
+		diffs    map[string]*core.AllocationDiff
+		limited  bool
+		want     []PlanStep
+	}{
+		{
+			name:     "scale up waits for scale down under tight capacity",
+			capacity: map[string]int{"GPU_A100": 8},
+			diffs: map[string]*core.AllocationDiff{
+				"high": planDiff("A100", 2, "A100", 6),
+				"low":  planDiff("A100", 6, "A100", 2),
+			},
+			limited: true,
+			want: []PlanStep{
+				{Index: 0, Action: ActionScaleDown, ServerName: "low", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 6, ToReplicas: 2, Units: 4},
+				{Index: 1, Action: ActionScaleUp, ServerName: "high", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 2, ToReplicas: 6, Units: 4, DependsOn: []int{0}},
+			},
+		},
+		{
+			name:     "free capacity leaves no dependencies",
+			capacity: map[string]int{"GPU_A100": 12},
+			diffs: map[string]*core.AllocationDiff{
+				"high": planDiff("A100", 2, "A100", 6),
+				"low":  planDiff("A100", 6, "A100", 2),
+			},
+			limited: true,
+			want: []PlanStep{
+				{Index: 0, Action: ActionScaleDown, ServerName: "low", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 6, ToReplicas: 2, Units: 4},
+				{Index: 1, Action: ActionScaleUp, ServerName: "high", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 2, ToReplicas: 6, Units: 4},
+			},
+		},
+		{
+			name:     "unlimited capacity leaves no dependencies",
+			capacity: map[string]int{"GPU_A100": 8},
+			diffs: map[string]*core.AllocationDiff{
+				"high": planDiff("A100", 2, "A100", 6),
+				"low":  planDiff("A100", 6, "A100", 2),
+			},
+			limited: false,
+			want: []PlanStep{
+				{Index: 0, Action: ActionScaleDown, ServerName: "low", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 6, ToReplicas: 2, Units: 4},
+				{Index: 1, Action: ActionScaleUp, ServerName: "high", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 2, ToReplicas: 6, Units: 4},
+			},
+		},
+		{
+			name:     "accelerator change releases the old accelerator",
+			capacity: map[string]int{"GPU_A100": 6, "GPU_H100": 4},
+			diffs: map[string]*core.AllocationDiff{
+				"medium": planDiff("A100", 4, "H100", 4),
+				"high":   planDiff("A100", 2, "A100", 6),
+			},
+			limited: true,
+			want: []PlanStep{
+				{Index: 0, Action: ActionRelease, ServerName: "medium", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 4, ToReplicas: 0, Units: 4},
+				{Index: 1, Action: ActionScaleUp, ServerName: "high", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 2, ToReplicas: 6, Units: 4, DependsOn: []int{0}},
+				{Index: 2, Action: ActionScaleUp, ServerName: "medium", Accelerator: "H100", AcceleratorType: "GPU_H100",
+					FromReplicas: 0, ToReplicas: 4, Units: 4},
+			},
+		},
+		{
+			name:     "lower priority servers release first and higher priority servers acquire first",
+			capacity: map[string]int{"GPU_A100": 10},
+			diffs: map[string]*core.AllocationDiff{
+				"high":   planDiff("A100", 4, "A100", 2),
+				"medium": planDiff("", 0, "A100", 2),
+				"low":    planDiff("A100", 6, "", 0),
+			},
+			limited: true,
+			want: []PlanStep{
+				{Index: 0, Action: ActionRelease, ServerName: "low", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 6, ToReplicas: 0, Units: 6},
+				{Index: 1, Action: ActionScaleDown, ServerName: "high", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 4, ToReplicas: 2, Units: 2},
+				{Index: 2, Action: ActionScaleUp, ServerName: "medium", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 0, ToReplicas: 2, Units: 2, DependsOn: []int{0}},
+			},
+		},
+		{
+			name:     "scale up waits for scale down in the namespace under its quota",
+			capacity: map[string]int{"GPU_A100": 16},
+			quotas: []config.NamespaceQuota{
+				{Namespace: "ns1", Count: []config.AcceleratorCount{{Type: "GPU_A100", Count: 4}}},
+			},
+			diffs: map[string]*core.AllocationDiff{
+				"high":   planDiff("A100", 1, "A100", 3),
+				"medium": planDiff("A100", 3, "A100", 1),
+				"low":    planDiff("A100", 1, "A100", 3),
+			},
+			limited: true,
+			want: []PlanStep{
+				{Index: 0, Action: ActionScaleDown, ServerName: "medium", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 3, ToReplicas: 1, Units: 2},
+				{Index: 1, Action: ActionScaleUp, ServerName: "high", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 1, ToReplicas: 3, Units: 2, DependsOn: []int{0}},
+				{Index: 2, Action: ActionScaleUp, ServerName: "low", Accelerator: "A100", AcceleratorType: "GPU_A100",
+					FromReplicas: 1, ToReplicas: 3, Units: 2},
+			},
+		},
+		{
+			name:     "unchanged allocations have no steps",
+			capacity: map[string]int{"GPU_A100": 8},
+			diffs: map[string]*core.AllocationDiff{
+				"high": planDiff("A100", 2, "A100", 2),
+			},
+			limited: true,
+			want:    []PlanStep{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestSystemForPlan(tt.capacity, tt.quotas, servers...)
+			plan := CreatePlan(tt.diffs, tt.limited)
+			if !reflect.DeepEqual(plan.Steps, tt.want) {
+				t.Errorf("CreatePlan() = %v, want %v", plan, &Plan{Steps: tt.want})
+			}
+		})
+	}
+}
+
+func TestSolver_Plan(t *testing.T) {
+	setupTestSystemForQuota(map[string]int{"GPU_A100": 8}, nil,
+		quotaServer{name: "a", namespace: "ns1", numReplicas: 4, values: map[string]float32{"A100": 1}},
+		quotaServer{name: "b", namespace: "ns2", numReplicas: 4, values: map[string]float32{"A100": 1}},
+	)
+	solver := NewSolver(&config.OptimizerSpec{})
+	if solver.Plan() != nil {
+		t.Error("Expected no plan before solving")
+	}
+	if err := solver.Solve(); err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+
+	plan := solver.Plan()
+	if plan == nil || len(plan.Steps) != 2 {
+		t.Fatalf("Expected a scale up step for each server, got %v", plan)
+	}
+	for _, step := range plan.Steps {
+		if step.Action != ActionScaleUp || step.ToReplicas != 4 || len(step.DependsOn) != 0 {
+			t.Errorf("Expected scale up to 4 replicas without dependencies, got %v", &step)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package solver
+
+import (
+	"maps"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
+)
+
+// Accelerator units left to allocate: the units of each accelerator type available in the system, further limited
+// by the quotas of the namespaces of servers
+type capacityPool struct {
+	available map[string]int            // available units by accelerator type
+	quotas    map[string]map[string]int // units left in quotas by namespace and accelerator type
+}
+
+// Create a pool over maps of available units and quotas (nil for none), which are updated in place
+func newCapacityPool(available map[string]int, quotas map[string]map[string]int) *capacityPool {
+	if quotas == nil {
+		quotas = make(map[string]map[string]int)
+	}
+	return &capacityPool{
+		available: available,
+		quotas:    quotas,
+	}
+}
+
+// Create a pool from copies of the capacity and quotas of the system
+func newSystemCapacityPool() *capacityPool {
+	available := make(map[string]int)
+	maps.Copy(available, core.GetCapacities())
+	quotas := make(map[string]map[string]int)
+	for namespace, quota := range core.GetQuotas() {
+		quotas[namespace] = maps.Clone(quota)
+	}
+	return newCapacityPool(available, quotas)
+}
+
+// Copy of the pool
+func (p *capacityPool) clone() *capacityPool {
+	quotas := make(map[string]map[string]int)
+	for namespace, quota := range p.quotas {
+		quotas[namespace] = maps.Clone(quota)
+	}
+	return newCapacityPool(maps.Clone(p.available), quotas)
+}
+
+// Number of units of an accelerator type that may be allocated to a server in a namespace
+func (p *capacityPool) units(namespace, accType string) int {
+	units := p.available[accType]
+	if quota, limited := p.quotas[namespace][accType]; limited {
+		units = min(units, quota)
+	}
+	return units
+}
+
+// Check whether a number of units of an accelerator type may be allocated to a server in a namespace, returning
+// the reason if not: the quota of the namespace if it alone does not allow it, the capacity otherwise
+func (p *capacityPool) limit(namespace, accType string, count int) string {
+	if quota, limited := p.quotas[namespace][accType]; limited && quota < count {
+		return core.CappedByQuota
+	}
+	if p.available[accType] < count {
+		return core.CappedByCapacity
+	}
+	return ""
+}
+
+// Allocate a number of units of an accelerator type to a server in a namespace
+func (p *capacityPool) take(namespace, accType string, count int) {
+	p.available[accType] -= count
+	if _, limited := p.quotas[namespace][accType]; limited {
+		p.quotas[namespace][accType] -= count
+	}
+}
+
+// Release a number of units of an accelerator type allocated to a server in a namespace
+func (p *capacityPool) release(namespace, accType string, count int) {
+	p.take(namespace, accType, -count)
+}
+
+// Preferred allocation of a server, the one of least value, as candidate before solving
+type preference struct {
+	accelerator string
+	numReplicas int
+	accType     string
+	count       int // number of accelerator units
+}
+
+// Record the preferred allocation of all servers, before solving modifies candidate allocations
+func preferredAllocations() map[string]preference {
+	preferred := make(map[string]preference)
+	for serverName, server := range core.GetServers() {
+		model := core.GetModel(server.ModelName())
+		if model == nil {
+			continue
+		}
+		var best *core.Allocation
+		for _, alloc := range server.AllAllocations() {
+			if best == nil || alloc.Value() < best.Value() ||
+				(alloc.Value() == best.Value() && alloc.Accelerator() < best.Accelerator()) {
+				best = alloc
+			}
+		}
+		if best == nil {
+			continue
+		}
+		acc := core.GetAccelerator(best.Accelerator())
+		if acc == nil {
+			continue
+		}
+		preferred[serverName] = preference{
+			accelerator: best.Accelerator(),
+			numReplicas: best.NumReplicas(),
+			accType:     acc.Type(),
+			count:       best.NumReplicas() * model.NumInstances(best.Accelerator()) * acc.Spec().Multiplicity,
+		}
+	}
+	return preferred
+}
+
+// Record why servers were not allocated their preferred allocation: given the allocations of all other servers,
+// whether the quota of its namespace or the capacity of the system does not leave room for it. Servers for which room
+// was left, e.g., when not allocated it to keep a better solution, are not capped by either.
+func markCapped(preferred map[string]preference) {
+	pool := newSystemCapacityPool()
+	for _, server := range core.GetServers() {
+		if accType, count := allocatedUnits(server); count > 0 {
+			pool.take(server.Namespace(), accType, count)
+		}
+	}
+	for serverName, server := range core.GetServers() {
+		server.SetCappedBy("")
+		pref, exists := preferred[serverName]
+		if !exists {
+			continue
+		}
+		alloc := server.Allocation()
+		if alloc != nil && alloc.Accelerator() == pref.accelerator && alloc.NumReplicas() >= pref.numReplicas {
+			continue
+		}
+		accType, count := allocatedUnits(server)
+		pool.release(server.Namespace(), accType, count)
+		reason := pool.limit(server.Namespace(), pref.accType, pref.count)
+		pool.take(server.Namespace(), accType, count)
+		server.SetCappedBy(reason)
+	}
+}
+
+// Accelerator type and number of units of the allocation of a server
+func allocatedUnits(server *core.Server) (accType string, count int) {
+	alloc := server.Allocation()
+	if alloc == nil {
+		return "", 0
+	}
+	return replicaUnits(server, alloc.Accelerator(), alloc.NumReplicas())
+}
+
+// Accelerator type and number of units of a number of replicas of a server on an accelerator
+func replicaUnits(server *core.Server, accName string, numReplicas int) (accType string, count int) {
+	acc := core.GetAccelerator(accName)
+	model := core.GetModel(server.ModelName())
+	if acc == nil || model == nil {
+		return "", 0
+	}
+	return acc.Type(), numReplicas * model.NumInstances(accName) * acc.Spec().Multiplicity
+}
+
+// Reasons servers were not allocated their preferred allocation in the last solution, by server name
+func (s *Solver) Capped() map[string]string {
+	capped := make(map[string]string)
+	for serverName, server := range core.GetServers() {
+		if reason := server.CappedBy(); reason != "" {
+			capped[serverName] = reason
+		}
+	}
+	return capped
+}
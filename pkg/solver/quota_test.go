@@ -0,0 +1,280 @@
+package solver
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/config"
+	"github.com/llm-d-incubation/workload-variant-autoscaler/pkg/core"
+)
+
+// Helper function to create a system of servers with candidate allocations of one unit per replica, of the given
+// value by accelerator, under the given capacity and quotas
+func setupTestSystemForQuota(capacity map[string]int, quotas []config.NamespaceQuota, servers ...quotaServer) {
+	setupTestSystemForGreedy()
+	for _, name := range []string{"server1", "server2", "server3"} {
+		_ = core.TheSystem.RemoveServer(name)
+	}
+	for accType, count := range capacity {
+		core.TheSystem.SetCountFromSpec(config.AcceleratorCount{Type: accType, Count: count})
+	}
+	core.TheSystem.SetQuotasFromSpec(&config.QuotaData{Spec: quotas})
+	for _, qs := range servers {
+		core.TheSystem.AddServerFromSpec(config.ServerSpec{
+			Name:      qs.name,
+			Namespace: qs.namespace,
+			Class:     "high-priority",
+			Model:     "llama-7b",
+		})
+		server := core.GetServer(qs.name)
+		for accName, value := range qs.values {
+			alloc := core.AllocationFromData(&config.AllocationData{
+				Accelerator: accName,
+				NumReplicas: qs.numReplicas,
+				Cost:        value,
+			})
+			alloc.SetValue(value)
+			server.AllAllocations()[accName] = alloc
+		}
+	}
+}
+
+type quotaServer struct {
+	name        string
+	namespace   string
+	numReplicas int
+	values      map[string]float32 // value of candidate allocation by accelerator
+}
+
+func TestCapacityPool(t *testing.T) {
+	pool := newCapacityPool(map[string]int{"A": 8, "B": 2}, map[string]map[string]int{"ns1": {"A": 3}})
+
+	if got := pool.units("ns1", "A"); got != 3 {
+		t.Errorf("units(ns1, A) = %d, want 3", got)
+	}
+	if got := pool.units("ns2", "A"); got != 8 {
+		t.Errorf("units(ns2, A) = %d, want 8", got)
+	}
+	if got := pool.units("ns1", "B"); got != 2 {
+		t.Errorf("units(ns1, B) = %d, want 2, since B is not limited by the quota", got)
+	}
+	if got := pool.limit("ns1", "A", 4); got != core.CappedByQuota {
+		t.Errorf("limit(ns1, A, 4) = %q, want %q", got, core.CappedByQuota)
+	}
+	if got := pool.limit("ns1", "B", 3); got != core.CappedByCapacity {
+		t.Errorf("limit(ns1, B, 3) = %q, want %q", got, core.CappedByCapacity)
+	}
+
+	clone := pool.clone()
+	pool.take("ns1", "A", 3)
+	if pool.available["A"] != 5 || pool.quotas["ns1"]["A"] != 0 {
+		t.Errorf("after take: available %v, quotas %v", pool.available, pool.quotas)
+	}
+	if clone.available["A"] != 8 || clone.quotas["ns1"]["A"] != 3 {
+		t.Errorf("clone should not share maps with the pool: available %v, quotas %v", clone.available, clone.quotas)
+	}
+	pool.release("ns1", "A", 3)
+	if pool.available["A"] != 8 || pool.quotas["ns1"]["A"] != 3 {
+		t.Errorf("after release: available %v, quotas %v", pool.available, pool.quotas)
+	}
+}
+
+func TestSolver_Quotas(t *testing.T) {
+	tests := []struct {
+		name         string
+		capacity     map[string]int
+		quotas       []config.NamespaceQuota
+		servers      []quotaServer
+		policy       string
+		wantAlloc    map[string]string // allocated accelerator by server, empty for none
+		wantReplicas map[string]int
+		wantCapped   map[string]string
+	}{
+		{
+			name:     "no quota",
+			capacity: map[string]int{"GPU_A100": 8, "GPU_H100": 8},
+			servers: []quotaServer{
+				{name: "a", namespace: "ns1", numReplicas: 4, values: map[string]float32{"A100": 1, "H100": 2}},
+				{name: "b", namespace: "ns2", numReplicas: 4, values: map[string]float32{"A100": 1, "H100": 2}},
+			},
+			policy:       "None",
+			wantAlloc:    map[string]string{"a": "A100", "b": "A100"},
+			wantReplicas: map[string]int{"a": 4, "b": 4},
+			wantCapped:   map[string]string{},
+		},
+		{
+			name:     "quota moves server to another accelerator",
+			capacity: map[string]int{"GPU_A100": 8, "GPU_H100": 8},
+			quotas: []config.NamespaceQuota{
+				{Namespace: "ns1", Count: []config.AcceleratorCount{{Type: "GPU_A100", Count: 2}}},
+			},
+			servers: []quotaServer{
+				{name: "a", namespace: "ns1", numReplicas: 4, values: map[string]float32{"A100": 1, "H100": 2}},
+				{name: "b", namespace: "ns2", numReplicas: 4, values: map[string]float32{"A100": 1, "H100": 2}},
+			},
+			policy:       "None",
+			wantAlloc:    map[string]string{"a": "H100", "b": "A100"},
+			wantReplicas: map[string]int{"a": 4, "b": 4},
+			wantCapped:   map[string]string{"a": core.CappedByQuota},
+		},
+		{
+			name:     "capacity moves server to another accelerator",
+			capacity: map[string]int{"GPU_A100": 4, "GPU_H100": 8},
+			servers: []quotaServer{
+				{name: "a", namespace: "ns1", numReplicas: 4, values: map[string]float32{"A100": 1, "H100": 3}},
+				{name: "b", namespace: "ns2", numReplicas: 4, values: map[string]float32{"A100": 1, "H100": 2}},
+			},
+			policy:       "None",
+			wantAlloc:    map[string]string{"a": "A100", "b": "H100"},
+			wantReplicas: map[string]int{"a": 4, "b": 4},
+			wantCapped:   map[string]string{"b": core.CappedByCapacity},
+		},
+		{
+			name:     "quota left unallocated",
+			capacity: map[string]int{"GPU_A100": 8},
+			quotas: []config.NamespaceQuota{
+				{Namespace: "ns1", Count: []config.AcceleratorCount{{Type: "GPU_A100", Count: 3}}},
+			},
+			servers: []quotaServer{
+				{name: "a", namespace: "ns1", numReplicas: 4, values: map[string]float32{"A100": 1}},
+			},
+			policy:       "None",
+			wantAlloc:    map[string]string{"a": ""},
+			wantCapped:   map[string]string{"a": core.CappedByQuota},
+			wantReplicas: map[string]int{},
+		},
+		{
+			name:     "best effort within quota",
+			capacity: map[string]int{"GPU_A100": 8},
+			quotas: []config.NamespaceQuota{
+				{Namespace: "ns1", Count: []config.AcceleratorCount{{Type: "GPU_A100", Count: 3}}},
+			},
+			servers: []quotaServer{
+				{name: "a", namespace: "ns1", numReplicas: 4, values: map[string]float32{"A100": 1}},
+				{name: "b", namespace: "ns2", numReplicas: 4, values: map[string]float32{"A100": 1}},
+			},
+			policy:       "PriorityExhaustive",
+			wantAlloc:    map[string]string{"a": "A100", "b": "A100"},
+			wantReplicas: map[string]int{"a": 3, "b": 4},
+			wantCapped:   map[string]string{"a": core.CappedByQuota},
+		},
+		{
+			name:     "round robin within quota",
+			capacity: map[string]int{"GPU_A100": 8},
+			quotas: []config.NamespaceQuota{
+				{Namespace: "ns1", Count: []config.AcceleratorCount{{Type: "GPU_A100", Count: 1}}},
+			},
+			servers: []quotaServer{
+				{name: "a", namespace: "ns1", numReplicas: 4, values: map[string]float32{"A100": 1}},
+				{name: "b", namespace: "ns1", numReplicas: 4, values: map[string]float32{"A100": 1}},
+			},
+			policy:       "RoundRobin",
+			wantReplicas: map[string]int{},
+			wantCapped:   map[string]string{"a": core.CappedByQuota, "b": core.CappedByQuota},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, mip := range []bool{false, true} {
+			name := tt.name
+			if mip {
+				name += " (MIP)"
+			}
+			t.Run(name, func(t *testing.T) {
+				setupTestSystemForQuota(tt.capacity, tt.quotas, tt.servers...)
+				solver := NewSolver(&config.OptimizerSpec{SaturationPolicy: tt.policy, MIP: mip})
+				if err := solver.Solve(); err != nil {
+					t.Fatalf("Solve() error = %v", err)
+				}
+				for serverName, want := range tt.wantAlloc {
+					got := ""
+					if alloc := core.GetServer(serverName).Allocation(); alloc != nil {
+						got = alloc.Accelerator()
+					}
+					if got != want {
+						t.Errorf("server %s: accelerator = %q, want %q", serverName, got, want)
+					}
+				}
+				for serverName, want := range tt.wantReplicas {
+					if got := allocatedReplicas(t, serverName); got != want {
+						t.Errorf("server %s: replicas = %d, want %d", serverName, got, want)
+					}
+				}
+				capped := solver.Capped()
+				if len(capped) != len(tt.wantCapped) {
+					t.Errorf("capped = %v, want %v", capped, tt.wantCapped)
+				}
+				for serverName, want := range tt.wantCapped {
+					if capped[serverName] != want {
+						t.Errorf("server %s: capped by %q, want %q", serverName, capped[serverName], want)
+					}
+				}
+
+				// allocations fit quotas
+				used := make(map[string]map[string]int)
+				for _, server := range core.GetServers() {
+					if accType, count := allocatedUnits(server); count > 0 {
+						if used[server.Namespace()] == nil {
+							used[server.Namespace()] = make(map[string]int)
+						}
+						used[server.Namespace()][accType] += count
+					}
+				}
+				for _, q := range tt.quotas {
+					for _, c := range q.Count {
+						if used[q.Namespace][c.Type] > c.Count {
+							t.Errorf("namespace %s: allocated %d units of %s, quota %d",
+								q.Namespace, used[q.Namespace][c.Type], c.Type, c.Count)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestMarkCapped_RoomLeft(t *testing.T) {
+	setupTestSystemForQuota(map[string]int{"GPU_A100": 8, "GPU_H100": 8}, nil,
+		quotaServer{name: "a", namespace: "ns1", numReplicas: 4, values: map[string]float32{"A100": 1, "H100": 2}})
+	server := core.GetServer("a")
+	preferred := preferredAllocations()
+
+	// allocated another accelerator although the preferred one has room, e.g., to keep a better solution
+	server.SetAllocation(server.AllAllocations()["H100"])
+	markCapped(preferred)
+	if got := server.CappedBy(); got != "" {
+		t.Errorf("capped by %q, want none with room for the preferred allocation", got)
+	}
+
+	core.TheSystem.SetCountFromSpec(config.AcceleratorCount{Type: "GPU_A100", Count: 2})
+	markCapped(preferred)
+	if got := server.CappedBy(); got != core.CappedByCapacity {
+		t.Errorf("capped by %q, want %q", got, core.CappedByCapacity)
+	}
+}
+
+func TestMIPProblem_SolveMatchesBruteForceWithQuotas(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	namespaces := []string{"ns1", "ns2"}
+	for trial := 0; trial < 50; trial++ {
+		p := randomMIPProblem(rng, 1+rng.Intn(6))
+		for _, s := range p.servers {
+			s.namespace = namespaces[rng.Intn(len(namespaces))]
+		}
+		p.quotas = map[string]map[string]int{
+			"ns1": {"A": rng.Intn(4)},
+			"ns2": {"B": rng.Intn(4), "C": rng.Intn(4)},
+		}
+		best, bestObj, _, optimal := p.solve(noneChoice(len(p.servers)), time.Now().Add(time.Minute))
+		if !optimal {
+			t.Fatalf("trial %d: search did not complete", trial)
+		}
+		if !p.feasible(best) {
+			t.Fatalf("trial %d: infeasible solution %v", trial, best)
+		}
+		if want := bruteForceMIP(p); bestObj != want {
+			t.Errorf("trial %d: objective = %v, want %v", trial, bestObj, want)
+		}
+	}
+}
@@ -18,6 +18,12 @@ type Solver struct {
 
 	// difference in allocation for all servers
 	diffAllocation map[string]*core.AllocationDiff
+
+	// statistics of the last MIP solution
+	mipStats *MIPStats
+
+	// ordered steps moving servers from their current to their desired allocations
+	plan *Plan
 }
 
 func NewSolver(optimizerSpec *config.OptimizerSpec) *Solver {
@@ -39,13 +45,25 @@ func (s *Solver) Solve() error {
 	}
 
 	// find solution
+	s.mipStats = nil
 	if s.optimizerSpec.Unlimited {
 		s.SolveUnlimited()
+		for _, server := range core.GetServers() {
+			server.SetCappedBy("")
+		}
 	} else {
-		s.SolveGreedy()
+		preferred := preferredAllocations()
+		if s.optimizerSpec.MIP {
+			s.SolveMIP()
+		} else {
+			s.SolveGreedy()
+		}
+		// report servers capped by capacity or quota
+		markCapped(preferred)
 	}
 
-	// TODO: cleanup after trying MIP solver
+	// reduce allocations exceeding the cost budget
+	s.EnforceBudget()
 
 	s.diffAllocation = make(map[string]*core.AllocationDiff)
 	for serverName, server := range core.GetServers() {
@@ -55,6 +73,7 @@ func (s *Solver) Solve() error {
 			s.diffAllocation[serverName] = allocDiff
 		}
 	}
+	s.plan = CreatePlan(s.diffAllocation, !s.optimizerSpec.Unlimited)
 	return nil
 }
 
@@ -82,6 +101,11 @@ func (s *Solver) AllocationDiff() map[string]*core.AllocationDiff {
 	return s.diffAllocation
 }
 
+// Plan of the last solution; nil before solving
+func (s *Solver) Plan() *Plan {
+	return s.plan
+}
+
 func (s *Solver) String() string {
 	var b bytes.Buffer
 	b.WriteString("Solver: \n")
@@ -89,5 +113,14 @@ func (s *Solver) String() string {
 		fmt.Fprintf(&b, "sName=%s, allocDiff=%v \n",
 			serverName, allocDiff)
 	}
+	if s.mipStats != nil {
+		fmt.Fprintf(&b, "%v \n", s.mipStats)
+	}
+	for serverName, reason := range s.Capped() {
+		fmt.Fprintf(&b, "sName=%s, cappedBy=%s \n", serverName, reason)
+	}
+	if s.plan != nil && len(s.plan.Steps) > 0 {
+		b.WriteString(s.plan.String())
+	}
 	return b.String()
 }
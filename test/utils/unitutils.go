@@ -136,8 +136,9 @@ func CreateVariantAutoscalingConfigMap(cmName, controllerNamespace string) *core
 
 // MockPromAPI is a mock implementation of promv1.API for testing
 type MockPromAPI struct {
-	QueryResults map[string]model.Value
-	QueryErrors  map[string]error
+	QueryResults      map[string]model.Value
+	QueryErrors       map[string]error
+	QueryRangeResults map[string]model.Value
 }
 
 func (m *MockPromAPI) Query(ctx context.Context, query string, ts time.Time, opts ...promv1.Option) (model.Value, promv1.Warnings, error) {
@@ -159,6 +160,12 @@ func (m *MockPromAPI) Query(ctx context.Context, query string, ts time.Time, opt
 }
 
 func (m *MockPromAPI) QueryRange(ctx context.Context, query string, r promv1.Range, opts ...promv1.Option) (model.Value, promv1.Warnings, error) {
+	if err, exists := m.QueryErrors[query]; exists {
+		return nil, nil, err
+	}
+	if val, exists := m.QueryRangeResults[query]; exists {
+		return val, nil, nil
+	}
 	return nil, nil, nil
 }
 